@@ -0,0 +1,364 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"log"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/callgraph/static"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Analyzer reports problems that would prevent the coroutines of a package
+// from being compiled to their durable form, without having to run the
+// compiler. It can be used with go vet, gopls, or any other driver of the
+// golang.org/x/tools/go/analysis framework.
+//
+// The analyzer runs the same checks as the compiler (function coloring,
+// directives and unsupported language features), and additionally reports
+// variables of colored functions which are captured in the coroutine frame
+// but hold functions that cannot be serialized because they aren't
+// registered, like method values or the closures returned by the standard
+// library.
+//
+// Since packages are analyzed separately, the analyzer builds the callgraph
+// using static calls only, and propagates colors across packages with facts.
+// Functions that the compiler would color because of dynamic calls are not
+// reported; the //coroutine:yields directive can be used to make them visible
+// to the analyzer.
+var Analyzer = &analysis.Analyzer{
+	Name:      "coroutine",
+	Doc:       "report problems preventing coroutines from being compiled to durable form",
+	Requires:  []*analysis.Analyzer{buildssa.Analyzer},
+	FactTypes: []analysis.Fact{new(yieldsFact), new(directiveFact)},
+	Run:       runAnalyzer,
+}
+
+// yieldsFact is exported for functions that were colored, so that callers in
+// other packages are colored as well.
+type yieldsFact struct {
+	// Color is the string representation of the function color.
+	Color string
+}
+
+func (*yieldsFact) AFact() {}
+
+func (f *yieldsFact) String() string { return "yields " + f.Color }
+
+// directiveFact is exported for functions and interface methods with a
+// coloring directive, so that they apply to other packages.
+type directiveFact struct {
+	NoYield bool
+	Yields  bool
+}
+
+func (*directiveFact) AFact() {}
+
+func (f *directiveFact) String() string {
+	if f.NoYield {
+		return noYieldDirective
+	}
+	return yieldsDirective
+}
+
+func runAnalyzer(pass *analysis.Pass) (any, error) {
+	if pass.Pkg.Path() == coroutinePackage {
+		return nil, nil
+	}
+	ssainput := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+
+	p := &packages.Package{
+		Name:      pass.Pkg.Name(),
+		PkgPath:   pass.Pkg.Path(),
+		Fset:      pass.Fset,
+		Syntax:    pass.Files,
+		Types:     pass.Pkg,
+		TypesInfo: pass.TypesInfo,
+	}
+
+	c := &compiler{
+		callgraphType: "static",
+		prog:          ssainput.Pkg.Prog,
+		fset:          pass.Fset,
+		directives:    newDirectives(),
+		logger:        log.New(io.Discard, "", 0),
+		output:        io.Discard,
+	}
+
+	if err := c.directives.parsePackage(p); err != nil {
+		reportError(pass, err)
+		return nil, nil
+	}
+	for obj := range c.directives.noyield {
+		pass.ExportObjectFact(obj, &directiveFact{NoYield: true})
+	}
+	for obj := range c.directives.yields {
+		pass.ExportObjectFact(obj, &directiveFact{Yields: true})
+	}
+	for _, f := range pass.AllObjectFacts() {
+		fact, ok := f.Fact.(*directiveFact)
+		if !ok || f.Object.Pkg() == pass.Pkg {
+			continue
+		}
+		if obj, ok := f.Object.(*types.Func); ok {
+			if fact.NoYield {
+				c.directives.noyield[obj] = struct{}{}
+			}
+			if fact.Yields {
+				c.directives.yields[obj] = struct{}{}
+			}
+		}
+	}
+
+	// Colors of functions in other packages are only known by their string
+	// representation; intern them so that identical colors are represented
+	// by the same signature.
+	colorsByString := map[string]*types.Signature{}
+	stringsByColor := map[*types.Signature]string{}
+	internColor := func(color string, signature *types.Signature) *types.Signature {
+		if existing, ok := colorsByString[color]; ok {
+			return existing
+		}
+		if signature == nil {
+			signature = placeholderColor(color)
+		}
+		colorsByString[color] = signature
+		stringsByColor[signature] = color
+		return signature
+	}
+
+	cg := static.CallGraph(c.prog)
+	yieldInstances := functionColors{}
+	for fn := range cg.Nodes {
+		if fn == nil {
+			continue
+		}
+		if isYieldInstance(fn) {
+			yieldInstances[fn] = internColor(types.TypeString(fn.Signature, nil), fn.Signature)
+			continue
+		}
+		if obj := funcObject(fn); obj != nil && obj.Pkg() != pass.Pkg {
+			var fact yieldsFact
+			if pass.ImportObjectFact(obj, &fact) {
+				yieldInstances[fn] = internColor(fact.Color, nil)
+			}
+		}
+	}
+	if len(yieldInstances) == 0 && len(c.directives.yields) == 0 {
+		return nil, nil
+	}
+
+	colors, err := c.colorFunctions(cg, ssautil.AllFunctions(c.prog), yieldInstances)
+	if err != nil {
+		reportError(pass, err)
+		return nil, nil
+	}
+
+	localColors := functionColors{}
+	for fn, color := range colors {
+		if fn.Pkg != ssainput.Pkg {
+			continue
+		}
+		localColors[fn] = color
+		if fn.Origin() != nil || fn.TypeParams().Len() > 0 {
+			continue
+		}
+		if obj := funcObject(fn); obj != nil && obj.Pkg() == pass.Pkg {
+			pass.ExportObjectFact(obj, &yieldsFact{Color: stringsByColor[color]})
+		}
+	}
+	colorsByFunc := colorsByFuncOf(localColors)
+
+	for _, f := range pass.Files {
+		for _, anydecl := range f.Decls {
+			decl, ok := anydecl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			if colorsByFunc[decl] != nil || containsColoredFuncLit(decl, colorsByFunc) {
+				for _, diag := range c.unsupported(p, decl, colorsByFunc) {
+					reportError(pass, diag)
+				}
+			}
+		}
+	}
+
+	for node := range colorsByFunc {
+		checkFrameVariables(pass, node, colorsByFunc)
+	}
+	return nil, nil
+}
+
+func isYieldInstance(fn *ssa.Function) bool {
+	origin := fn.Origin()
+	if origin == nil || origin.Pkg == nil {
+		return false
+	}
+	return origin.Pkg.Pkg.Path() == coroutinePackage && origin.Name() == "Yield"
+}
+
+// placeholderColor creates a signature which stands for a color that is only
+// known by its string representation.
+func placeholderColor(color string) *types.Signature {
+	obj := types.NewTypeName(token.NoPos, nil, color, nil)
+	named := types.NewNamed(obj, types.NewStruct(nil, nil), nil)
+	return types.NewSignatureType(nil, nil, nil,
+		types.NewTuple(types.NewParam(token.NoPos, nil, "v", named)),
+		types.NewTuple(types.NewParam(token.NoPos, nil, "", types.NewInterfaceType(nil, nil))),
+		false)
+}
+
+// checkFrameVariables reports variables of a colored function which are
+// stored in the coroutine frame but cannot be serialized.
+//
+// Values of all types can be serialized, except for functions which are not
+// registered with the types package, so the analyzer looks for the function
+// values that are assigned to variables.
+func checkFrameVariables(pass *analysis.Pass, fn ast.Node, colorsByFunc map[ast.Node]*types.Signature) {
+	ast.Inspect(fn, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			// Variables of nested function literals are stored in the
+			// frame of the enclosing function if they are captured, and
+			// in their own frame if they are colored (checked separately).
+			return n == fn
+		case *ast.AssignStmt:
+			checkAssignedFuncs(pass, n.Lhs, n.Rhs)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(n.Names))
+			for i, name := range n.Names {
+				lhs[i] = name
+			}
+			checkAssignedFuncs(pass, lhs, n.Values)
+		}
+		return true
+	})
+}
+
+// checkAssignedFuncs reports the variables of lhs which are assigned function
+// values that are not registered. The values are either paired with the
+// variables, or the results of a single call.
+func checkAssignedFuncs(pass *analysis.Pass, lhs, rhs []ast.Expr) {
+	for i, expr := range lhs {
+		ident, ok := expr.(*ast.Ident)
+		if !ok || ident.Name == "_" {
+			continue
+		}
+		var value ast.Expr
+		var result int
+		switch {
+		case len(lhs) == len(rhs):
+			value = rhs[i]
+		case len(rhs) == 1:
+			value, result = rhs[0], i
+		default:
+			continue
+		}
+		if reason := unregisteredFunc(pass, value, result); reason != "" {
+			pass.Reportf(value.Pos(), "variable %s of a coroutine cannot be serialized: %s", ident.Name, reason)
+		}
+	}
+}
+
+// unregisteredFunc returns a description of the reason why the function value
+// produced by expr would not be registered with the types package, or an
+// empty string if the value is not a function or is expected to be
+// registered. When expr is a call, result is the index of the value in its
+// results.
+//
+// The compiler registers the types of functions and closures declared in the
+// packages that it compiles. Method values, functions of the standard
+// library, and the closures that they return are not registered.
+func unregisteredFunc(pass *analysis.Pass, expr ast.Expr, result int) string {
+	expr = ast.Unparen(expr)
+	t := pass.TypesInfo.TypeOf(expr)
+	if tuple, ok := t.(*types.Tuple); ok {
+		if result >= tuple.Len() {
+			return ""
+		}
+		t = tuple.At(result).Type()
+	}
+	if t == nil {
+		return ""
+	}
+	if _, ok := t.Underlying().(*types.Signature); !ok {
+		return ""
+	}
+	var ident *ast.Ident
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		fn := typeutil.StaticCallee(pass.TypesInfo, e)
+		if fn != nil && fn.Pkg() != nil && isStandardLibrary(fn.Pkg().Path()) {
+			return "function returned by " + fn.FullName() + " is not registered"
+		}
+		return ""
+	case *ast.SelectorExpr:
+		if sel, ok := pass.TypesInfo.Selections[e]; ok && sel.Kind() == types.MethodVal {
+			return "method value " + e.Sel.Name + " is not registered"
+		}
+		ident = e.Sel
+	case *ast.Ident:
+		ident = e
+	default:
+		return ""
+	}
+	fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return ""
+	}
+	if isStandardLibrary(fn.Pkg().Path()) {
+		return "function " + fn.FullName() + " is not registered"
+	}
+	return ""
+}
+
+func isStandardLibrary(pkgPath string) bool {
+	elem, _, _ := strings.Cut(pkgPath, "/")
+	return !strings.Contains(elem, ".")
+}
+
+// reportError reports an error as a diagnostic of the analysis pass, using
+// the position of *Diagnostic errors when available.
+func reportError(pass *analysis.Pass, err error) {
+	diags, ok := err.(Diagnostics)
+	if !ok {
+		if diag, ok := err.(*Diagnostic); ok {
+			diags = Diagnostics{diag}
+		}
+	}
+	if diags == nil {
+		pos := token.NoPos
+		if len(pass.Files) > 0 {
+			pos = pass.Files[0].Package
+		}
+		pass.Reportf(pos, "%s", err)
+		return
+	}
+	for _, diag := range diags {
+		pass.Report(analysis.Diagnostic{
+			Pos:     positionToPos(pass, diag.Pos),
+			Message: diag.Message,
+		})
+	}
+}
+
+func positionToPos(pass *analysis.Pass, position token.Position) token.Pos {
+	for _, f := range pass.Files {
+		file := pass.Fset.File(f.Pos())
+		if file != nil && file.Name() == position.Filename && position.Offset <= file.Size() {
+			return file.Pos(position.Offset)
+		}
+	}
+	if len(pass.Files) > 0 {
+		return pass.Files[0].Package
+	}
+	return token.NoPos
+}
@@ -0,0 +1,13 @@
+package compiler
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata := filepath.Join(analysistest.TestData(), "analyzer")
+	analysistest.Run(t, testdata, Analyzer, "a", "b", "c")
+}
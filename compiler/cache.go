@@ -0,0 +1,421 @@
+package compiler
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/go/gcexportdata"
+	"golang.org/x/tools/go/packages"
+)
+
+// The compiler caches its output in a content-addressed store, at two levels:
+//
+//   - The result of compiling a whole module is keyed on the content of all
+//     the source files of the module and its dependencies. When none of them
+//     changed, the compiler doesn't have to type-check packages, build the
+//     SSA program or the callgraph.
+//
+//   - The files generated for a package are keyed on the content of its
+//     source files, the export data of the packages it imports, the colors of
+//     its functions and the instances of its generic functions. Coloring is
+//     performed on the whole program, so an edit in a package may change the
+//     colors of functions in another one, which then gets recompiled.
+//
+// Source files are hashed in the form that the compiler rewrites them to,
+// without the build tag it adds to their constraint, so that compiling a
+// module doesn't invalidate its keys.
+//
+// Keys also include the identity of the compiler binary, so that upgrading
+// the compiler invalidates the cache.
+//
+// Entries map keys to the list of files of a result, and the content of the
+// files is stored separately, addressed by its hash.
+const cacheVersion = 2
+
+const (
+	// Entries which haven't been used for this amount of time are removed
+	// from the cache.
+	cacheTrimAge = 5 * 24 * time.Hour
+	// The cache is trimmed at most once per interval.
+	cacheTrimInterval = 24 * time.Hour
+)
+
+type cacheKey [sha256.Size]byte
+
+func (k cacheKey) String() string { return hex.EncodeToString(k[:]) }
+
+// cacheKeyHash computes cache keys. Values are length-prefixed so that
+// different sequences of values cannot produce the same key.
+type cacheKeyHash struct{ h hash.Hash }
+
+func newCacheKeyHash(kind string) *cacheKeyHash {
+	h := &cacheKeyHash{h: sha256.New()}
+	h.int(cacheVersion)
+	h.string(kind)
+	h.bytes(compilerID())
+	return h
+}
+
+func (h *cacheKeyHash) int(v int) {
+	h.h.Write(binary.AppendUvarint(nil, uint64(v)))
+}
+
+func (h *cacheKeyHash) bytes(b []byte) {
+	h.int(len(b))
+	h.h.Write(b)
+}
+
+func (h *cacheKeyHash) string(s string) {
+	h.bytes([]byte(s))
+}
+
+func (h *cacheKeyHash) strings(s []string) {
+	h.int(len(s))
+	for _, v := range s {
+		h.string(v)
+	}
+}
+
+func (h *cacheKeyHash) sum() (k cacheKey) {
+	h.h.Sum(k[:0])
+	return
+}
+
+var compilerID = sync.OnceValue(func() []byte {
+	// The executable may not be found on some platforms, in which case
+	// the key only depends on the cache version.
+	exe, err := os.Executable()
+	if err != nil {
+		return nil
+	}
+	f, err := os.Open(exe)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil
+	}
+	return h.Sum(nil)
+})
+
+// cache is an on-disk compilation cache.
+type cache struct {
+	dir string
+
+	mutex  sync.Mutex
+	hashes map[string][sha256.Size]byte
+}
+
+func newCache(dir string) *cache {
+	return &cache{dir: dir, hashes: map[string][sha256.Size]byte{}}
+}
+
+type cacheEntry struct {
+	Files     []cacheFile      `json:"files"`
+	Functions []FunctionReport `json:"functions,omitempty"`
+	GOROOT    string           `json:"goroot,omitempty"`
+}
+
+type cacheFile struct {
+	Path string `json:"path"`
+	Hash string `json:"hash"`
+}
+
+func (c *cache) path(name string) string {
+	return filepath.Join(c.dir, name[:2], name)
+}
+
+// get returns the result stored for a key.
+func (c *cache) get(key cacheKey) (*Result, bool) {
+	entryPath := c.path(key.String() + "-e")
+	b, err := os.ReadFile(entryPath)
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return nil, false
+	}
+	res := &Result{Functions: entry.Functions, GOROOT: entry.GOROOT}
+	for _, f := range entry.Files {
+		dataPath := c.path(f.Hash + "-d")
+		content, err := os.ReadFile(dataPath)
+		if err != nil {
+			return nil, false
+		}
+		if sum := sha256.Sum256(content); hex.EncodeToString(sum[:]) != f.Hash {
+			return nil, false
+		}
+		c.touch(dataPath)
+		res.Files = append(res.Files, File{Path: f.Path, Content: content})
+	}
+	c.touch(entryPath)
+	return res, true
+}
+
+// put stores the files of a result for a key. Results with diagnostics are
+// not cached.
+func (c *cache) put(key cacheKey, res *Result) error {
+	entry := cacheEntry{Functions: res.Functions, GOROOT: res.GOROOT}
+	for _, f := range res.Files {
+		sum := sha256.Sum256(f.Content)
+		hash := hex.EncodeToString(sum[:])
+		if err := c.write(c.path(hash+"-d"), f.Content); err != nil {
+			return err
+		}
+		entry.Files = append(entry.Files, cacheFile{Path: f.Path, Hash: hash})
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return c.write(c.path(key.String()+"-e"), b)
+}
+
+// write atomically writes a file of the cache, so that concurrent runs of the
+// compiler never observe partially written files.
+func (c *cache) write(path string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// touch updates the modification time of a file which was used, unless it was
+// recently updated, so that trim keeps it in the cache.
+func (c *cache) touch(path string) {
+	now := time.Now()
+	if info, err := os.Stat(path); err == nil && now.Sub(info.ModTime()) > time.Hour {
+		os.Chtimes(path, now, now)
+	}
+}
+
+// trim removes the files of the cache which haven't been used recently.
+func (c *cache) trim() error {
+	now := time.Now()
+	trimPath := filepath.Join(c.dir, "trim.txt")
+	if info, err := os.Stat(trimPath); err == nil && now.Sub(info.ModTime()) < cacheTrimInterval {
+		return nil
+	}
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path == trimPath {
+			return err
+		}
+		if info, err := d.Info(); err == nil && now.Sub(info.ModTime()) > cacheTrimAge {
+			os.Remove(path)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return c.write(trimPath, []byte(now.Format(time.RFC3339)))
+}
+
+// fileHash returns the hash of the content of a source file, transformed by
+// normalize.
+func (c *cache) fileHash(path string, normalize func([]byte) []byte) ([sha256.Size]byte, error) {
+	c.mutex.Lock()
+	sum, ok := c.hashes[path]
+	c.mutex.Unlock()
+	if ok {
+		return sum, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return sum, err
+	}
+	sum = sha256.Sum256(normalize(b))
+	c.mutex.Lock()
+	c.hashes[path] = sum
+	c.mutex.Unlock()
+	return sum, nil
+}
+
+// moduleKey computes the cache key of the result of compiling the packages
+// matched by a pattern for a set of build configurations. Only the list of
+// files of the packages and their dependencies is loaded, which is much
+// faster than type-checking them.
+func (c *compiler) moduleKey(absPath, pattern string, configs []buildConfig) (cacheKey, error) {
+	h := newCacheKeyHash("module")
+	h.string(absPath)
+	h.string(pattern)
+	h.string(c.callgraphType)
+	h.strings(c.buildTags)
+	h.string(c.buildTag)
+	h.string(c.fileSuffix)
+	h.int(len(configs))
+
+	for _, config := range configs {
+		h.string(config.String())
+
+		conf := &packages.Config{
+			Mode: packages.NeedName | packages.NeedModule |
+				packages.NeedImports | packages.NeedDeps | packages.NeedFiles,
+			Dir:        absPath,
+			Env:        config.env(),
+			BuildFlags: c.buildFlags(),
+		}
+		pkgs, err := packages.Load(conf, pattern)
+		if err != nil {
+			return cacheKey{}, err
+		}
+
+		var all []*packages.Package
+		packages.Visit(pkgs, nil, func(p *packages.Package) {
+			all = append(all, p)
+		})
+		slices.SortFunc(all, func(a, b *packages.Package) int {
+			return cmp.Compare(a.PkgPath, b.PkgPath)
+		})
+
+		h.int(len(all))
+		for _, p := range all {
+			if len(p.Errors) > 0 {
+				return cacheKey{}, p.Errors[0]
+			}
+			h.string(p.PkgPath)
+			h.string(moduleGoVersion(p))
+			if err := c.hashFiles(h, p.GoFiles); err != nil {
+				return cacheKey{}, err
+			}
+		}
+	}
+	return h.sum(), nil
+}
+
+// packageKey computes the cache key of the files generated for a package.
+func (c *compiler) packageKey(p *packages.Package, colors functionColors, config buildConfig) (cacheKey, error) {
+	h := newCacheKeyHash("package")
+	h.string(config.String())
+	h.strings(c.buildTags)
+	h.string(c.buildTag)
+	h.string(c.fileSuffix)
+	h.string(p.PkgPath)
+	h.string(p.Name)
+	h.string(moduleGoVersion(p))
+
+	// The output paths differ from the source paths of vendored GOROOT
+	// packages.
+	h.strings(p.GoFiles)
+	sources := make([]string, len(p.Syntax))
+	for i, f := range p.Syntax {
+		sources[i] = c.fset.Position(f.Package).Filename
+	}
+	if err := c.hashFiles(h, sources); err != nil {
+		return cacheKey{}, err
+	}
+
+	imports := make([]string, 0, len(p.Imports))
+	for path := range p.Imports {
+		if path != "unsafe" && path != "C" {
+			imports = append(imports, path)
+		}
+	}
+	slices.Sort(imports)
+	h.int(len(imports))
+	for _, path := range imports {
+		var b bytes.Buffer
+		if err := gcexportdata.Write(&b, c.fset, p.Imports[path].Types); err != nil {
+			return cacheKey{}, fmt.Errorf("export data of %s: %w", path, err)
+		}
+		h.string(path)
+		h.bytes(b.Bytes())
+	}
+
+	funcs := make([]string, 0, len(colors))
+	for fn, color := range colors {
+		funcs = append(funcs, fn.String()+" "+color.String())
+	}
+	slices.Sort(funcs)
+	h.strings(funcs)
+
+	var instances []string
+	for fn, fns := range c.generics {
+		if fn.Pkg == nil || fn.Pkg.Pkg != p.Types {
+			continue
+		}
+		for _, instance := range fns {
+			instances = append(instances, fn.String()+" "+instance.String())
+		}
+	}
+	slices.Sort(instances)
+	h.strings(instances)
+
+	return h.sum(), nil
+}
+
+// moduleGoVersion returns the Go version of the module of a package, which
+// the semantics of the compiled code depend on.
+func moduleGoVersion(p *packages.Package) string {
+	if p.Module == nil {
+		return ""
+	}
+	return p.Module.GoVersion
+}
+
+func (c *compiler) hashFiles(h *cacheKeyHash, paths []string) error {
+	h.int(len(paths))
+	for _, path := range paths {
+		sum, err := c.sourceHash(path)
+		if err != nil {
+			return err
+		}
+		h.string(path)
+		h.bytes(sum[:])
+	}
+	return nil
+}
+
+// sourceHash returns the hash of a source file in the form that the compiler
+// rewrites it to, without the !buildTag term added to its build constraint,
+// so that the hash of the file doesn't change once it was compiled.
+func (c *compiler) sourceHash(path string) ([sha256.Size]byte, error) {
+	return c.cache.fileHash(path, func(src []byte) []byte {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			// The syntax errors are reported when loading the package.
+			return src
+		}
+		buildTag := c.buildTagExpr()
+		out, err := formatSource(fset, path, f, func(expr constraint.Expr) constraint.Expr {
+			return withoutNotBuildTag(expr, buildTag)
+		})
+		if err != nil {
+			return src
+		}
+		return out
+	})
+}
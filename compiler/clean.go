@@ -0,0 +1,190 @@
+package compiler
+
+import (
+	"fmt"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Clean removes the output of Compile from a module.
+//
+// Generated files are deleted, the build constraints that were added
+// to the original source files are removed, and GOROOT packages that
+// were vendored into the module are deleted. The files generated for
+// every build configuration are removed, regardless of the GOOS and
+// GOARCH options.
+//
+// The path argument is interpreted the same way as in Compile.
+func Clean(path string, options ...Option) error {
+	c := &compiler{
+		fset:   token.NewFileSet(),
+		logger: log.Default(),
+		output: os.Stdout,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c.clean(path)
+}
+
+func (c *compiler) clean(path string) error {
+	absPath, pattern, err := resolvePath(path)
+	if err != nil {
+		return err
+	}
+	if err := c.initBuildTag(); err != nil {
+		return err
+	}
+
+	// Type information isn't required here, and the module might not
+	// type-check in its current state anyway. We only need to know which
+	// files are excluded from the default build.
+	conf := &packages.Config{
+		Mode:       packages.NeedName | packages.NeedModule | packages.NeedFiles,
+		Fset:       c.fset,
+		Dir:        absPath,
+		Env:        os.Environ(),
+		BuildFlags: c.buildFlags(),
+	}
+	pkgs, err := packages.Load(conf, pattern)
+	if err != nil {
+		return fmt.Errorf("packages.Load %q: %w", path, err)
+	}
+	var moduleDir string
+	for _, p := range pkgs {
+		if p.Module == nil {
+			return fmt.Errorf("package %s is not part of a module", p.PkgPath)
+		}
+		if moduleDir == "" {
+			moduleDir = p.Module.Dir
+		} else if moduleDir != p.Module.Dir {
+			return fmt.Errorf("pattern more than one module (%s + %s)", moduleDir, p.Module.Dir)
+		}
+	}
+
+	buildTag := c.buildTagExpr()
+
+	for _, p := range pkgs {
+		if p.PkgPath == coroutinePackage {
+			continue
+		}
+		if err := c.cleanPackage(p, buildTag); err != nil {
+			return err
+		}
+	}
+
+	if moduleDir != "" {
+		goroot := filepath.Join(moduleDir, "goroot")
+		if isVendoredGOROOT(goroot) {
+			c.logger.Printf("removing vendored GOROOT packages")
+			if err := os.RemoveAll(goroot); err != nil {
+				return err
+			}
+		}
+	}
+
+	c.logger.Printf("done")
+	return nil
+}
+
+// cleanPackage restores the source files of a package that have a generated
+// counterpart, and removes the generated files.
+//
+// Generated files are recognized by their build constraint, and are always
+// excluded from the build since the build tag cannot be satisfied while
+// loading packages. The source files compiled for other build configurations
+// are excluded as well, so all of them are found regardless of the
+// configuration that the package was loaded with.
+//
+// Source files which lost their generated counterpart (e.g. because it was
+// deleted by hand) are restored as well when their constraint contains the
+// !buildTag term added by the compiler. A file without counterpart where this
+// term was written by hand cannot be told apart, and is restored too.
+func (c *compiler) cleanPackage(p *packages.Package, buildTag *constraint.TagExpr) error {
+	files := slices.Concat(p.GoFiles, p.IgnoredFiles)
+	var removed []string
+
+	for _, outputPath := range p.IgnoredFiles {
+		path, ok := strings.CutSuffix(outputPath, c.fileSuffix)
+		if !ok {
+			continue
+		}
+		path += ".go"
+		if !slices.Contains(files, path) {
+			continue
+		}
+
+		genBuildTags, err := c.parseFileBuildTags(outputPath)
+		if err != nil {
+			return err
+		}
+		if genBuildTags == nil || !containsExpr(genBuildTags, buildTag) {
+			continue // not generated by the compiler
+		}
+
+		if err := c.restoreFile(path, buildTag); err != nil {
+			return err
+		}
+		if err := os.Remove(outputPath); err != nil {
+			return err
+		}
+		removed = append(removed, outputPath)
+	}
+
+	notBuildTag := &constraint.NotExpr{X: buildTag}
+	for _, path := range files {
+		if slices.Contains(removed, path) {
+			continue
+		}
+		outputPath := strings.TrimSuffix(path, ".go") + c.fileSuffix
+		if slices.Contains(files, outputPath) {
+			continue
+		}
+
+		buildTags, err := c.parseFileBuildTags(path)
+		if err != nil {
+			return err
+		}
+		if buildTags == nil || !containsExpr(buildTags, notBuildTag) {
+			continue
+		}
+
+		if err := c.restoreFile(path, buildTag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *compiler) parseFileBuildTags(path string) (constraint.Expr, error) {
+	f, err := parser.ParseFile(c.fset, path, nil, parser.ParseComments|parser.PackageClauseOnly)
+	if err != nil {
+		return nil, err
+	}
+	return parseBuildTags(f)
+}
+
+// restoreFile removes the !buildTag term added by the compiler from the build
+// constraint of a source file.
+func (c *compiler) restoreFile(path string, buildTag *constraint.TagExpr) error {
+	c.logger.Printf("cleaning %s", path)
+	f, err := parser.ParseFile(c.fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	src, err := c.formatFile(path, f, nil, func(expr constraint.Expr) constraint.Expr {
+		return withoutNotBuildTag(expr, buildTag)
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, src, 0666)
+}
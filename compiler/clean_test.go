@@ -0,0 +1,99 @@
+package compiler
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestClean(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		goroot   func(dir string) error
+		vendored bool
+	}{
+		{
+			name: "vendored GOROOT",
+			goroot: func(dir string) error {
+				if err := os.MkdirAll(filepath.Join(dir, "src"), 0755); err != nil {
+					return err
+				}
+				return os.Symlink(filepath.Join(runtime.GOROOT(), "pkg"), filepath.Join(dir, "pkg"))
+			},
+			vendored: true,
+		},
+		{
+			name: "other goroot directory",
+			goroot: func(dir string) error {
+				if err := os.MkdirAll(filepath.Join(dir, "pkg"), 0755); err != nil {
+					return err
+				}
+				return os.WriteFile(filepath.Join(dir, "README"), []byte("not generated\n"), 0666)
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			files := map[string]string{
+				"go.mod": "module example.com/clean\n\ngo 1.22\n",
+
+				"clean.go":         "//go:build !durable\n\npackage clean\n",
+				"clean_durable.go": "//go:build durable\n\npackage clean\n",
+
+				// Compiled for another GOOS, see TestBuildPlatforms.
+				"clean_windows.go":         "//go:build !durable\n\npackage clean\n",
+				"clean_windows_durable.go": "//go:build windows && durable\n\npackage clean\n",
+
+				// Not generated by the compiler.
+				"other.go":         "//go:build !durable\n\npackage clean\n",
+				"other_durable.go": "//go:build linux\n\npackage clean\n",
+
+				// The generated counterpart was deleted.
+				"orphan.go":       "//go:build linux && !durable\n\npackage clean\n",
+				"orphan_arm64.go": "//go:build !durable\n\npackage clean\n",
+			}
+			for name, content := range files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0666); err != nil {
+					t.Fatal(err)
+				}
+			}
+			goroot := filepath.Join(dir, "goroot")
+			if err := test.goroot(goroot); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := Clean(dir, Logger(log.New(io.Discard, "", 0))); err != nil {
+				t.Fatal(err)
+			}
+
+			for name, want := range map[string]string{
+				"clean.go":         "package clean\n",
+				"clean_windows.go": "package clean\n",
+				"other.go":         files["other.go"],
+				"other_durable.go": files["other_durable.go"],
+				"orphan.go":        "//go:build linux\n\npackage clean\n",
+				"orphan_arm64.go":  "package clean\n",
+			} {
+				b, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Error(err)
+				} else if string(b) != want {
+					t.Errorf("unexpected content of %s:\n%s\nexpect:\n%s", name, b, want)
+				}
+			}
+			for _, name := range []string{"clean_durable.go", "clean_windows_durable.go"} {
+				if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+					t.Errorf("%s was not removed: %v", name, err)
+				}
+			}
+
+			_, err := os.Stat(goroot)
+			if removed := os.IsNotExist(err); removed != test.vendored {
+				t.Errorf("goroot directory removed: %t, expect %t", removed, test.vendored)
+			}
+		})
+	}
+}
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"runtime/debug"
 	"runtime/pprof"
+	"strings"
 
 	"github.com/dispatchrun/coroutine/compiler"
 )
@@ -17,18 +21,50 @@ coroc is a coroutine compiler for Go.
 
 USAGE:
   coroc [OPTIONS] [PATH]
+  coroc clean [PATH]
+  coroc explain <FUNC> [PATH]
+
+COMMANDS:
+  clean              Remove generated files and restore build constraints
+  explain            Print the shortest call chain from a function to a
+                     yield point, explaining why the function was colored
 
 OPTIONS:
   -h, --help         Show this help information
   -l, --list         List all files that would be compiled
   -v, --version      Show the compiler version
 
+  -tags <TAGS>       Comma-separated list of additional build tags to
+                     satisfy while loading packages
+
+  -goos <GOOS>       Comma-separated list of target operating systems
+  -goarch <GOARCH>   Comma-separated list of target architectures. The
+                     module is compiled for every combination of GOOS
+                     and GOARCH, so that files behind platform build
+                     constraints are compiled as well
+
+  -buildtag <TAG>    Build tag of the generated files. Default is durable.
+                     Programs generated with another tag are built with
+                     -tags coroutine.durable,<TAG>
+  -suffix <SUFFIX>   Suffix of the generated files. Default is _<TAG>.go
+
+  -report <FILE>     Write a JSON report of the generated coroutines,
+                     with their frame layout and sizes, the number of
+                     resume points and the registered functions
+
 ADVANCED OPTIONS:
+  -cache <DIR>       Set the directory of the compilation cache, or disable
+                     the cache with "off". Default is coroc in the user
+                     cache directory.
+
   -callgraph <TYPE>  Set the callgraph construction algorithm
                      (static, cha, rta, vta). Default is vta.
 
   -colors            Print debug information about function colors
 
+  -json              Print errors as JSON objects (one per line) with the
+                     file, line, column, function and message fields
+
   -cpuprofile        Write CPU profile to file
   -memprofile        Write memory profile to file
 `
@@ -37,7 +73,15 @@ var (
 	showVersion   bool
 	onlyListFiles bool
 	debugColors   bool
+	jsonOutput    bool
 	callgraphType string
+	buildTags     string
+	goos          string
+	goarch        string
+	buildTag      string
+	fileSuffix    string
+	cacheDir      string
+	reportPath    string
 	cpuProfile    string
 	memProfile    string
 )
@@ -50,7 +94,11 @@ func boolFlag(ptr *bool, names ...string) {
 
 func main() {
 	if err := run(); err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		if jsonOutput {
+			printJSON(err)
+		} else {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		}
 		os.Exit(1)
 	}
 }
@@ -61,7 +109,15 @@ func run() error {
 	boolFlag(&showVersion, "v", "version")
 	boolFlag(&onlyListFiles, "l", "list")
 	boolFlag(&debugColors, "colors")
+	boolFlag(&jsonOutput, "json")
 	flag.StringVar(&callgraphType, "callgraph", "", "")
+	flag.StringVar(&buildTags, "tags", "", "")
+	flag.StringVar(&goos, "goos", "", "")
+	flag.StringVar(&goarch, "goarch", "", "")
+	flag.StringVar(&buildTag, "buildtag", "", "")
+	flag.StringVar(&fileSuffix, "suffix", "", "")
+	flag.StringVar(&cacheDir, "cache", "", "")
+	flag.StringVar(&reportPath, "report", "", "")
 	flag.StringVar(&cpuProfile, "cpuprofile", "", "")
 	flag.StringVar(&memProfile, "memprofile", "", "")
 	flag.Parse()
@@ -96,7 +152,25 @@ func run() error {
 		defer pprof.StopCPUProfile()
 	}
 
-	path := flag.Arg(0)
+	args := flag.Args()
+	var clean bool
+	var explain string
+	if len(args) > 0 {
+		switch args[0] {
+		case "clean":
+			clean, args = true, args[1:]
+		case "explain":
+			if len(args) < 2 {
+				return fmt.Errorf("usage: coroc explain <FUNC> [PATH]")
+			}
+			explain, args = args[1], args[2:]
+		}
+	}
+
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	}
 	if path == "" {
 		// If the compiler was invoked via go generate, the GOFILE
 		// environment variable will be set with the name of the file
@@ -110,17 +184,70 @@ func run() error {
 		}
 	}
 
-	if onlyListFiles {
+	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
+	if onlyListFiles || explain != "" {
 		log.SetOutput(io.Discard)
 	}
 
+	if clean {
+		return compiler.Clean(path,
+			compiler.BuildTags(splitList(buildTags)...),
+			compiler.BuildTag(buildTag),
+			compiler.FileSuffix(fileSuffix),
+		)
+	}
+
+	switch cacheDir {
+	case "":
+		if dir, err := os.UserCacheDir(); err == nil {
+			cacheDir = filepath.Join(dir, "coroc")
+		}
+	case "off":
+		cacheDir = ""
+	}
+
 	return compiler.Compile(path,
 		compiler.CallgraphType(callgraphType),
 		compiler.OnlyListFiles(onlyListFiles),
 		compiler.DebugColors(debugColors),
+		compiler.Explain(explain),
+		compiler.BuildTags(splitList(buildTags)...),
+		compiler.GOOS(splitList(goos)...),
+		compiler.GOARCH(splitList(goarch)...),
+		compiler.BuildTag(buildTag),
+		compiler.FileSuffix(fileSuffix),
+		compiler.Cache(cacheDir),
+		compiler.Report(reportPath),
 	)
 }
 
+// splitList splits a comma-separated list of values.
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+func printJSON(err error) {
+	var diags compiler.Diagnostics
+	var diag *compiler.Diagnostic
+	switch {
+	case errors.As(err, &diags):
+	case errors.As(err, &diag):
+		diags = compiler.Diagnostics{diag}
+	default:
+		diags = compiler.Diagnostics{{Message: err.Error()}}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	for _, diag := range diags {
+		if err := enc.Encode(diag); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			return
+		}
+	}
+}
+
 func version() (version string) {
 	version = "devel"
 	if info, ok := debug.ReadBuildInfo(); ok {
@@ -0,0 +1,16 @@
+// Command corovet reports problems that would prevent coroutines from being
+// compiled to their durable form by coroc.
+//
+// It can be run standalone, or as a vet tool:
+//
+//	go vet -vettool=$(which corovet) ./...
+package main
+
+import (
+	"github.com/dispatchrun/coroutine/compiler"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(compiler.Analyzer)
+}
@@ -0,0 +1,281 @@
+package compiler
+
+import (
+	"cmp"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"slices"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+// generateCodecs generates serialization and deserialization functions for
+// the frames of the coroutines compiled in a file, and for the struct types
+// declared in the package which are reachable from those frames. The functions
+// are registered with types.RegisterCodec, allowing the types package to
+// serialize the struct fields without reflection.
+//
+// Frames of generic functions, or which refer to types that are not declared
+// at the package level, are skipped; the types package falls back to using
+// reflection for them.
+func generateCodecs(p *packages.Package, f *ast.File, frames []*ast.StructType) {
+	init := new(ast.BlockStmt)
+	named := map[*types.Named]struct{}{}
+	seen := map[string]struct{}{}
+
+	for _, frame := range frames {
+		if !isPackageLevelTypeExpr(p, frame) {
+			continue
+		}
+		key := types.ExprString(frame)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		var fields []string
+		for _, field := range frame.Fields.List {
+			for _, name := range field.Names {
+				fields = append(fields, name.Name)
+			}
+			collectNamedStructs(p, field.Type, named)
+		}
+		init.List = append(init.List, registerCodec(frame, fields))
+	}
+
+	namedStructs := make([]*types.Named, 0, len(named))
+	for t := range named {
+		namedStructs = append(namedStructs, t)
+	}
+	slices.SortFunc(namedStructs, func(a, b *types.Named) int {
+		return cmp.Compare(a.Obj().Name(), b.Obj().Name())
+	})
+
+	for _, t := range namedStructs {
+		s := t.Underlying().(*types.Struct)
+		var fields []string
+		for i := 0; i < s.NumFields(); i++ {
+			if !isTransientField(s, i) {
+				fields = append(fields, s.Field(i).Name())
+			}
+		}
+		init.List = append(init.List, registerCodec(ast.NewIdent(t.Obj().Name()), fields))
+	}
+
+	if len(init.List) == 0 {
+		return
+	}
+
+	astutil.AddNamedImport(nil, f, "_types", "github.com/dispatchrun/coroutine/types")
+	appendInit(f, init.List)
+}
+
+// appendInit adds statements to the init function generated for the function
+// types if there is one, or to a new init function otherwise.
+func appendInit(f *ast.File, stmts []ast.Stmt) {
+	for _, decl := range f.Decls {
+		if d, ok := decl.(*ast.FuncDecl); ok && d.Recv == nil && d.Name.Name == "init" && d.Body != nil {
+			d.Body.List = append(d.Body.List, stmts...)
+			return
+		}
+	}
+	f.Decls = append(f.Decls,
+		&ast.FuncDecl{
+			Name: ast.NewIdent("init"),
+			Type: &ast.FuncType{Params: new(ast.FieldList)},
+			Body: &ast.BlockStmt{List: stmts},
+		})
+}
+
+// registerCodec generates a call to types.RegisterCodec for a struct type with
+// the given fields.
+func registerCodec(typ ast.Expr, fields []string) ast.Stmt {
+	codecFunc := func(arg, argType, fieldFunc string) *ast.FuncLit {
+		body := new(ast.BlockStmt)
+		for _, field := range fields {
+			body.List = append(body.List, &ast.ExprStmt{
+				X: &ast.CallExpr{
+					Fun: &ast.SelectorExpr{X: ast.NewIdent("_types"), Sel: ast.NewIdent(fieldFunc)},
+					Args: []ast.Expr{
+						ast.NewIdent(arg),
+						&ast.UnaryExpr{
+							Op: token.AND,
+							X:  &ast.SelectorExpr{X: ast.NewIdent("x"), Sel: ast.NewIdent(field)},
+						},
+					},
+				},
+			})
+		}
+		return &ast.FuncLit{
+			Type: &ast.FuncType{
+				Params: &ast.FieldList{List: []*ast.Field{
+					{
+						Names: []*ast.Ident{ast.NewIdent(arg)},
+						Type:  &ast.StarExpr{X: &ast.SelectorExpr{X: ast.NewIdent("_types"), Sel: ast.NewIdent(argType)}},
+					},
+					{
+						Names: []*ast.Ident{ast.NewIdent("x")},
+						Type:  &ast.StarExpr{X: typ},
+					},
+				}},
+			},
+			Body: body,
+		}
+	}
+
+	return &ast.ExprStmt{
+		X: &ast.CallExpr{
+			Fun: &ast.SelectorExpr{X: ast.NewIdent("_types"), Sel: ast.NewIdent("RegisterCodec")},
+			Args: []ast.Expr{
+				codecFunc("s", "Serializer", "SerializeField"),
+				codecFunc("d", "Deserializer", "DeserializeField"),
+			},
+		},
+	}
+}
+
+// isPackageLevelTypeExpr returns true if the type expression only refers to
+// predeclared types, types declared at the package level, and types of other
+// packages; such type expressions are valid outside of the function that they
+// were generated for.
+func isPackageLevelTypeExpr(p *packages.Package, expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if obj, ok := types.Universe.Lookup(e.Name).(*types.TypeName); ok && obj != nil {
+			return true
+		}
+		obj, ok := p.Types.Scope().Lookup(e.Name).(*types.TypeName)
+		return ok && !isGenericType(obj.Type())
+	case *ast.SelectorExpr:
+		return true
+	case *ast.StarExpr:
+		return isPackageLevelTypeExpr(p, e.X)
+	case *ast.ArrayType:
+		return isPackageLevelTypeExpr(p, e.Elt)
+	case *ast.Ellipsis:
+		return isPackageLevelTypeExpr(p, e.Elt)
+	case *ast.MapType:
+		return isPackageLevelTypeExpr(p, e.Key) && isPackageLevelTypeExpr(p, e.Value)
+	case *ast.ChanType:
+		return isPackageLevelTypeExpr(p, e.Value)
+	case *ast.StructType:
+		return isPackageLevelFieldList(p, e.Fields)
+	case *ast.InterfaceType:
+		return isPackageLevelFieldList(p, e.Methods)
+	case *ast.FuncType:
+		return isPackageLevelFieldList(p, e.Params) && isPackageLevelFieldList(p, e.Results)
+	case *ast.IndexExpr:
+		return isPackageLevelTypeExpr(p, e.Index) && isPackageLevelGenericType(p, e.X)
+	case *ast.IndexListExpr:
+		for _, index := range e.Indices {
+			if !isPackageLevelTypeExpr(p, index) {
+				return false
+			}
+		}
+		return isPackageLevelGenericType(p, e.X)
+	case *ast.ParenExpr:
+		return isPackageLevelTypeExpr(p, e.X)
+	}
+	return false
+}
+
+func isPackageLevelFieldList(p *packages.Package, fields *ast.FieldList) bool {
+	if fields == nil {
+		return true
+	}
+	for _, field := range fields.List {
+		if !isPackageLevelTypeExpr(p, field.Type) {
+			return false
+		}
+	}
+	return true
+}
+
+func isPackageLevelGenericType(p *packages.Package, expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		_, ok := p.Types.Scope().Lookup(e.Name).(*types.TypeName)
+		return ok
+	case *ast.SelectorExpr:
+		return true
+	}
+	return false
+}
+
+func isGenericType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.TypeParams().Len() > 0
+}
+
+// collectNamedStructs collects the non-generic struct types declared at the
+// package level which are reachable from a type expression.
+func collectNamedStructs(p *packages.Package, expr ast.Expr, named map[*types.Named]struct{}) {
+	seen := map[types.Type]struct{}{}
+
+	var visit func(types.Type)
+	visit = func(t types.Type) {
+		if _, ok := seen[t]; ok {
+			return
+		}
+		seen[t] = struct{}{}
+
+		switch t := t.(type) {
+		case *types.Named:
+			obj := t.Obj()
+			if obj.Pkg() != p.Types || obj.Parent() != p.Types.Scope() || t.TypeParams().Len() > 0 {
+				return
+			}
+			if s, ok := t.Underlying().(*types.Struct); ok && hasNamedFields(s) {
+				named[t] = struct{}{}
+			}
+			visit(t.Underlying())
+		case *types.Pointer:
+			visit(t.Elem())
+		case *types.Slice:
+			visit(t.Elem())
+		case *types.Array:
+			visit(t.Elem())
+		case *types.Map:
+			visit(t.Key())
+			visit(t.Elem())
+		case *types.Struct:
+			for i := 0; i < t.NumFields(); i++ {
+				if !isTransientField(t, i) {
+					visit(t.Field(i).Type())
+				}
+			}
+		}
+	}
+
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.SelectorExpr:
+			return false
+		case *ast.Ident:
+			if obj, ok := p.Types.Scope().Lookup(n.Name).(*types.TypeName); ok {
+				visit(obj.Type())
+			}
+		}
+		return true
+	})
+}
+
+// hasNamedFields returns true if all the fields of a struct can be accessed
+// with a selector expression.
+func hasNamedFields(s *types.Struct) bool {
+	for i := 0; i < s.NumFields(); i++ {
+		if s.Field(i).Name() == "_" {
+			return false
+		}
+	}
+	return true
+}
+
+// isTransientField returns true if the i-th field of a struct is tagged with
+// `coroutine:"-"`; the types package does not serialize those fields.
+func isTransientField(s *types.Struct, i int) bool {
+	return reflect.StructTag(s.Tag(i)).Get("coroutine") == "-"
+}
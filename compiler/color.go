@@ -1,8 +1,11 @@
 package compiler
 
 import (
+	"cmp"
 	"fmt"
+	"go/token"
 	"go/types"
+	"slices"
 	"strings"
 
 	"golang.org/x/tools/go/callgraph"
@@ -12,31 +15,213 @@ import (
 // colorFunctions walks the call graph, coloring functions that yield (or may
 // yield) by their yield type. It's an error if a function has more than one
 // yield type.
-func (c *compiler) colorFunctions(cg *callgraph.Graph, yieldInstances functionColors) (functionColors, error) {
+//
+// The walk honors the //coroutine:noyield and //coroutine:yields directives,
+// see directive.go.
+func (c *compiler) colorFunctions(cg *callgraph.Graph, functions map[*ssa.Function]bool, yieldInstances functionColors) (functionColors, error) {
+	if err := c.verifyNoYield(cg, yieldInstances); err != nil {
+		return nil, err
+	}
+
+	// Roots are walked in a deterministic order, so that the errors reported
+	// don't change between runs.
+	roots := make([]*ssa.Function, 0, len(yieldInstances))
+	for yieldInstance := range yieldInstances {
+		roots = append(roots, yieldInstance)
+	}
+	c.sortFunctions(roots)
+
 	colors := map[*ssa.Function]*types.Signature{}
-	for yieldInstance, color := range yieldInstances {
+	for _, yieldInstance := range roots {
+		color := yieldInstances[yieldInstance]
 		if c.debugColors {
-			fmt.Println("[color] scanning root", yieldInstance, "with color:", color)
+			fmt.Fprintln(c.output, "[color] scanning root", yieldInstance, "with color:", color)
 		}
 		if err := c.colorFunctions0(cg, colors, yieldInstance, color, 1); err != nil {
 			return nil, err
 		}
 		if c.debugColors {
-			fmt.Println("[color]")
+			fmt.Fprintln(c.output, "[color]")
+		}
+	}
+
+	// Functions marked with //coroutine:yields, as well as callers of
+	// interface methods marked with the directive, are colored even if the
+	// callgraph doesn't contain a path to a yield point. The colors of those
+	// roots may be inferred from the ones colored before them, so they are
+	// walked in the order of their position as well.
+	var forced []*ssa.Function
+	for fn := range functions {
+		if fn.TypeParams().Len() > 0 {
+			continue // only color instances of generic functions
+		}
+		if fn.Blocks == nil {
+			continue // external function, or function of a package that was not built
+		}
+		if c.directives.isYields(fn) || callsYieldingMethod(c.directives, fn) {
+			forced = append(forced, fn)
+		}
+	}
+	c.sortFunctions(forced)
+
+	for _, fn := range forced {
+		if c.directives.isYields(fn) && !hasCalls(fn) {
+			return nil, c.diagnostic(fn.Pos(), fn, "marked %s but does not call any function", yieldsDirective)
+		}
+		color, err := c.inferColor(cg, colors, yieldInstances, fn)
+		if err != nil {
+			return nil, err
+		}
+		if c.debugColors {
+			fmt.Fprintln(c.output, "[color] scanning forced root", fn, "with color:", color)
+		}
+		if err := c.colorFunctions1(cg, colors, fn, color, 1); err != nil {
+			return nil, err
+		}
+		if c.debugColors {
+			fmt.Fprintln(c.output, "[color]")
 		}
 	}
 	return colors, nil
 }
 
+// sortFunctions sorts functions by position, and by name when they share the
+// same position, like the instances of a generic function.
+func (c *compiler) sortFunctions(fns []*ssa.Function) {
+	slices.SortFunc(fns, func(a, b *ssa.Function) int {
+		pa, pb := c.prog.Fset.Position(a.Pos()), c.prog.Fset.Position(b.Pos())
+		return cmp.Or(
+			cmp.Compare(pa.Filename, pb.Filename),
+			cmp.Compare(pa.Offset, pb.Offset),
+			strings.Compare(a.String(), b.String()),
+		)
+	})
+}
+
+// verifyNoYield checks that functions marked with //coroutine:noyield don't
+// reach a yield point through a chain of static calls. Dynamic calls are not
+// checked since the point of the directive is to prune imprecise edges of the
+// callgraph.
+func (c *compiler) verifyNoYield(cg *callgraph.Graph, yieldInstances functionColors) error {
+	seen := map[*ssa.Function]struct{}{}
+	queue := make([]*ssa.Function, 0, len(yieldInstances))
+	for fn := range yieldInstances {
+		queue = append(queue, fn)
+	}
+	for len(queue) > 0 {
+		fn := queue[0]
+		queue = queue[1:]
+
+		node := cg.Nodes[fn]
+		if node == nil {
+			continue
+		}
+		for _, edge := range node.In {
+			if edge.Site == nil || edge.Site.Common().StaticCallee() == nil {
+				continue
+			}
+			caller := edge.Caller.Func
+			if isCoroutinePackageFunc(caller) {
+				continue
+			}
+			if c.directives.isNoYield(caller) {
+				return c.diagnostic(edge.Pos(), caller, "marked %s but calls %s which yields", noYieldDirective, fn)
+			}
+			if _, ok := seen[caller]; !ok {
+				seen[caller] = struct{}{}
+				queue = append(queue, caller)
+			}
+		}
+	}
+	return nil
+}
+
+// inferColor determines the color of a function that was forced to yield by
+// a directive. The color is inherited from the functions it calls or from its
+// callers if any of them was colored, otherwise the program must have a single
+// yield type.
+func (c *compiler) inferColor(cg *callgraph.Graph, colors functionColors, yieldInstances functionColors, fn *ssa.Function) (_ *types.Signature, err error) {
+	defer recoverDiagnostic(c.prog.Fset, fn.Pos(), fn.String(), &err)
+
+	if node := cg.Nodes[fn]; node != nil {
+		for _, edge := range node.Out {
+			if color, ok := colors[edge.Callee.Func]; ok {
+				return color, nil
+			}
+			if color, ok := yieldInstances[edge.Callee.Func]; ok {
+				return color, nil
+			}
+		}
+		for _, edge := range node.In {
+			if color, ok := colors[edge.Caller.Func]; ok {
+				return color, nil
+			}
+		}
+	}
+	var color *types.Signature
+	for _, yieldColor := range yieldInstances {
+		if containsTypeParam(yieldColor) {
+			continue
+		}
+		if color == nil {
+			color = yieldColor
+		} else if !types.Identical(color, yieldColor) {
+			return nil, c.diagnostic(fn.Pos(), fn, "cannot infer the color forced by %s (%v or %v)", yieldsDirective, color, yieldColor)
+		}
+	}
+	if color == nil {
+		return nil, c.diagnostic(fn.Pos(), fn, "cannot infer the color forced by %s (the program does not yield)", yieldsDirective)
+	}
+	return color, nil
+}
+
+func hasCalls(fn *ssa.Function) bool {
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			if _, ok := instr.(ssa.CallInstruction); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func callsYieldingMethod(d *directives, fn *ssa.Function) bool {
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			site, ok := instr.(ssa.CallInstruction)
+			if !ok {
+				continue
+			}
+			if method := invokedMethod(site); method != nil {
+				if _, ok := d.yields[method]; ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 type functionColors map[*ssa.Function]*types.Signature
 
 func (c *compiler) colorFunctions0(cg *callgraph.Graph, colors functionColors, fn *ssa.Function, color *types.Signature, depth int) error {
+	node := cg.Nodes[fn]
+	if node == nil {
+		return nil
+	}
 	var prevCaller *ssa.Function
-	for _, edge := range cg.Nodes[fn].In {
+	for _, edge := range node.In {
 		caller := edge.Caller.Func
 		if caller == prevCaller {
 			continue
 		}
+		if c.directives.isPruned(edge) {
+			if c.debugColors {
+				fmt.Fprintln(c.output, "[color] ", strings.Repeat("  ", depth), "pruned", edge)
+			}
+			continue
+		}
 		if err := c.colorFunctions1(cg, colors, edge.Caller.Func, color, depth); err != nil {
 			return err
 		}
@@ -47,7 +232,7 @@ func (c *compiler) colorFunctions0(cg *callgraph.Graph, colors functionColors, f
 
 func (c *compiler) colorFunctions1(cg *callgraph.Graph, colors functionColors, fn *ssa.Function, color *types.Signature, depth int) error {
 	if c.debugColors {
-		fmt.Println("[color] ", strings.Repeat("  ", depth-1), "<~", fn)
+		fmt.Fprintln(c.output, "[color] ", strings.Repeat("  ", depth-1), "<~", fn)
 	}
 	if origin := fn.Origin(); origin != nil && origin.Pkg != nil {
 		// Don't follow edges into and through the coroutine package.
@@ -56,10 +241,17 @@ func (c *compiler) colorFunctions1(cg *callgraph.Graph, colors functionColors, f
 		}
 	}
 
+	if c.directives.isNoYield(fn) {
+		if c.debugColors {
+			fmt.Fprintln(c.output, "[color] ", strings.Repeat("  ", depth), "pruned", fn)
+		}
+		return nil
+	}
+
 	existing, ok := colors[fn]
 	if ok {
 		if !types.Identical(existing, color) {
-			return fmt.Errorf("function %s has more than one color (%v + %v)", fn, existing, color)
+			return c.diagnostic(fn.Pos(), fn, "function has more than one color (%v + %v)", existing, color)
 		}
 		return nil // already walked
 	}
@@ -67,3 +259,13 @@ func (c *compiler) colorFunctions1(cg *callgraph.Graph, colors functionColors, f
 	colors[fn] = color
 	return c.colorFunctions0(cg, colors, fn, color, depth+1)
 }
+
+// diagnostic creates a *Diagnostic error for a problem found while coloring
+// the function fn.
+func (c *compiler) diagnostic(pos token.Pos, fn *ssa.Function, format string, args ...any) *Diagnostic {
+	return &Diagnostic{
+		Pos:      c.prog.Fset.Position(pos),
+		Function: fn.String(),
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
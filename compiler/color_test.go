@@ -0,0 +1,31 @@
+package compiler
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestColorForcedRoots(t *testing.T) {
+	dir, err := filepath.Abs("testdata/directives")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The three functions are invalid; the diagnostic must always be
+	// reported for the first one.
+	_, err = Build(dir)
+
+	var diag *Diagnostic
+	if !errors.As(err, &diag) {
+		t.Fatalf("expected *Diagnostic error, got %v", err)
+	}
+	want := Diagnostic{
+		Pos:      diag.Pos,
+		Function: "github.com/dispatchrun/coroutine/compiler/testdata/directives.First",
+		Message:  "marked //coroutine:yields but does not call any function",
+	}
+	if *diag != want || filepath.Base(diag.Pos.Filename) != "directives.go" || diag.Pos.Line != 10 {
+		t.Errorf("unexpected diagnostic: %v", diag)
+	}
+}
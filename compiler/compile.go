@@ -1,6 +1,7 @@
 package compiler
 
 import (
+	"bytes"
 	"cmp"
 	"fmt"
 	"go/ast"
@@ -8,6 +9,7 @@ import (
 	"go/format"
 	"go/token"
 	"go/types"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -16,6 +18,7 @@ import (
 	"strconv"
 	"strings"
 
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/go/callgraph"
 	"golang.org/x/tools/go/callgraph/cha"
@@ -37,9 +40,46 @@ const coroutinePackage = "github.com/dispatchrun/coroutine"
 // nearest module is located and compiled as a whole.
 //
 // The path can be absolute, or relative to the current working directory.
+//
+// The generated files are written next to the source files, and the
+// progress of the compiler is reported with the standard logger unless
+// a different one is configured with the Logger option.
 func Compile(path string, options ...Option) error {
 	c := &compiler{
-		fset: token.NewFileSet(),
+		fset:   token.NewFileSet(),
+		logger: log.Default(),
+		output: os.Stdout,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	res, err := c.compile(path)
+	if err != nil {
+		return err
+	}
+	if len(res.Diagnostics) > 0 {
+		return res.Diagnostics
+	}
+	if err := res.Write(); err != nil {
+		return err
+	}
+	if c.report != "" && !c.onlyListFiles && c.explain == "" {
+		return writeReport(c.report, res)
+	}
+	return nil
+}
+
+// Build compiles coroutines in a module like Compile, but returns the
+// generated and modified files instead of writing them to the file system.
+//
+// Problems found in the source code are reported in the Diagnostics field of
+// the result rather than as an error. Build doesn't log anything unless a
+// logger is configured with the Logger option.
+func Build(path string, options ...Option) (*Result, error) {
+	c := &compiler{
+		fset:   token.NewFileSet(),
+		logger: log.New(io.Discard, "", 0),
+		output: io.Discard,
 	}
 	for _, option := range options {
 		option(c)
@@ -51,59 +91,124 @@ type compiler struct {
 	callgraphType string
 	onlyListFiles bool
 	debugColors   bool
+	explain       string
+	buildTags     []string
+	buildTag      string
+	fileSuffix    string
+	goos          []string
+	goarch        []string
+	cache         *cache
+	report        string
+
+	// logger receives progress information and warnings, and output
+	// receives the file lists, explanations and debug information
+	// requested by options.
+	logger *log.Logger
+	output io.Writer
 
 	prog         *ssa.Program
 	generics     map[*ssa.Function][]*ssa.Function
 	coroutinePkg *packages.Package
+	directives   *directives
 
-	fset *token.FileSet
+	fset      *token.FileSet
+	moduleDir string
 }
 
-func (c *compiler) compile(path string) error {
-	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
-
-	absPath, err := filepath.Abs(path)
+func (c *compiler) compile(path string) (*Result, error) {
+	absPath, pattern, err := resolvePath(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	var dotdotdot bool
-	absPath, dotdotdot = strings.CutSuffix(absPath, "...")
-	if s, err := os.Stat(absPath); err != nil {
-		return err
-	} else if !s.IsDir() {
-		// Make sure we're loading whole packages.
-		absPath = filepath.Dir(absPath)
+
+	if c.callgraphType == "" {
+		c.callgraphType = "vta"
 	}
-	var pattern string
-	if dotdotdot {
-		pattern = "./..."
-	} else {
-		pattern = "."
+	if err := c.initBuildTag(); err != nil {
+		return nil, err
+	}
+
+	configs := c.buildConfigs()
+	if c.explain != "" || c.onlyListFiles {
+		// The cache only holds compiled files.
+		c.cache = nil
+		if c.explain != "" {
+			configs = configs[:1]
+		}
+	}
+
+	var key cacheKey
+	if c.cache != nil {
+		if err := c.cache.trim(); err != nil {
+			c.logger.Printf("warning: trimming cache: %v", err)
+		}
+		c.logger.Printf("computing cache key")
+		if key, err = c.moduleKey(absPath, pattern, configs); err != nil {
+			c.logger.Printf("warning: computing cache key: %v", err)
+			c.cache = nil
+		} else if res, ok := c.cache.get(key); ok {
+			c.logger.Printf("using cached result")
+			return res, nil
+		}
+	}
+
+	results := make([]*Result, len(configs))
+	for i, config := range configs {
+		if len(configs) > 1 {
+			c.logger.Printf("compiling for %s", config)
+		}
+		if results[i], err = c.compileConfig(path, absPath, pattern, config); err != nil {
+			return nil, err
+		}
 	}
 
-	log.Printf("reading, parsing and type-checking")
+	if c.onlyListFiles {
+		cwd, _ := os.Getwd()
+		for _, f := range mergeFileLists(results) {
+			relPath, _ := filepath.Rel(cwd, f)
+			fmt.Fprintln(c.output, relPath)
+		}
+		return new(Result), nil
+	}
+
+	res, err := mergeResults(configs, results)
+	if err != nil {
+		return nil, err
+	}
+	if c.cache != nil && len(res.Diagnostics) == 0 {
+		if err := c.cache.put(key, res); err != nil {
+			c.logger.Printf("warning: writing cache: %v", err)
+		}
+	}
+	return res, nil
+}
+
+// compileConfig compiles coroutines in a module for one build configuration.
+func (c *compiler) compileConfig(path, absPath, pattern string, config buildConfig) (*Result, error) {
+	c.logger.Printf("reading, parsing and type-checking")
 	conf := &packages.Config{
 		Mode: packages.NeedName | packages.NeedModule |
 			packages.NeedImports | packages.NeedDeps |
 			packages.NeedFiles | packages.NeedSyntax |
 			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedTypesSizes,
-		Fset: c.fset,
-		Dir:  absPath,
-		Env:  os.Environ(),
+		Fset:       c.fset,
+		Dir:        absPath,
+		Env:        config.env(),
+		BuildFlags: c.buildFlags(),
 	}
 	pkgs, err := packages.Load(conf, pattern)
 	if err != nil {
-		return fmt.Errorf("packages.Load %q: %w", path, err)
+		return nil, fmt.Errorf("packages.Load %q: %w", path, err)
 	}
-	var moduleDir string
+	moduleDir := ""
 	for _, p := range pkgs {
 		if p.Module == nil {
-			return fmt.Errorf("package %s is not part of a module", p.PkgPath)
+			return nil, fmt.Errorf("package %s is not part of a module", p.PkgPath)
 		}
 		if moduleDir == "" {
 			moduleDir = p.Module.Dir
 		} else if moduleDir != p.Module.Dir {
-			return fmt.Errorf("pattern more than one module (%s + %s)", moduleDir, p.Module.Dir)
+			return nil, fmt.Errorf("pattern more than one module (%s + %s)", moduleDir, p.Module.Dir)
 		}
 	}
 	err = nil
@@ -115,18 +220,15 @@ func (c *compiler) compile(path string) error {
 		return err == nil
 	}, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	log.Printf("building SSA program")
+	c.logger.Printf("building SSA program")
 	c.prog, _ = ssautil.AllPackages(pkgs, ssa.InstantiateGenerics|ssa.GlobalDebug)
 	c.prog.Build()
 	functions := ssautil.AllFunctions(c.prog)
 
-	if c.callgraphType == "" {
-		c.callgraphType = "vta"
-	}
-	log.Printf("building callgraph using %s algorithm", c.callgraphType)
+	c.logger.Printf("building callgraph using %s algorithm", c.callgraphType)
 	var cg *callgraph.Graph
 	// See https://cs.opensource.google/go/x/tools/+/refs/tags/v0.16.1:cmd/callgraph/main.go
 	switch c.callgraphType {
@@ -145,10 +247,10 @@ func (c *compiler) compile(path string) error {
 		rtares := rta.Analyze(roots, true)
 		cg = rtares.CallGraph
 	default:
-		return fmt.Errorf("invalid or unsupported callgraph construction algorithm %q", c.callgraphType)
+		return nil, fmt.Errorf("invalid or unsupported callgraph construction algorithm %q", c.callgraphType)
 	}
 
-	log.Printf("collecting generic instances")
+	c.logger.Printf("collecting generic instances")
 	c.generics = map[*ssa.Function][]*ssa.Function{}
 	for fn := range functions {
 		if fn.Signature.TypeParams() != nil {
@@ -161,7 +263,8 @@ func (c *compiler) compile(path string) error {
 		}
 	}
 
-	log.Printf("finding yield points")
+	c.logger.Printf("finding yield points")
+	c.coroutinePkg = nil
 	packages.Visit(pkgs, func(p *packages.Package) bool {
 		if p.PkgPath == coroutinePackage {
 			c.coroutinePkg = p
@@ -169,8 +272,8 @@ func (c *compiler) compile(path string) error {
 		return c.coroutinePkg == nil
 	}, nil)
 	if c.coroutinePkg == nil {
-		log.Printf("%s not imported by the module. Nothing to do", coroutinePackage)
-		return nil
+		c.logger.Printf("%s not imported by the module. Nothing to do", coroutinePackage)
+		return new(Result), nil
 	}
 	yieldFunc := c.prog.FuncValue(c.coroutinePkg.Types.Scope().Lookup("Yield").(*types.Func))
 	yieldInstances := functionColors{}
@@ -180,10 +283,22 @@ func (c *compiler) compile(path string) error {
 		}
 	}
 
-	log.Printf("coloring functions")
-	colors, err := c.colorFunctions(cg, yieldInstances)
+	c.directives, err = parseDirectives(pkgs)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if c.explain != "" {
+		if err := c.explainFunction(cg, yieldInstances, c.explain); err != nil {
+			return nil, err
+		}
+		return new(Result), nil
+	}
+
+	c.logger.Printf("coloring functions")
+	colors, err := c.colorFunctions(cg, functions, yieldInstances)
+	if err != nil {
+		return nil, err
 	}
 	pkgsByTypes := map[*types.Package]*packages.Package{}
 	packages.Visit(pkgs, func(p *packages.Package) bool {
@@ -202,7 +317,7 @@ func (c *compiler) compile(path string) error {
 			if fn.Synthetic != "" {
 				continue
 			}
-			return fmt.Errorf("unsupported yield function %s (Pkg is nil)", fn)
+			return nil, fmt.Errorf("unsupported yield function %s (Pkg is nil)", fn)
 		}
 
 		p := pkgsByTypes[pkg.Pkg]
@@ -220,11 +335,25 @@ func (c *compiler) compile(path string) error {
 	// literal types need to be registered.
 	//
 	// TODO: improve this by scanning dependencies to see if they need to be included
+	//
+	// The coroutine package and the packages it depends on are not compiled,
+	// which matters when compiling packages of the coroutine module.
+	runtimePkgs := map[*packages.Package]bool{}
+	packages.Visit(pkgs, func(p *packages.Package) bool {
+		if p.PkgPath != coroutinePackage {
+			return true
+		}
+		packages.Visit([]*packages.Package{p}, func(dep *packages.Package) bool {
+			runtimePkgs[dep] = true
+			return true
+		}, nil)
+		return false
+	}, nil)
 	packages.Visit(pkgs, func(p *packages.Package) bool {
 		if p.Module == nil || p.Module.Dir != moduleDir {
 			return true
 		}
-		if p.PkgPath == coroutinePackage {
+		if runtimePkgs[p] {
 			return true
 		}
 		if _, ok := colorsByPkg[p]; !ok {
@@ -234,14 +363,37 @@ func (c *compiler) compile(path string) error {
 	}, nil)
 
 	if c.onlyListFiles {
-		cwd, _ := os.Getwd()
+		// Only the paths are returned, and listed by compile.
+		res := new(Result)
 		for pkg := range colorsByPkg {
 			for _, filePath := range pkg.GoFiles {
-				relPath, _ := filepath.Rel(cwd, filePath)
-				fmt.Println(relPath)
+				res.Files = append(res.Files, File{Path: filePath})
+			}
+		}
+		return res, nil
+	}
+
+	// Reject unsupported language features before mutating packages, and
+	// report all of them at once.
+	c.logger.Printf("checking for unsupported language features")
+	var diags Diagnostics
+	for p, colors := range colorsByPkg {
+		colorsByFunc := colorsByFuncOf(colors)
+		for _, f := range p.Syntax {
+			for _, anydecl := range f.Decls {
+				decl, ok := anydecl.(*ast.FuncDecl)
+				if !ok {
+					continue
+				}
+				if colorsByFunc[decl] != nil || containsColoredFuncLit(decl, colorsByFunc) {
+					diags = append(diags, c.unsupported(p, decl, colorsByFunc)...)
+				}
 			}
 		}
-		return nil
+	}
+	if len(diags) > 0 {
+		diags.sort()
+		return &Result{Diagnostics: diags}, nil
 	}
 
 	// Before mutating packages, we need to ensure that packages exist in a
@@ -256,7 +408,7 @@ func (c *compiler) compile(path string) error {
 		// packages (including those in the ./vendor directory).
 		moduleRel, err := filepath.Rel(moduleDir, dir)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if !strings.HasPrefix(moduleRel, "..") {
 			continue
@@ -265,7 +417,7 @@ func (c *compiler) compile(path string) error {
 		// Collect GOROOT packages and vendor them below.
 		gorootRel, err := filepath.Rel(goroot, dir)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if !strings.HasPrefix(gorootRel, "..") {
 			needVendoring = append(needVendoring, p)
@@ -274,89 +426,193 @@ func (c *compiler) compile(path string) error {
 
 		// Reject packages without an associated module.
 		if p.Module == nil {
-			return fmt.Errorf("cannot mutate package %s (%s) without a Go module", p.PkgPath, dir)
+			return nil, fmt.Errorf("cannot mutate package %s (%s) without a Go module", p.PkgPath, dir)
 		}
 
 		// Reject packages outside ./vendor.
-		return fmt.Errorf("cannot mutate package %s (%s) safely. Please vendor dependencies: go mod vendor", p.PkgPath, dir)
+		return nil, fmt.Errorf("cannot mutate package %s (%s) safely. Please vendor dependencies: go mod vendor", p.PkgPath, dir)
 	}
 
+	c.moduleDir = moduleDir
+
+	res := new(Result)
 	if len(needVendoring) > 0 {
-		log.Printf("vendoring GOROOT packages")
-		newRoot := filepath.Join(moduleDir, "goroot")
-		if err := vendorGOROOT(newRoot, needVendoring); err != nil {
-			return err
+		c.logger.Printf("vendoring GOROOT packages")
+		res.GOROOT = filepath.Join(moduleDir, "goroot")
+		if err := rewriteGOROOT(res.GOROOT, needVendoring); err != nil {
+			return nil, err
 		}
 	}
 
-	for p, colors := range colorsByPkg {
-		if err := c.compilePackage(p, colors); err != nil {
-			return err
+	// Packages are compiled concurrently. Results and errors are collected
+	// in the order of package paths so that the output doesn't depend on
+	// scheduling.
+	compiled := make([]*packages.Package, 0, len(colorsByPkg))
+	for p := range colorsByPkg {
+		compiled = append(compiled, p)
+	}
+	slices.SortFunc(compiled, func(a, b *packages.Package) int {
+		return cmp.Compare(a.PkgPath, b.PkgPath)
+	})
+
+	results := make([]*Result, len(compiled))
+	errs := make([]error, len(compiled))
+
+	var group errgroup.Group
+	group.SetLimit(runtime.GOMAXPROCS(0))
+	for i, p := range compiled {
+		group.Go(func() error {
+			results[i], errs[i] = c.compileCachedPackage(p, colorsByPkg[p], config)
+			return nil
+		})
+	}
+	group.Wait()
+
+	for i := range compiled {
+		if err := errs[i]; err != nil {
+			if diag, ok := err.(*Diagnostic); ok {
+				return &Result{Diagnostics: Diagnostics{diag}}, nil
+			}
+			return nil, err
 		}
+		res.Files = append(res.Files, results[i].Files...)
+		res.Functions = append(res.Functions, results[i].Functions...)
 	}
+	slices.SortFunc(res.Files, func(a, b File) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+	sortFunctionReports(res.Functions)
 
-	log.Printf("done")
-	return nil
+	c.logger.Printf("done")
+	return res, nil
 }
 
-func (c *compiler) writeFile(path string, file *ast.File, changeBuildTags func(constraint.Expr) constraint.Expr) error {
+// resolvePath converts the path passed to Compile or Clean to an absolute
+// directory and a package pattern relative to that directory.
+func resolvePath(path string) (dir, pattern string, err error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", err
+	}
+	var dotdotdot bool
+	absPath, dotdotdot = strings.CutSuffix(absPath, "...")
+	if s, err := os.Stat(absPath); err != nil {
+		return "", "", err
+	} else if !s.IsDir() {
+		// Make sure we're loading whole packages.
+		absPath = filepath.Dir(absPath)
+	}
+	if dotdotdot {
+		pattern = "./..."
+	} else {
+		pattern = "."
+	}
+	return absPath, pattern, nil
+}
+
+// formatFile formats a file that will be written to path. When lines is not
+// nil, the markers that it holds are replaced by //line directives.
+func (c *compiler) formatFile(path string, file *ast.File, lines *lineMarkers, changeBuildTags func(constraint.Expr) constraint.Expr) ([]byte, error) {
+	src, err := formatSource(c.fset, path, file, changeBuildTags)
+	if err != nil {
+		return nil, err
+	}
+
+	if lines != nil && len(lines.markers) > 0 {
+		// Line numbers of the directives must be computed on the final
+		// layout of the file, which is only known after formatting the
+		// source.
+		if src, err = format.Source(src); err != nil {
+			return nil, err
+		}
+		src = lines.expand(src, path)
+	}
+	return src, nil
+}
+
+// formatSource formats a file with its build constraint changed by
+// changeBuildTags.
+func formatSource(fset *token.FileSet, path string, file *ast.File, changeBuildTags func(constraint.Expr) constraint.Expr) ([]byte, error) {
 	buildTags, err := parseBuildTags(file)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	buildTags = changeBuildTags(buildTags)
 	stripBuildTagsOf(file, path)
 
 	// Comments are awkward to attach to the tree (they rely on token.Pos, which
 	// is coupled to a token.FileSet). Instead, just write out the raw strings.
-	var b strings.Builder
+	var b bytes.Buffer
 	if buildTags != nil {
 		b.WriteString(`//go:build `)
 		b.WriteString(buildTags.String())
 		b.WriteString("\n\n")
 	}
 
-	f, err := os.Create(path)
+	// Format/write the remainder of the AST.
+	if err := format.Node(&b, fset, file); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// compileCachedPackage compiles a package, or returns its files from the
+// cache when they are available.
+func (c *compiler) compileCachedPackage(p *packages.Package, colors functionColors, config buildConfig) (*Result, error) {
+	if c.cache == nil {
+		return c.compilePackage(p, colors)
+	}
+
+	key, err := c.packageKey(p, colors, config)
 	if err != nil {
-		return err
+		c.logger.Printf("warning: computing cache key of package %s: %v", p.PkgPath, err)
+		return c.compilePackage(p, colors)
+	}
+	if res, ok := c.cache.get(key); ok {
+		c.logger.Printf("using cached package %s", p.Name)
+		return res, nil
 	}
-	defer f.Close()
 
-	if _, err := f.WriteString(b.String()); err != nil {
-		return err
+	res, err := c.compilePackage(p, colors)
+	if err != nil {
+		return nil, err
 	}
-	// Format/write the remainder of the AST.
-	if err := format.Node(f, c.fset, file); err != nil {
-		return err
+	if err := c.cache.put(key, res); err != nil {
+		c.logger.Printf("warning: writing cache: %v", err)
 	}
-	return f.Close()
+	return res, nil
 }
 
-func (c *compiler) compilePackage(p *packages.Package, colors functionColors) error {
-	log.Printf("compiling package %s", p.Name)
+// compilePackage compiles the coroutines of a package, returning the
+// generated and modified files, and the reports of the coroutines.
+func (c *compiler) compilePackage(p *packages.Package, colors functionColors) (*Result, error) {
+	c.logger.Printf("compiling package %s", p.Name)
 
-	colorsByFunc := map[ast.Node]*types.Signature{}
-	for fn, color := range colors {
-		decl := fn.Syntax()
-		switch decl.(type) {
-		case *ast.FuncDecl:
-		case *ast.FuncLit:
-		default:
-			continue
-		}
-		colorsByFunc[decl] = color
-	}
+	colorsByFunc := colorsByFuncOf(colors)
 
-	buildTag := &constraint.TagExpr{
-		Tag: "durable",
-	}
+	buildTag := c.buildTagExpr()
 
+	res := new(Result)
 	for i, f := range p.Syntax {
-		if err := c.writeFile(p.GoFiles[i], f, func(expr constraint.Expr) constraint.Expr {
+		var fileConstraint constraint.Expr
+		src, err := c.formatFile(p.GoFiles[i], f, nil, func(expr constraint.Expr) constraint.Expr {
+			fileConstraint = withoutNotBuildTag(expr, buildTag)
 			return withoutBuildTag(expr, buildTag)
-		}); err != nil {
-			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		res.Files = append(res.Files, File{Path: p.GoFiles[i], Content: src})
+
+		filename := c.fset.Position(f.Package).Filename
+		lines := newLineMarkers(c.fset)
+		if err := lines.setOffset(f, src); err != nil {
+			return nil, err
 		}
+		var compiledDecls []*ast.FuncDecl
+		var frames []*ast.StructType
+		digests := funcLitDigests(f)
+		reports := map[*ast.FuncDecl][]FunctionReport{}
 
 		// Generate the coroutine AST.
 		gen := &ast.File{
@@ -378,13 +634,22 @@ func (c *compiler) compilePackage(p *packages.Package, colors functionColors) er
 
 				compiled := false
 				if color != nil || containsColoredFuncLit(decl, colorsByFunc) {
-					// Reject certain language features for now.
-					if err := c.unsupported(decl, p.TypesInfo, colorsByFunc); err != nil {
-						return err
+					scope := &scope{compiler: c, colors: colorsByFunc, lines: lines, frames: &frames, digests: digests, funcName: funcDeclName(p, decl), perIterationLoopVars: perIterationLoopVars(p, f)}
+					if obj, ok := p.TypesInfo.Defs[decl.Name].(*types.Func); ok {
+						signature := obj.Type().(*types.Signature)
+						scope.generic = signature.TypeParams().Len() > 0 || signature.RecvTypeParams().Len() > 0
+					}
+					gen, err := scope.compileFuncDecl(p, decl, color)
+					if err != nil {
+						if diag, ok := err.(*Diagnostic); ok && diag.Function == "" {
+							diag.Function = funcDeclName(p, decl)
+						}
+						return nil, err
 					}
-					scope := &scope{compiler: c, colors: colorsByFunc}
-					decl = scope.compileFuncDecl(p, decl, color)
+					decl = gen
 					compiled = true
+					compiledDecls = append(compiledDecls, decl)
+					reports[decl] = scope.reports
 				}
 
 				if compiled || containsFuncLit(decl) {
@@ -415,22 +680,99 @@ func (c *compiler) compilePackage(p *packages.Package, colors functionColors) er
 			}
 		}
 
-		c.generateFunctypes(p, gen, colorsByFunc)
+		registered, err := c.generateFunctypes(p, gen, colorsByFunc, digests)
+		if err != nil {
+			return nil, err
+		}
+		generateCodecs(p, gen, frames)
+
+		var fileReports []FunctionReport
+		for _, decl := range compiledDecls {
+			for _, report := range reports[decl] {
+				if !report.Literal {
+					report.Registered = registered[decl]
+				}
+				fileReports = append(fileReports, report)
+			}
+		}
+		c.generateVersions(p, gen, fileReports)
+		res.Functions = append(res.Functions, fileReports...)
 
 		// Find all the required imports for this file.
 		gen = addImports(p, f, gen)
 
+		// Map the statements of compiled functions back to the original
+		// source code.
+		for _, decl := range compiledDecls {
+			lines.addLineMarkers(decl, filename)
+		}
+
 		outputPath := strings.TrimSuffix(p.GoFiles[i], ".go")
-		outputPath += "_durable.go"
+		outputPath += c.fileSuffix
+
+		// The generated file must be built under the same constraints as the
+		// source file it replaces, including the _GOOS or _GOARCH suffix of
+		// the source file name which the generated file doesn't end with.
+		fileTags := fileNameTags(p.GoFiles[i])
 
-		if err := c.writeFile(outputPath, gen, func(expr constraint.Expr) constraint.Expr {
+		src, err = c.formatFile(outputPath, gen, lines, func(constraint.Expr) constraint.Expr {
+			expr := fileConstraint
+			for _, tag := range fileTags {
+				expr = withBuildTag(expr, tag)
+			}
 			return withBuildTag(expr, buildTag)
-		}); err != nil {
-			return err
+		})
+		if err != nil {
+			return nil, err
 		}
+		res.Files = append(res.Files, File{Path: outputPath, Content: src})
 	}
 
-	return nil
+	sortFunctionReports(res.Functions)
+	return res, nil
+}
+
+// colorsByFuncOf maps the syntax nodes of colored functions to their color.
+//
+// The instances of a generic function share the syntax of the function, which
+// is compiled with the color of the generic function itself if it is colored,
+// or else with the color of the first instance in the order of their names, so
+// the output doesn't depend on the order of iteration over the colors.
+func colorsByFuncOf(colors functionColors) map[ast.Node]*types.Signature {
+	fns := make([]*ssa.Function, 0, len(colors))
+	for fn := range colors {
+		fns = append(fns, fn)
+	}
+	slices.SortFunc(fns, func(a, b *ssa.Function) int {
+		if ia, ib := isInstance(a), isInstance(b); ia != ib {
+			if ia {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Compare(a.String(), b.String())
+	})
+
+	colorsByFunc := map[ast.Node]*types.Signature{}
+	for _, fn := range fns {
+		decl := fn.Syntax()
+		switch decl.(type) {
+		case *ast.FuncDecl:
+		case *ast.FuncLit:
+		default:
+			continue
+		}
+		if _, ok := colorsByFunc[decl]; !ok {
+			colorsByFunc[decl] = colors[fn]
+		}
+	}
+	return colorsByFunc
+}
+
+// isInstance returns true if fn is an instance of a generic function, or a
+// function literal of such an instance.
+func isInstance(fn *ssa.Function) bool {
+	return fn.Origin() != nil || len(fn.TypeArgs()) > 0
 }
 
 func containsColoredFuncLit(decl ast.Node, colorsByFunc map[ast.Node]*types.Signature) (yes bool) {
@@ -529,6 +871,23 @@ type scope struct {
 	compiler *compiler
 
 	colors map[ast.Node]*types.Signature
+	// Positions of the original statements that generated statements were
+	// derived from.
+	lines *lineMarkers
+	// Frame types of the coroutines compiled in the file, for which codecs
+	// are generated. Frames of generic functions are not collected.
+	frames  *[]*ast.StructType
+	generic bool
+	// Whether loops declare variables for each iteration, which depends on
+	// the Go version of the file (see loopvar.go).
+	perIterationLoopVars bool
+	// Digests of the function literals of the file, which are carried over
+	// to the compiled literals (see closureID).
+	digests map[*ast.FuncLit]string
+	// Name of the function declaration being compiled, and reports of the
+	// coroutines compiled within it.
+	funcName string
+	reports  []FunctionReport
 	// Index used to generate unique object identifiers within the scope of a
 	// function.
 	//
@@ -544,31 +903,68 @@ type scope struct {
 	frameIndex int
 }
 
-func (scope *scope) compileFuncDecl(p *packages.Package, fn *ast.FuncDecl, color *types.Signature) *ast.FuncDecl {
-	log.Printf("compiling function %s.%s", p.Name, fn.Name)
+func (scope *scope) addFrame(frameType *ast.StructType) {
+	if scope.frames != nil && !scope.generic {
+		*scope.frames = append(*scope.frames, frameType)
+	}
+}
+
+// addReport records the report of a compiled coroutine.
+func (scope *scope) addReport(report *FunctionReport, name string, pos token.Pos, literal bool) {
+	if report == nil {
+		return
+	}
+	position := scope.compiler.fset.Position(pos)
+	report.Name = name
+	report.Literal = literal
+	report.File = position.Filename
+	if rel, err := filepath.Rel(scope.compiler.moduleDir, position.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+		report.File = filepath.ToSlash(rel)
+	}
+	report.Line = position.Line
+	scope.reports = append(scope.reports, *report)
+}
+
+func (scope *scope) compileFuncDecl(p *packages.Package, fn *ast.FuncDecl, color *types.Signature) (_ *ast.FuncDecl, err error) {
+	scope.compiler.logger.Printf("compiling function %s.%s", p.Name, fn.Name)
+	defer recoverDiagnostic(p.Fset, fn.Pos(), "", &err)
 
 	// Generate the coroutine function. At this stage, use the same name
 	// as the source function (and require that the caller use build tags
 	// to disambiguate function calls).
 	fnType := funcTypeWithNamedResults(p, fn)
+	name := funcDeclName(p, fn)
+	body, report, err := scope.compileFuncBody(p, name, fnType, fn.Body, fn.Recv, color)
+	if err != nil {
+		return nil, err
+	}
+	scope.addReport(report, name, fn.Pos(), false)
 	gen := &ast.FuncDecl{
 		Recv: fn.Recv,
 		Doc:  &ast.CommentGroup{},
 		Name: fn.Name,
 		Type: fnType,
-		Body: scope.compileFuncBody(p, fnType, fn.Body, fn.Recv, color),
+		Body: body,
 	}
 
 	if color != nil && !isExpr(gen.Body) {
 		scope.colors[gen] = color
 	}
-	return gen
+	return gen, nil
 }
 
-func (scope *scope) compileFuncLit(p *packages.Package, fn *ast.FuncLit, color *types.Signature) *ast.FuncLit {
+func (scope *scope) compileFuncLit(p *packages.Package, fn *ast.FuncLit, color *types.Signature) (*ast.FuncLit, error) {
+	body, report, err := scope.compileFuncBody(p, scope.funcName, fn.Type, fn.Body, nil, color)
+	if err != nil {
+		return nil, err
+	}
+	scope.addReport(report, scope.funcName, fn.Pos(), true)
 	gen := &ast.FuncLit{
 		Type: funcTypeWithNamedResults(p, fn),
-		Body: scope.compileFuncBody(p, fn.Type, fn.Body, nil, color),
+		Body: body,
+	}
+	if digest, ok := scope.digests[fn]; ok {
+		scope.digests[gen] = digest
 	}
 
 	p.TypesInfo.Types[gen] = types.TypeAndValue{Type: p.TypesInfo.TypeOf(fn)}
@@ -576,30 +972,54 @@ func (scope *scope) compileFuncLit(p *packages.Package, fn *ast.FuncLit, color *
 	if !isExpr(gen.Body) {
 		scope.colors[gen] = color
 	}
-	return gen
+	return gen, nil
+}
+
+// compileColoredFuncLit compiles a function literal at the cursor if it is
+// colored.
+func (scope *scope) compileColoredFuncLit(p *packages.Package, cursor *astutil.Cursor, lit *ast.FuncLit) error {
+	color, ok := scope.colors[lit]
+	if !ok {
+		return nil
+	}
+	gen, err := scope.compileFuncLit(p, lit, color)
+	if err != nil {
+		return err
+	}
+	cursor.Replace(gen)
+	return nil
 }
 
-func (scope *scope) compileFuncBody(p *packages.Package, typ *ast.FuncType, body *ast.BlockStmt, recv *ast.FieldList, color *types.Signature) *ast.BlockStmt {
+func (scope *scope) compileFuncBody(p *packages.Package, name string, typ *ast.FuncType, body *ast.BlockStmt, recv *ast.FieldList, color *types.Signature) (*ast.BlockStmt, *FunctionReport, error) {
 	// If the function itself doesn't yield, but it contains a function
 	// literal that does yield, take a slightly different approach.
 	if color == nil {
-		return scope.compileFuncWrapperBody(p, typ, body, recv)
+		body, err := scope.compileFuncWrapperBody(p, typ, body, recv)
+		return body, nil, err
 	}
 
+	digest := functionDigest(typ, body)
+
 	var defers *ast.Ident
 
+	shared := scope.prepareLoopVars(body, p.TypesInfo)
+
 	mayYield := findCalls(body, p.TypesInfo)
 	markBranchStmt(body, mayYield)
 
-	body = desugar(p, body, mayYield).(*ast.BlockStmt)
+	desugared, err := desugar(p, body, mayYield, scope.lines)
+	if err != nil {
+		return nil, nil, err
+	}
+	body = desugared.(*ast.BlockStmt)
 	body = astutil.Apply(body,
 		func(cursor *astutil.Cursor) bool {
+			if err != nil {
+				return false
+			}
 			switch n := cursor.Node().(type) {
 			case *ast.FuncLit:
-				color, ok := scope.colors[n]
-				if ok {
-					cursor.Replace(scope.compileFuncLit(p, n, color))
-				}
+				err = scope.compileColoredFuncLit(p, cursor, n)
 				return false
 			case *ast.DeferStmt:
 				if defers == nil {
@@ -627,9 +1047,12 @@ func (scope *scope) compileFuncBody(p *packages.Package, typ *ast.FuncType, body
 		},
 		nil,
 	).(*ast.BlockStmt)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	if isExpr(body) {
-		return body
+		return body, nil, nil
 	}
 
 	gen := new(ast.BlockStmt)
@@ -677,8 +1100,18 @@ func (scope *scope) compileFuncBody(p *packages.Package, typ *ast.FuncType, body
 	// declarations to the function prologue. We downgrade inline var decls and
 	// assignments that use := to assignments that use =. Constant decls are
 	// hoisted and also have their value assigned in the function prologue.
-	decls, frameType, frameInit := extractDecls(p, typ, body, recv, defers, p.TypesInfo)
-	renameObjects(typ, body, p.TypesInfo, decls, frameName, frameType, frameInit, scope)
+	//
+	// Variables which are never live across a yield point are declared as
+	// ordinary local variables rather than being stored in the frame.
+	//
+	// Variables declared in loops which escape the iteration are stored in
+	// cells, so that each iteration has its own instance of the variables.
+	locals := localVars(body, p.TypesInfo)
+	cells := loopCells(body, p.TypesInfo, shared)
+	decls, frameType, frameInit := extractDecls(p, typ, body, recv, defers, locals, cells, p.TypesInfo)
+	frameVars := frameObjects(frameType, p.TypesInfo)
+	cellFields := renameObjects(typ, body, p.TypesInfo, decls, frameName, frameType, frameInit, cells, scope)
+	scope.addFrame(frameType)
 
 	// var _f{n} F = coroutine.Push[F](&_c.Stack)
 	gen.List = append(gen.List, &ast.DeclStmt{Decl: &ast.GenDecl{
@@ -764,7 +1197,20 @@ func (scope *scope) compileFuncBody(p *packages.Package, typ *ast.FuncType, body
 
 	spans := trackDispatchSpans(body)
 	mayYield = findCalls(body, p.TypesInfo)
+
+	yieldPoints := 0
+	for node := range mayYield {
+		if _, ok := node.(*ast.CallExpr); ok {
+			yieldPoints++
+		}
+	}
+	span := spans[body]
+	report := newFunctionReport(p, color, frameType, frameVars, cells, span.end-span.start, yieldPoints)
+	report.Version = functionVersion(digest, report.Frame)
+	tagFrame(frameType, name, report.Version)
+
 	compiledBody := compileDispatch(body, frameName, spans, mayYield).(*ast.BlockStmt)
+	scope.bindCells(p, compiledBody, frameName, cellFields)
 	gen.List = append(gen.List, compiledBody.List...)
 
 	// If the function returns one or more values, it must end with a return
@@ -783,30 +1229,52 @@ func (scope *scope) compileFuncBody(p *packages.Package, typ *ast.FuncType, body
 		}
 	}
 
-	return gen
+	return gen, report, nil
+}
+
+// prepareLoopVars prepares the loops of a function body for the compilation of
+// their variables. When loops declare variables for each iteration, the
+// variables of for clauses are copied to the loop bodies; otherwise, the
+// variables of range clauses are returned, which are shared by all the
+// iterations even after being moved to loop bodies by desugaring.
+func (scope *scope) prepareLoopVars(body *ast.BlockStmt, info *types.Info) map[types.Object]struct{} {
+	if scope.perIterationLoopVars {
+		copyLoopVars(body, info)
+		return nil
+	}
+	return rangeLoopVars(body, info)
 }
 
-func (scope *scope) compileFuncWrapperBody(p *packages.Package, typ *ast.FuncType, body *ast.BlockStmt, recv *ast.FieldList) *ast.BlockStmt {
+func (scope *scope) compileFuncWrapperBody(p *packages.Package, typ *ast.FuncType, body *ast.BlockStmt, recv *ast.FieldList) (*ast.BlockStmt, error) {
 	frameName := ast.NewIdent(fmt.Sprintf("_f%d", scope.frameIndex))
 	scope.frameIndex++
 
 	renameFuncRecvParamsResults(typ, recv, body, p.TypesInfo)
 
-	decls, frameType, frameInit := extractDecls(p, typ, body, recv, nil, p.TypesInfo)
-	renameObjects(typ, body, p.TypesInfo, decls, frameName, frameType, frameInit, scope)
+	shared := scope.prepareLoopVars(body, p.TypesInfo)
+	cells := loopCells(body, p.TypesInfo, shared)
+	decls, frameType, frameInit := extractDecls(p, typ, body, recv, nil, nil, cells, p.TypesInfo)
+	cellFields := renameObjects(typ, body, p.TypesInfo, decls, frameName, frameType, frameInit, cells, scope)
+	scope.addFrame(frameType)
 
+	var err error
 	body = astutil.Apply(body,
 		func(cursor *astutil.Cursor) bool {
+			if err != nil {
+				return false
+			}
 			if lit, ok := cursor.Node().(*ast.FuncLit); ok {
-				if color, ok := scope.colors[lit]; ok {
-					cursor.Replace(scope.compileFuncLit(p, lit, color))
-				}
+				err = scope.compileColoredFuncLit(p, cursor, lit)
 				return false
 			}
 			return true
 		},
 		nil,
 	).(*ast.BlockStmt)
+	if err != nil {
+		return nil, err
+	}
+	scope.bindCells(p, body, frameName, cellFields)
 
 	gen := new(ast.BlockStmt)
 	for _, decl := range decls {
@@ -822,7 +1290,7 @@ func (scope *scope) compileFuncWrapperBody(p *packages.Package, typ *ast.FuncTyp
 	}})
 	gen.List = append(gen.List, body.List...)
 
-	return gen
+	return gen, nil
 }
 
 func panicCall(s string) ast.Expr {
@@ -0,0 +1,359 @@
+package compiler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestBuild(t *testing.T) {
+	dir, err := filepath.Abs("testdata/build")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name    string
+		options []Option
+		files   []string
+	}{
+		{
+			name:    "linux",
+			options: []Option{GOOS("linux")},
+			files:   []string{"build.go", "build_durable.go"},
+		},
+		{
+			name:    "linux and windows",
+			options: []Option{GOOS("linux", "windows")},
+			files:   []string{"build.go", "build_durable.go", "build_windows.go", "build_windows_durable.go"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			before := readDir(t, dir)
+
+			res, err := Build(dir, test.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Diagnostics) > 0 {
+				t.Fatal(res.Diagnostics)
+			}
+			if after := readDir(t, dir); !reflect.DeepEqual(after, before) {
+				t.Errorf("files were written by Build")
+			}
+
+			var files []string
+			for _, f := range res.Files {
+				files = append(files, filepath.Base(f.Path))
+			}
+			if !slices.Equal(files, test.files) {
+				t.Fatalf("unexpected files: got %v, want %v", files, test.files)
+			}
+
+			for _, f := range res.Files {
+				expect := before[filepath.Base(f.Path)].content
+				if !bytes.Equal(f.Content, expect) {
+					t.Errorf("unexpected content of %s:\n%s\nexpect:\n%s", f.Path, f.Content, expect)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildDeterministic(t *testing.T) {
+	dir, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The instances of generic functions share their syntax, the output
+	// must not depend on which instance the compiler sees first.
+	var results [2]*Result
+	for i := range results {
+		if results[i], err = Build(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files0, files1 := results[0].Files, results[1].Files
+	if len(files0) != len(files1) {
+		t.Fatalf("builds generated %d and %d files", len(files0), len(files1))
+	}
+	for i := range files0 {
+		if files0[i].Path != files1[i].Path {
+			t.Errorf("builds generated files %s and %s", files0[i].Path, files1[i].Path)
+		} else if !bytes.Equal(files0[i].Content, files1[i].Content) {
+			t.Errorf("builds generated different content for %s", files0[i].Path)
+		}
+	}
+}
+
+type fileState struct {
+	content []byte
+	modTime time.Time
+}
+
+// readDir returns the content and modification time of the files of a
+// directory, to check that they were not modified.
+func readDir(t *testing.T, dir string) map[string]fileState {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]fileState{}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = fileState{content, info.ModTime()}
+	}
+	return files
+}
+
+func TestBuildPlatforms(t *testing.T) {
+	dir, err := filepath.Abs("testdata/platform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	goos := []string{"darwin", "linux", "windows"}
+
+	res, err := Build(dir, GOOS(goos...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Diagnostics) > 0 {
+		t.Fatal(res.Diagnostics)
+	}
+
+	overlay := map[string][]byte{}
+	for _, f := range res.Files {
+		overlay[f.Path] = f.Content
+	}
+	for _, o := range goos {
+		t.Run(o, func(t *testing.T) {
+			pkgs, err := packages.Load(&packages.Config{
+				Mode:       packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedImports,
+				Dir:        dir,
+				Env:        append(os.Environ(), "GOOS="+o),
+				BuildFlags: []string{"-tags=durable"},
+				Overlay:    overlay,
+			}, ".")
+			if err != nil {
+				t.Fatal(err)
+			}
+			packages.Visit(pkgs, nil, func(p *packages.Package) {
+				for _, err := range p.Errors {
+					t.Error(err)
+				}
+			})
+
+			var files []string
+			for _, p := range pkgs {
+				for _, f := range p.GoFiles {
+					files = append(files, filepath.Base(f))
+				}
+			}
+			name := "name_" + o + "_durable.go"
+			if o == "darwin" {
+				name = "name_other_durable.go"
+			}
+			want := []string{name, "platform_durable.go"}
+			if !slices.Equal(files, want) {
+				t.Errorf("unexpected files: got %v, want %v", files, want)
+			}
+		})
+	}
+}
+
+func TestBuildCache(t *testing.T) {
+	dir, err := filepath.Abs("testdata/build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cacheDir := t.TempDir()
+
+	res1, err := Build(dir, Cache(cacheDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("nothing was written to the cache")
+	}
+
+	var logs bytes.Buffer
+	res2, err := Build(dir, Cache(cacheDir), Logger(log.New(&logs, "", 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logs.String(), "using cached result") {
+		t.Errorf("cache was not used:\n%s", logs.String())
+	}
+	if !reflect.DeepEqual(res1, res2) {
+		t.Errorf("cached result differs from the compiled one")
+	}
+}
+
+func TestCacheSourceHash(t *testing.T) {
+	// The source file was compiled already, so its constraint is !durable.
+	compiled, err := os.ReadFile("testdata/build/build.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := bytes.Replace(compiled, []byte("//go:build !durable\n\n"), nil, 1)
+	edited := bytes.Replace(source, []byte("package build"), []byte("package build\n\nvar _ = 0"), 1)
+
+	dir := t.TempDir()
+	c := &compiler{buildTag: "durable", cache: newCache(t.TempDir())}
+	hash := func(name string, src []byte) [sha256.Size]byte {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, src, 0644); err != nil {
+			t.Fatal(err)
+		}
+		sum, err := c.sourceHash(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sum
+	}
+
+	if hash("source.go", source) != hash("compiled.go", compiled) {
+		t.Error("compiling the source file changed its hash")
+	}
+	if hash("source.go", source) == hash("edited.go", edited) {
+		t.Error("editing the source file did not change its hash")
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	dir, err := filepath.Abs("testdata/build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Build(dir, GOOS("linux"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Functions) != 1 {
+		t.Fatalf("expected 1 function, got %d", len(res.Functions))
+	}
+
+	const name = "github.com/dispatchrun/coroutine/compiler/testdata/build.Count"
+	want := FunctionReport{
+		Package: "github.com/dispatchrun/coroutine/compiler/testdata/build",
+		Name:    name,
+		File:    "compiler/testdata/build/build.go",
+		Line:    7,
+		Yield:   "int",
+		Send:    "any",
+		Frame: []FrameField{
+			{Name: "IP", Type: "int", Size: 8},
+			{Name: "X0", Var: "n", Type: "int", Size: 8},
+			{Name: "X1", Var: "i", Type: "int", Size: 8},
+		},
+		FrameSize:   24,
+		IPs:         2,
+		YieldPoints: 1,
+		Registered:  []RegisteredFunc{{Name: name}},
+	}
+	got := res.Functions[0]
+	if len(got.Version) != 16 {
+		t.Errorf("unexpected version: %q", got.Version)
+	}
+	want.Version = got.Version
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected report:\ngot  %+v\nwant %+v", got, want)
+	}
+
+	durable, err := os.ReadFile(filepath.Join(dir, "build_durable.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag := fmt.Sprintf("`frame:\"%s@%s\"`", name, got.Version); !bytes.Contains(durable, []byte(tag)) {
+		t.Errorf("frame is not tagged with %s", tag)
+	}
+}
+
+func TestBuildTag(t *testing.T) {
+	dir, err := filepath.Abs("testdata/build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	durable, err := os.ReadFile(filepath.Join(dir, "build_durable.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name    string
+		options []Option
+		output  string
+	}{
+		{
+			name:    "tag",
+			options: []Option{BuildTag("workflow")},
+			output:  "build_workflow.go",
+		},
+		{
+			name:    "tag and suffix",
+			options: []Option{BuildTag("workflow"), FileSuffix(".workflow.go")},
+			output:  "build.workflow.go",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := Build(dir, append(test.options, GOOS("linux"))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Files) != 2 {
+				t.Fatalf("expected 2 files, got %d", len(res.Files))
+			}
+
+			src, gen := res.Files[0], res.Files[1]
+			if filepath.Base(src.Path) != "build.go" || filepath.Base(gen.Path) != test.output {
+				t.Fatalf("unexpected files: %s, %s", src.Path, gen.Path)
+			}
+			if want := "//go:build !durable && !workflow\n"; !bytes.HasPrefix(src.Content, []byte(want)) {
+				t.Errorf("unexpected build constraint of %s:\n%s", src.Path, src.Content)
+			}
+
+			// The source file was compiled with the default build tag
+			// already, so !durable is part of its constraint, which the
+			// generated file carries over.
+			expect := bytes.Replace(durable, []byte("//go:build durable\n"), []byte("//go:build !durable && workflow\n"), 1)
+			expect = bytes.ReplaceAll(expect, []byte("build_durable.go"), []byte(test.output))
+			if !bytes.Equal(gen.Content, expect) {
+				t.Errorf("unexpected content of %s:\n%s\nexpect:\n%s", gen.Path, gen.Content, expect)
+			}
+		})
+	}
+
+	for _, options := range [][]Option{
+		{BuildTag("a && b")},
+		{BuildTag("coroutine.durable")},
+		{BuildTag("workflow"), BuildTags("durable")},
+		{FileSuffix("_test.go")},
+		{FileSuffix(".txt")},
+	} {
+		if _, err := Build(dir, options...); err == nil {
+			t.Error("expected an error for invalid build tag or file suffix")
+		}
+	}
+}
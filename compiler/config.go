@@ -0,0 +1,188 @@
+package compiler
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"go/build"
+	"go/build/constraint"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	// defaultBuildTag is the build tag that selects the durable
+	// implementation of the coroutine package, and the generated files
+	// unless another tag is configured with the BuildTag option.
+	defaultBuildTag = "durable"
+
+	// runtimeBuildTag selects the durable implementation of the coroutine
+	// package without selecting the files generated with the default tag,
+	// for programs generated with other tags.
+	runtimeBuildTag = "coroutine.durable"
+)
+
+// initBuildTag applies the defaults of the build tag and of the suffix of
+// generated files, and validates them.
+func (c *compiler) initBuildTag() error {
+	if c.buildTag == "" {
+		c.buildTag = defaultBuildTag
+	}
+	if expr, err := constraint.Parse("//go:build " + c.buildTag); err != nil {
+		return fmt.Errorf("invalid build tag %q: %w", c.buildTag, err)
+	} else if _, ok := expr.(*constraint.TagExpr); !ok {
+		return fmt.Errorf("invalid build tag %q: not a single tag", c.buildTag)
+	}
+	if c.buildTag == runtimeBuildTag {
+		return fmt.Errorf("invalid build tag %q: reserved by the coroutine package", c.buildTag)
+	}
+	for _, tag := range []string{c.buildTag, defaultBuildTag, runtimeBuildTag} {
+		if slices.Contains(c.buildTags, tag) {
+			return fmt.Errorf("build tag %q cannot be satisfied while loading packages", tag)
+		}
+	}
+
+	if c.fileSuffix == "" {
+		c.fileSuffix = "_" + c.buildTag + ".go"
+	}
+	if !strings.HasSuffix(c.fileSuffix, ".go") || c.fileSuffix == ".go" || strings.HasSuffix(c.fileSuffix, "_test.go") {
+		return fmt.Errorf("invalid generated file suffix %q", c.fileSuffix)
+	}
+	return nil
+}
+
+func (c *compiler) buildTagExpr() *constraint.TagExpr {
+	return &constraint.TagExpr{Tag: c.buildTag}
+}
+
+// buildConfig is a build configuration that the compiler loads packages
+// with. Empty fields inherit the value from the environment.
+type buildConfig struct {
+	goos   string
+	goarch string
+}
+
+func (b buildConfig) String() string {
+	return cmp.Or(b.goos, build.Default.GOOS) + "/" + cmp.Or(b.goarch, build.Default.GOARCH)
+}
+
+func (b buildConfig) env() []string {
+	env := os.Environ()
+	if b.goos != "" {
+		env = append(env, "GOOS="+b.goos)
+	}
+	if b.goarch != "" {
+		env = append(env, "GOARCH="+b.goarch)
+	}
+	return env
+}
+
+// buildConfigs returns the build configurations that the module must be
+// compiled for, which are all the combinations of the GOOS and GOARCH values
+// passed to the compiler.
+func (c *compiler) buildConfigs() []buildConfig {
+	goos := c.goos
+	if len(goos) == 0 {
+		goos = []string{""}
+	}
+	goarch := c.goarch
+	if len(goarch) == 0 {
+		goarch = []string{""}
+	}
+	var configs []buildConfig
+	for _, o := range goos {
+		for _, arch := range goarch {
+			configs = append(configs, buildConfig{goos: o, goarch: arch})
+		}
+	}
+	return configs
+}
+
+func (c *compiler) buildFlags() []string {
+	if len(c.buildTags) == 0 {
+		return nil
+	}
+	return []string{"-tags=" + strings.Join(c.buildTags, ",")}
+}
+
+// mergeFileLists returns the sorted list of unique file paths of results.
+func mergeFileLists(results []*Result) []string {
+	var paths []string
+	for _, res := range results {
+		for _, f := range res.Files {
+			paths = append(paths, f.Path)
+		}
+	}
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
+
+// mergeResults combines the results of compiling a module for multiple build
+// configurations.
+//
+// Files which are part of several build configurations must be compiled to
+// the same code in each of them, since the durable build tag doesn't
+// discriminate between configurations; this may not be the case when the
+// functions are colored differently, for example because a function called
+// from the file only yields on some platforms.
+func mergeResults(configs []buildConfig, results []*Result) (*Result, error) {
+	if len(results) == 1 {
+		return results[0], nil
+	}
+
+	type origin struct{ file, config int }
+
+	merged := new(Result)
+	files := map[string]origin{}
+	diags := map[string]struct{}{}
+	type function struct {
+		pkg, name, file string
+		line            int
+		literal         bool
+	}
+	functions := map[function]struct{}{}
+
+	for i, res := range results {
+		for _, diag := range res.Diagnostics {
+			if _, ok := diags[diag.Error()]; !ok {
+				diags[diag.Error()] = struct{}{}
+				merged.Diagnostics = append(merged.Diagnostics, diag)
+			}
+		}
+		for _, f := range res.Files {
+			o, ok := files[f.Path]
+			if !ok {
+				files[f.Path] = origin{file: len(merged.Files), config: i}
+				merged.Files = append(merged.Files, f)
+				continue
+			}
+			if !bytes.Equal(merged.Files[o.file].Content, f.Content) {
+				return nil, fmt.Errorf("%s: compiled differently for build configurations %s and %s", f.Path, configs[o.config], configs[i])
+			}
+		}
+		for _, fn := range res.Functions {
+			key := function{fn.Package, fn.Name, fn.File, fn.Line, fn.Literal}
+			if _, ok := functions[key]; !ok {
+				functions[key] = struct{}{}
+				merged.Functions = append(merged.Functions, fn)
+			}
+		}
+		if res.GOROOT != "" {
+			merged.GOROOT = res.GOROOT
+		}
+	}
+
+	if len(merged.Diagnostics) > 0 {
+		merged.Diagnostics.sort()
+		merged.Files = nil
+		merged.Functions = nil
+		merged.GOROOT = ""
+		return merged, nil
+	}
+	slices.SortFunc(merged.Files, func(a, b File) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+	sortFunctionReports(merged.Functions)
+	return merged, nil
+}
@@ -3,8 +3,10 @@ package compiler
 import (
 	"go/ast"
 	"go/build/constraint"
+	"path/filepath"
 	"reflect"
 	"slices"
+	"strings"
 )
 
 func containsExpr(expr, contains constraint.Expr) bool {
@@ -39,6 +41,30 @@ func withoutBuildTag(expr constraint.Expr, buildTag *constraint.TagExpr) constra
 	}
 }
 
+// withoutNotBuildTag reverts withoutBuildTag, removing the !buildTag term
+// from the top-level conjunction of expr.
+func withoutNotBuildTag(expr constraint.Expr, buildTag *constraint.TagExpr) constraint.Expr {
+	notBuildTag := &constraint.NotExpr{X: buildTag}
+	switch x := expr.(type) {
+	case *constraint.AndExpr:
+		lhs := withoutNotBuildTag(x.X, buildTag)
+		rhs := withoutNotBuildTag(x.Y, buildTag)
+		switch {
+		case lhs == nil:
+			return rhs
+		case rhs == nil:
+			return lhs
+		default:
+			return &constraint.AndExpr{X: lhs, Y: rhs}
+		}
+	default:
+		if reflect.DeepEqual(expr, notBuildTag) {
+			return nil
+		}
+		return expr
+	}
+}
+
 func parseBuildTags(file *ast.File) (constraint.Expr, error) {
 	groups := commentGroupsOf(file)
 
@@ -77,3 +103,79 @@ func stripBuildTagsOf(file *ast.File, path string) {
 		})
 	}
 }
+
+// fileNameTags returns the GOOS and GOARCH build tags implied by the name of a
+// source file, like windows and amd64 for foo_windows_amd64.go, following the
+// rules of the go/build package.
+//
+// Generated files don't keep these suffixes at the end of their name, so the
+// tags must be added to their build constraint instead.
+func fileNameTags(path string) []*constraint.TagExpr {
+	name, _, _ := strings.Cut(filepath.Base(path), ".")
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return nil
+	}
+	l := strings.Split(name[i:], "_")
+	if n := len(l); n > 0 && l[n-1] == "test" {
+		l = l[:n-1]
+	}
+	n := len(l)
+	if n >= 2 && knownOS[l[n-2]] && knownArch[l[n-1]] {
+		return []*constraint.TagExpr{{Tag: l[n-2]}, {Tag: l[n-1]}}
+	}
+	if n >= 1 && (knownOS[l[n-1]] || knownArch[l[n-1]]) {
+		return []*constraint.TagExpr{{Tag: l[n-1]}}
+	}
+	return nil
+}
+
+// knownOS and knownArch are the values of GOOS and GOARCH that go/build
+// recognizes in file names.
+var knownOS = map[string]bool{
+	"aix":       true,
+	"android":   true,
+	"darwin":    true,
+	"dragonfly": true,
+	"freebsd":   true,
+	"hurd":      true,
+	"illumos":   true,
+	"ios":       true,
+	"js":        true,
+	"linux":     true,
+	"nacl":      true,
+	"netbsd":    true,
+	"openbsd":   true,
+	"plan9":     true,
+	"solaris":   true,
+	"wasip1":    true,
+	"windows":   true,
+	"zos":       true,
+}
+
+var knownArch = map[string]bool{
+	"386":         true,
+	"amd64":       true,
+	"amd64p32":    true,
+	"arm":         true,
+	"armbe":       true,
+	"arm64":       true,
+	"arm64be":     true,
+	"loong64":     true,
+	"mips":        true,
+	"mipsle":      true,
+	"mips64":      true,
+	"mips64le":    true,
+	"mips64p32":   true,
+	"mips64p32le": true,
+	"ppc":         true,
+	"ppc64":       true,
+	"ppc64le":     true,
+	"riscv":       true,
+	"riscv64":     true,
+	"s390":        true,
+	"s390x":       true,
+	"sparc":       true,
+	"sparc64":     true,
+	"wasm":        true,
+}
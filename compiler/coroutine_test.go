@@ -1,13 +1,16 @@
 package compiler
 
 import (
+	"errors"
 	"math"
 	"reflect"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/dispatchrun/coroutine"
 	. "github.com/dispatchrun/coroutine/compiler/testdata"
+	coroutinev1 "github.com/dispatchrun/coroutine/gen/proto/go/coroutine/v1"
 	"github.com/dispatchrun/coroutine/types"
 )
 
@@ -263,6 +266,18 @@ func TestCoroutineYield(t *testing.T) {
 			yields: []int{0, 1, 2},
 		},
 
+		{
+			name:   "loop variables captured by closures",
+			coro:   func() { LoopVarClosures(3) },
+			yields: []int{0, 1, 2, 0, 10, 20, 0, 2, 0, 1, 2, 0, 1, 2, 0, 10, 20},
+		},
+
+		{
+			name:   "loop variables shared before go1.22",
+			coro:   func() { LoopVarSharedClosures(3) },
+			yields: []int{0, 1, 2, 0, 10, 20, 3, 3, 3, 2, 2, 2, 0, 0, 10, 0, 20, 0},
+		},
+
 		{
 			name: "reflect type",
 			coro: func() {
@@ -306,6 +321,30 @@ func TestCoroutineYield(t *testing.T) {
 			coro:   func() { GenericSlice(3) },
 			yields: []int{0, 1, 2, 0, 1, 2},
 		},
+
+		{
+			name:   "noyield directive",
+			coro:   func() { NoYieldDirective(4) },
+			yields: []int{1, 3, 6, 10},
+		},
+
+		{
+			name:   "yields directive",
+			coro:   func() { YieldsDirective(3) },
+			yields: []int{0, 2, 4},
+		},
+
+		{
+			name:   "channel buffer",
+			coro:   func() { ChannelBuffer(4) },
+			yields: []int{1, 4, 9, 16},
+		},
+
+		{
+			name:   "transient field",
+			coro:   func() { TransientField(4) },
+			yields: []int{1, 4, 9, 16},
+		},
 	}
 
 	// This emulates the installation of function type information by the
@@ -359,8 +398,16 @@ func TestCoroutineYield(t *testing.T) {
 					t.Fatal(err)
 				}
 
+				// Every other state is restored with symbolic resolution
+				// of functions and types, which must produce the same
+				// coroutine.
+				var options []coroutine.UnmarshalOption
+				if yield%2 == 0 {
+					options = append(options, coroutine.CrossBuild())
+				}
+
 				reconstructed := coroutine.New[int, any](test.coro)
-				if err := reconstructed.Context().Unmarshal(b); err != nil {
+				if err := reconstructed.Context().Unmarshal(b, options...); err != nil {
 					t.Fatal(err)
 				}
 				g = reconstructed
@@ -377,6 +424,89 @@ func TestCoroutineYield(t *testing.T) {
 	}
 }
 
+func TestCoroutineMigration(t *testing.T) {
+	if !coroutine.Durable {
+		t.Skip("coroutine states can only be migrated in durable mode")
+	}
+	const name = "github.com/dispatchrun/coroutine/compiler/testdata.SquareGenerator"
+
+	entry := func() { SquareGenerator(4) }
+	types.RegisterFunc[func()](types.FuncByAddr(types.FuncAddr(entry)).Name)
+
+	g := coroutine.New[int, any](entry)
+	if !g.Next() || g.Recv() != 1 {
+		t.Fatal("coroutine did not yield")
+	}
+	b, err := g.Context().Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pretend that the state was created by another version of the function.
+	withVersion := func(version string) []byte {
+		var state coroutinev1.State
+		if err := state.UnmarshalVT(b); err != nil {
+			t.Fatal(err)
+		}
+		state.Build.Id = "other"
+		for _, typ := range state.Types {
+			for _, f := range typ.Fields {
+				if strings.HasPrefix(f.Tag, `frame:"`+name+"@") {
+					f.Tag = `frame:"` + name + "@" + version + `"`
+				}
+			}
+		}
+		b, err := state.MarshalVT()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	// Frames of versions without a migration cannot be resumed.
+	g = coroutine.New[int, any](entry)
+	if err := g.Context().Unmarshal(withVersion("unknown"), coroutine.CrossBuild()); !errors.Is(err, coroutine.ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState for a frame without migration, got %v", err)
+	}
+
+	b = withVersion("old")
+	coroutine.RegisterMigration(name, "old", func(old *coroutine.OldFrame, frame any) (int, error) {
+		if old.Function != name || old.Version != "old" {
+			t.Errorf("unexpected frame: %s@%s", old.Function, old.Version)
+		}
+		fields := map[string]int{}
+		s := old.Region.Scan()
+		for s.Next() {
+			if f := s.Field(); f != nil {
+				fields[f.Name()] = s.Int()
+			}
+		}
+		if err := s.Close(); err != nil {
+			return 0, err
+		}
+		if fields["IP"] != old.IP {
+			t.Errorf("unexpected instruction pointer: got %d, want %d", old.IP, fields["IP"])
+		}
+		// Skip one iteration of the loop.
+		f := reflect.ValueOf(frame).Elem()
+		f.FieldByName("X0").SetInt(int64(fields["X0"]))
+		f.FieldByName("X1").SetInt(int64(fields["X1"] + 1))
+		return old.IP, nil
+	})
+
+	g = coroutine.New[int, any](entry)
+	if err := g.Context().Unmarshal(b, coroutine.CrossBuild()); err != nil {
+		t.Fatal(err)
+	}
+	var values []int
+	for g.Next() {
+		values = append(values, g.Recv())
+	}
+	if !slices.Equal(values, []int{9, 16}) {
+		t.Errorf("wrong values yield by migrated coroutine: %#v", values)
+	}
+}
+
 func TestCoroutineStop(t *testing.T) {
 	coro := coroutine.New[int, any](func() { SquareGenerator(4) })
 
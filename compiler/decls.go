@@ -20,10 +20,16 @@ import (
 // The declaration order is preserved in case types refer to constants and vice
 // versa.
 //
+// Variables in the locals set are not stored in the frame; a var declaration
+// is returned for each of them instead, after the type and const declarations.
+//
+// Variables in the cells set are stored in cells which are allocated when the
+// variable is declared (see loopCells), the frame holds a pointer to the cell.
+//
 // Note that declarations are extracted from all nested scopes within the
 // function body, so there may be duplicate identifiers. Identifiers can be
 // disambiguated using (*types.Info).ObjectOf(ident).
-func extractDecls(p *packages.Package, typ *ast.FuncType, body *ast.BlockStmt, recv *ast.FieldList, defers *ast.Ident, info *types.Info) (decls []*ast.GenDecl, frameType *ast.StructType, frameInit *ast.CompositeLit) {
+func extractDecls(p *packages.Package, typ *ast.FuncType, body *ast.BlockStmt, recv *ast.FieldList, defers *ast.Ident, locals, cells map[types.Object]struct{}, info *types.Info) (decls []*ast.GenDecl, frameType *ast.StructType, frameInit *ast.CompositeLit) {
 	IP := &ast.Field{
 		Names: []*ast.Ident{ast.NewIdent("IP")},
 		Type:  ast.NewIdent("int"),
@@ -83,6 +89,27 @@ func extractDecls(p *packages.Package, typ *ast.FuncType, body *ast.BlockStmt, r
 		}
 	}
 
+	var localDecls []*ast.GenDecl
+	addVar := func(name *ast.Ident, typ ast.Expr) {
+		if _, ok := cells[info.ObjectOf(name)]; ok {
+			typ = &ast.StarExpr{X: typ}
+		}
+		if _, ok := locals[info.ObjectOf(name)]; ok {
+			localDecls = append(localDecls, &ast.GenDecl{
+				Tok: token.VAR,
+				Specs: []ast.Spec{&ast.ValueSpec{
+					Names: []*ast.Ident{name},
+					Type:  typ,
+				}},
+			})
+		} else {
+			frameType.Fields.List = append(frameType.Fields.List, &ast.Field{
+				Names: []*ast.Ident{name},
+				Type:  typ,
+			})
+		}
+	}
+
 	ast.Inspect(body, func(node ast.Node) bool {
 		switch n := node.(type) {
 		case *ast.FuncLit:
@@ -99,10 +126,7 @@ func extractDecls(p *packages.Package, typ *ast.FuncType, body *ast.BlockStmt, r
 					valueType := typeExpr(p, info.TypeOf(valueSpec.Names[0]), nil)
 					for _, ident := range valueSpec.Names {
 						if ident.Name != "_" {
-							frameType.Fields.List = append(frameType.Fields.List, &ast.Field{
-								Names: []*ast.Ident{ident},
-								Type:  valueType,
-							})
+							addVar(ident, valueType)
 						}
 					}
 				}
@@ -130,14 +154,12 @@ func extractDecls(p *packages.Package, typ *ast.FuncType, body *ast.BlockStmt, r
 					// AssignStmt that declares it.
 					continue
 				}
-				frameType.Fields.List = append(frameType.Fields.List, &ast.Field{
-					Names: []*ast.Ident{name},
-					Type:  typeExpr(p, t, nil),
-				})
+				addVar(name, typeExpr(p, t, nil))
 			}
 		}
 		return true
 	})
+	decls = append(decls, localDecls...)
 
 	if defers != nil {
 		frameType.Fields.List = append(frameType.Fields.List, &ast.Field{
@@ -152,10 +174,15 @@ func extractDecls(p *packages.Package, typ *ast.FuncType, body *ast.BlockStmt, r
 // renameObjects renames types, constants and variables declared within
 // a function. Each is given a unique name, so that declarations are safe
 // to hoist into the function prologue.
-func renameObjects(fntype *ast.FuncType, tree ast.Node, info *types.Info, decls []*ast.GenDecl, frameName *ast.Ident, frameType *ast.StructType, frameInit *ast.CompositeLit, scope *scope) {
+//
+// The variables of the cells set are dereferenced, and their cells are
+// allocated where they are declared. The types of the cells stored in the
+// frame are returned, indexed by field name.
+func renameObjects(fntype *ast.FuncType, tree ast.Node, info *types.Info, decls []*ast.GenDecl, frameName *ast.Ident, frameType *ast.StructType, frameInit *ast.CompositeLit, cells map[types.Object]struct{}, scope *scope) (cellFields map[string]types.Type) {
 	// Scan decls to find objects, giving each new object a unique name.
 	names := make(map[types.Object]*ast.Ident, len(decls))
 	selectors := make(map[types.Object]*ast.SelectorExpr, len(frameType.Fields.List))
+	cellTypes := make(map[types.Object]ast.Expr, len(cells))
 
 	generateUniqueIdent := func() *ast.Ident {
 		ident := scope.objectIndex
@@ -169,7 +196,11 @@ func renameObjects(fntype *ast.FuncType, tree ast.Node, info *types.Info, decls
 			newIdent := generateUniqueIdent()
 			names[obj] = newIdent
 			// Add type info for the new identifiers.
-			info.Defs[newIdent] = types.NewVar(0, nil, ident.Name, obj.Type())
+			t := obj.Type()
+			if _, ok := cells[obj]; ok {
+				t = types.NewPointer(t)
+			}
+			info.Defs[newIdent] = types.NewVar(0, nil, ident.Name, t)
 		}
 	}
 
@@ -181,6 +212,9 @@ func renameObjects(fntype *ast.FuncType, tree ast.Node, info *types.Info, decls
 			case *ast.ValueSpec: // const/var
 				for _, name := range s.Names {
 					addName(name)
+					if _, ok := cells[info.ObjectOf(name)]; ok {
+						cellTypes[info.ObjectOf(name)] = s.Type.(*ast.StarExpr).X
+					}
 				}
 			}
 		}
@@ -211,6 +245,14 @@ func renameObjects(fntype *ast.FuncType, tree ast.Node, info *types.Info, decls
 					expr.Key = newIdent
 				}
 
+				if _, ok := cells[obj]; ok {
+					cellTypes[obj] = field.Type.(*ast.StarExpr).X
+					if cellFields == nil {
+						cellFields = map[string]types.Type{}
+					}
+					cellFields[newIdent.Name] = obj.Type()
+				}
+
 				index++
 			}
 		}
@@ -240,6 +282,31 @@ func renameObjects(fntype *ast.FuncType, tree ast.Node, info *types.Info, decls
 	// Note that replacing identifiers is a recursive operation which traverses
 	// function literals.
 
+	// slot returns the expression of the frame field or local variable that
+	// holds an object after renaming.
+	slot := func(obj types.Object) ast.Expr {
+		if selector, ok := selectors[obj]; ok {
+			return selector
+		}
+		return names[obj]
+	}
+
+	// newCells returns assignments allocating the cells of the variables
+	// declared by a list of identifiers.
+	newCells := func(idents []*ast.Ident) (stmts []ast.Stmt) {
+		for _, ident := range idents {
+			obj := info.ObjectOf(ident)
+			if t, ok := cellTypes[obj]; ok {
+				stmts = append(stmts, &ast.AssignStmt{
+					Lhs: []ast.Expr{slot(obj)},
+					Tok: token.ASSIGN,
+					Rhs: []ast.Expr{newCell(t, info)},
+				})
+			}
+		}
+		return stmts
+	}
+
 	astutil.Apply(tree,
 		func(cursor *astutil.Cursor) bool {
 			switch n := cursor.Node().(type) {
@@ -257,7 +324,11 @@ func renameObjects(fntype *ast.FuncType, tree ast.Node, info *types.Info, decls
 					var assigns []ast.Stmt
 					for _, spec := range decl.Specs {
 						s, ok := spec.(*ast.ValueSpec)
-						if !ok || len(s.Values) == 0 {
+						if !ok {
+							continue
+						}
+						assigns = append(assigns, newCells(s.Names)...)
+						if len(s.Values) == 0 {
 							continue
 						}
 						lhs := make([]ast.Expr, len(s.Names))
@@ -285,6 +356,14 @@ func renameObjects(fntype *ast.FuncType, tree ast.Node, info *types.Info, decls
 						return true // preserve type switch decls.
 					}
 					n.Tok = token.ASSIGN // otherwise, convert := to =
+
+					var idents []*ast.Ident
+					for _, lhs := range n.Lhs {
+						idents = append(idents, lhs.(*ast.Ident))
+					}
+					for _, stmt := range newCells(idents) {
+						cursor.InsertBefore(stmt)
+					}
 				}
 			}
 			return true
@@ -297,7 +376,9 @@ func renameObjects(fntype *ast.FuncType, tree ast.Node, info *types.Info, decls
 			switch n := cursor.Node().(type) {
 			case *ast.Ident:
 				if obj := info.ObjectOf(n); obj != nil {
-					if selector, ok := selectors[obj]; ok {
+					if _, ok := cells[obj]; ok {
+						cursor.Replace(&ast.StarExpr{X: slot(obj)})
+					} else if selector, ok := selectors[obj]; ok {
 						cursor.Replace(selector)
 					} else if ident, ok := names[obj]; ok {
 						cursor.Replace(ident)
@@ -309,6 +390,22 @@ func renameObjects(fntype *ast.FuncType, tree ast.Node, info *types.Info, decls
 		nil,
 	)
 
+	// Declarations of variables which are not stored in the frame are not
+	// part of the tree, their names are replaced separately.
+	for _, decl := range decls {
+		if decl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range decl.Specs {
+			s := spec.(*ast.ValueSpec)
+			for i, name := range s.Names {
+				if ident, ok := names[info.ObjectOf(name)]; ok {
+					s.Names[i] = ident
+				}
+			}
+		}
+	}
+
 	astutil.Apply(tree,
 		func(cursor *astutil.Cursor) bool {
 			switch n := cursor.Node().(type) {
@@ -355,6 +452,8 @@ func renameObjects(fntype *ast.FuncType, tree ast.Node, info *types.Info, decls
 				return true
 			}, nil)
 	}
+
+	return cellFields
 }
 
 func hasNamedResults(t *ast.FuncType) bool {
@@ -40,8 +40,15 @@ import (
 // types.Info. If this gets unruly in the future, desugaring should be
 // performed after parsing AST's but before type checking so that this is
 // done automatically by the type checker.
-func desugar(p *packages.Package, stmt ast.Stmt, mayYield map[ast.Node]struct{}) ast.Stmt {
-	d := desugarer{pkg: p, info: p.TypesInfo, nodesThatMayYield: mayYield}
+//
+// The position of the original statements are recorded in lines (if not nil)
+// for the statements generated by the pass, so that //line directives can map
+// them back to the source code.
+//
+// Constructs that cannot be desugared are reported as a *Diagnostic error.
+func desugar(p *packages.Package, stmt ast.Stmt, mayYield map[ast.Node]struct{}, lines *lineMarkers) (_ ast.Stmt, err error) {
+	d := desugarer{pkg: p, info: p.TypesInfo, nodesThatMayYield: mayYield, lines: lines}
+	defer recoverDiagnostic(p.Fset, stmt.Pos(), "", &err)
 	stmt = d.desugar(stmt, nil, nil, nil)
 
 	// Unused labels cause a compile error (label X defined and not used)
@@ -53,7 +60,13 @@ func desugar(p *packages.Package, stmt ast.Stmt, mayYield map[ast.Node]struct{})
 		return true
 	}, nil)
 
-	return stmt
+	return stmt, nil
+}
+
+// errorf reports a construct that cannot be desugared. It unwinds the
+// recursive desugaring pass, and is converted to an error by desugar.
+func (d *desugarer) errorf(node ast.Node, format string, args ...any) {
+	panic(&compileError{pos: node.Pos(), msg: fmt.Sprintf(format, args...)})
 }
 
 type desugarer struct {
@@ -64,12 +77,15 @@ type desugarer struct {
 	nodesThatMayYield map[ast.Node]struct{}
 	unusedLabels      map[*ast.Ident]struct{}
 	userLabels        map[types.Object]*ast.Ident
+	lines             *lineMarkers
 }
 
 func (d *desugarer) desugar(stmt ast.Stmt, breakTo, continueTo, userLabel *ast.Ident) ast.Stmt {
 	if !d.mayYield(stmt) {
 		return stmt
 	}
+	orig := stmt
+	defer func() { d.lines.setPosition(stmt, orig) }()
 
 	switch s := stmt.(type) {
 	case nil:
@@ -85,7 +101,7 @@ func (d *desugarer) desugar(stmt ast.Stmt, breakTo, continueTo, userLabel *ast.I
 	case *ast.IncDecStmt:
 
 	case *ast.BadStmt:
-		panic("bad stmt")
+		d.errorf(s, "bad stmt")
 
 	case *ast.BlockStmt:
 		stmt = &ast.BlockStmt{List: d.desugarList(s.List, breakTo, continueTo)}
@@ -94,7 +110,7 @@ func (d *desugarer) desugar(stmt ast.Stmt, breakTo, continueTo, userLabel *ast.I
 		if s.Label != nil {
 			label := d.getUserLabel(s.Label)
 			if label == nil {
-				panic(fmt.Sprintf("label not found: %s", s.Label))
+				d.errorf(s, "label not found: %s", s.Label)
 			}
 			d.useLabel(label)
 			stmt = &ast.BranchStmt{Tok: s.Tok, Label: label}
@@ -107,7 +123,7 @@ func (d *desugarer) desugar(stmt ast.Stmt, breakTo, continueTo, userLabel *ast.I
 				d.useLabel(continueTo)
 				stmt = &ast.BranchStmt{Tok: token.CONTINUE, Label: continueTo}
 			default: // FALLTHROUGH / GOTO
-				panic("not implemented")
+				d.errorf(s, "not implemented: %s", s.Tok)
 			}
 		}
 
@@ -205,7 +221,7 @@ func (d *desugarer) desugar(stmt ast.Stmt, breakTo, continueTo, userLabel *ast.I
 		}
 
 	case *ast.GoStmt:
-		panic("not implemented")
+		d.errorf(s, "not implemented: go")
 
 	case *ast.IfStmt:
 		// Rewrite `if init; cond { ... }` => `{ init; _cond := cond; if _cond { ... } }`
@@ -246,8 +262,6 @@ func (d *desugarer) desugar(stmt ast.Stmt, breakTo, continueTo, userLabel *ast.I
 		}
 		prologue := d.desugarList([]ast.Stmt{init}, nil, nil)
 
-		intType := types.Typ[types.Int]
-
 		switch rangeElemType := d.info.TypeOf(s.X).(type) {
 		case *types.Basic:
 			switch rangeElemType.Kind() {
@@ -256,12 +270,7 @@ func (d *desugarer) desugar(stmt ast.Stmt, breakTo, continueTo, userLabel *ast.I
 				// - `for range x {}` => `{ _x := x; for _i := 0; _i < _x; _i++ {} }`
 				// - `for _ := range x {}` => `{ _x := x; for _i := 0; _i < _x; _i++ {} }`
 				// - `for i := range x {}` => `{ _x := x; for i := 0; i < _x; i++ {} }`
-				var i *ast.Ident
-				if s.Key == nil || isUnderscore(s.Key) {
-					i = d.newVar(intType)
-				} else {
-					i = s.Key.(*ast.Ident)
-				}
+				i := d.rangeIndex(s)
 				forStmt := &ast.ForStmt{
 					Init: &ast.AssignStmt{Lhs: []ast.Expr{i}, Tok: token.DEFINE, Rhs: []ast.Expr{&ast.BasicLit{Kind: token.INT, Value: "0"}}},
 					Post: &ast.IncDecStmt{X: i, Tok: token.INC},
@@ -276,7 +285,7 @@ func (d *desugarer) desugar(stmt ast.Stmt, breakTo, continueTo, userLabel *ast.I
 				}
 
 			default:
-				panic(fmt.Sprintf("not implemented: for range over %T", rangeElemType))
+				d.errorf(s, "not implemented: for range over %T", rangeElemType)
 			}
 
 		case *types.Array, *types.Slice:
@@ -289,12 +298,7 @@ func (d *desugarer) desugar(stmt ast.Stmt, breakTo, continueTo, userLabel *ast.I
 			// - `for i, v := range x {}` => `{ _x := x; for i := 0; i < len(_x); i++ { v := _x[i]; ... } }`
 			// - `for _, v := range x {}` => `{ _x := x; for _i := 0; _i < len(_x); _i++ { v := _x[_i]; ... } }`
 			// Then, desugar loops further (see ast.ForStmt case above).
-			var i *ast.Ident
-			if s.Key == nil || isUnderscore(s.Key) {
-				i = d.newVar(intType)
-			} else {
-				i = s.Key.(*ast.Ident)
-			}
+			i := d.rangeIndex(s)
 			if s.Value != nil && !isUnderscore(s.Value) {
 				s.Body.List = append([]ast.Stmt{
 					&ast.AssignStmt{Lhs: []ast.Expr{s.Value}, Tok: token.DEFINE, Rhs: []ast.Expr{&ast.IndexExpr{X: x, Index: i}}},
@@ -414,7 +418,7 @@ func (d *desugarer) desugar(stmt ast.Stmt, breakTo, continueTo, userLabel *ast.I
 				stmt = &ast.BlockStmt{List: append(prologue, collectKeys, iterKeys)}
 			}
 		default:
-			panic(fmt.Sprintf("not implemented: for range over %T", s.X))
+			d.errorf(s, "not implemented: for range over %T", s.X)
 		}
 
 	case *ast.SelectStmt:
@@ -474,7 +478,7 @@ func (d *desugarer) desugar(stmt ast.Stmt, breakTo, continueTo, userLabel *ast.I
 			case *ast.ExprStmt:
 				recv := m.X.(*ast.UnaryExpr)
 				if recv.Op != token.ARROW {
-					panic("unexpected select case")
+					d.errorf(m, "unexpected select case")
 				}
 				tmpRecv := d.newVar(d.info.TypeOf(recv.X))
 				assignRecv := &ast.AssignStmt{Lhs: []ast.Expr{tmpRecv}, Tok: token.DEFINE, Rhs: []ast.Expr{recv.X}}
@@ -485,11 +489,11 @@ func (d *desugarer) desugar(stmt ast.Stmt, breakTo, continueTo, userLabel *ast.I
 				recv.X = tmpRecv
 			case *ast.AssignStmt:
 				if len(m.Rhs) != 1 {
-					panic("unexpected select case")
+					d.errorf(m, "unexpected select case")
 				}
 				recv := m.Rhs[0].(*ast.UnaryExpr)
 				if recv.Op != token.ARROW {
-					panic("unexpected select case")
+					d.errorf(m, "unexpected select case")
 				}
 				tmpRecv := d.newVar(d.info.TypeOf(recv.X))
 				assignRecv := &ast.AssignStmt{Lhs: []ast.Expr{tmpRecv}, Tok: token.DEFINE, Rhs: []ast.Expr{recv.X}}
@@ -526,7 +530,7 @@ func (d *desugarer) desugar(stmt ast.Stmt, breakTo, continueTo, userLabel *ast.I
 				switchBodyCase.Body = append(caseBodyAssigns, switchBodyCase.Body...)
 				m.Tok = token.ASSIGN
 			default:
-				panic(fmt.Sprintf("unexpected select case %T", m))
+				d.errorf(m, "unexpected select case %T", m)
 			}
 
 			rawSelect.Body.List[i] = &ast.CommClause{
@@ -713,7 +717,7 @@ func (d *desugarer) desugar(stmt ast.Stmt, breakTo, continueTo, userLabel *ast.I
 		}
 
 	default:
-		panic(fmt.Sprintf("unsupported ast.Stmt: %T", stmt))
+		d.errorf(stmt, "unsupported ast.Stmt: %T", stmt)
 	}
 	return stmt
 }
@@ -730,6 +734,15 @@ func (d *desugarer) desugarList(stmts []ast.Stmt, breakTo, continueTo *ast.Ident
 }
 
 func (d *desugarer) flatMap(stmt ast.Stmt) (result []ast.Stmt) {
+	// Expressions of the statement are replaced below, record its position
+	// before they are.
+	pos := d.lines.pos(stmt)
+	defer func() {
+		for _, s := range result {
+			d.lines.setPos(s, pos)
+		}
+	}()
+
 	var prereqs []ast.Stmt
 	switch s := stmt.(type) {
 	case *ast.AssignStmt:
@@ -836,7 +849,7 @@ func (d *desugarer) decomposeExpression(expr ast.Expr, flags exprFlags) (ast.Exp
 	for i := 0; i < len(queue); i++ {
 		switch e := queue[i].(type) {
 		case *ast.BadExpr:
-			panic("bad expr")
+			d.errorf(e, "bad expr")
 
 		case *ast.BinaryExpr:
 			e.X = decompose(e.X)
@@ -915,7 +928,7 @@ func (d *desugarer) decomposeExpression(expr ast.Expr, flags exprFlags) (ast.Exp
 			e.X = decompose(e.X)
 
 		default:
-			panic(fmt.Sprintf("unsupported ast.Expr: %T", queue[i]))
+			d.errorf(queue[i], "unsupported ast.Expr: %T", queue[i])
 		}
 	}
 	prereqs := make([]ast.Stmt, len(tmps))
@@ -953,6 +966,30 @@ func (d *desugarer) newVar(t types.Type) *ast.Ident {
 	return v
 }
 
+// rangeIndex returns the index variable of a for range loop over an int, an
+// array or a slice. When the key is captured by a function literal or its
+// address is taken in the body, it is declared at the beginning of the body
+// instead, so that each iteration has its own instance of the key:
+//
+//	for i := range x { ... } => for _i := 0; _i < ...; _i++ { i := _i; ... }
+func (d *desugarer) rangeIndex(s *ast.RangeStmt) *ast.Ident {
+	if s.Key == nil || isUnderscore(s.Key) {
+		return d.newVar(types.Typ[types.Int])
+	}
+	key := s.Key.(*ast.Ident)
+	if s.Tok != token.DEFINE {
+		return key
+	}
+	if _, ok := escapingVars(s.Body, d.info)[d.info.Defs[key]]; !ok {
+		return key
+	}
+	i := d.newVar(types.Typ[types.Int])
+	s.Body.List = append([]ast.Stmt{
+		&ast.AssignStmt{Lhs: []ast.Expr{key}, Tok: token.DEFINE, Rhs: []ast.Expr{i}},
+	}, s.Body.List...)
+	return i
+}
+
 func (d *desugarer) newLabel() *ast.Ident {
 	l := ast.NewIdent("_l" + strconv.Itoa(d.labels))
 	d.labels++
@@ -2,6 +2,7 @@ package compiler
 
 import (
 	"bytes"
+	"errors"
 	"go/ast"
 	"go/format"
 	"go/parser"
@@ -1247,7 +1248,10 @@ defer func() {
 			})
 
 			p := &packages.Package{TypesInfo: info}
-			desugared := desugar(p, body, mayYield)
+			desugared, err := desugar(p, body, mayYield, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
 			desugared = unnestBlocks(desugared)
 
 			expect := strings.TrimSpace(test.expect)
@@ -1261,6 +1265,33 @@ defer func() {
 	}
 }
 
+func TestDesugarError(t *testing.T) {
+	expr, err := parser.ParseExpr("func() {\ngo foo()\n}()")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := expr.(*ast.CallExpr).Fun.(*ast.FuncLit).Body
+
+	mayYield := map[ast.Node]struct{}{}
+	ast.Inspect(body, func(node ast.Node) bool {
+		if node != nil {
+			mayYield[node] = struct{}{}
+		}
+		return true
+	})
+
+	p := &packages.Package{TypesInfo: &types.Info{}}
+	_, err = desugar(p, body, mayYield, nil)
+
+	var diag *Diagnostic
+	if !errors.As(err, &diag) {
+		t.Fatalf("expected *Diagnostic error, got %v", err)
+	}
+	if diag.Message != "not implemented: go" {
+		t.Errorf("unexpected error message: %s", diag.Message)
+	}
+}
+
 func formatNode(node ast.Node) string {
 	fset := token.NewFileSet()
 	// ast.Print(fset, node)
@@ -0,0 +1,175 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+)
+
+const (
+	// noYieldDirective asserts that a function, or calls to an interface
+	// method, never yield. It prunes the callgraph used to color functions.
+	noYieldDirective = "//coroutine:noyield"
+
+	// yieldsDirective asserts that a function, or calls to an interface
+	// method, may yield. It forces coloring of functions when the callgraph
+	// isn't able to find the path to the yield point.
+	yieldsDirective = "//coroutine:yields"
+)
+
+// directives are the coloring directives found in comments of function
+// declarations and interface methods.
+//
+// Generic functions and methods are tracked by their origin, and so the
+// directives apply to all their instances.
+type directives struct {
+	noyield map[*types.Func]struct{}
+	yields  map[*types.Func]struct{}
+}
+
+func (d *directives) isNoYield(fn *ssa.Function) bool {
+	obj := funcObject(fn)
+	if obj == nil {
+		return false
+	}
+	_, ok := d.noyield[obj]
+	return ok
+}
+
+func (d *directives) isYields(fn *ssa.Function) bool {
+	obj := funcObject(fn)
+	if obj == nil {
+		return false
+	}
+	_, ok := d.yields[obj]
+	return ok
+}
+
+// isPruned returns true if the edge is a dynamic call to an interface method
+// marked with the //coroutine:noyield directive.
+func (d *directives) isPruned(edge *callgraph.Edge) bool {
+	method := invokedMethod(edge.Site)
+	if method == nil {
+		return false
+	}
+	_, ok := d.noyield[method]
+	return ok
+}
+
+// invokedMethod returns the interface method called by an instruction, or nil
+// if the instruction is not a dynamic call to an interface method.
+func invokedMethod(site ssa.CallInstruction) *types.Func {
+	if site == nil {
+		return nil
+	}
+	common := site.Common()
+	if !common.IsInvoke() {
+		return nil
+	}
+	return common.Method.Origin()
+}
+
+func funcObject(fn *ssa.Function) *types.Func {
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+	obj, _ := fn.Object().(*types.Func)
+	if obj == nil {
+		return nil
+	}
+	return obj.Origin()
+}
+
+func newDirectives() *directives {
+	return &directives{
+		noyield: map[*types.Func]struct{}{},
+		yields:  map[*types.Func]struct{}{},
+	}
+}
+
+// parseDirectives collects the coloring directives of packages that are part
+// of a module.
+func parseDirectives(pkgs []*packages.Package) (*directives, error) {
+	d := newDirectives()
+
+	var err error
+	packages.Visit(pkgs, func(p *packages.Package) bool {
+		if p.Module != nil {
+			err = d.parsePackage(p)
+		}
+		return err == nil
+	}, nil)
+
+	return d, err
+}
+
+func (d *directives) parsePackage(p *packages.Package) (err error) {
+	for _, f := range p.Syntax {
+		ast.Inspect(f, func(node ast.Node) bool {
+			if err != nil {
+				return false
+			}
+			switch n := node.(type) {
+			case *ast.FuncDecl:
+				if obj, ok := p.TypesInfo.Defs[n.Name].(*types.Func); ok {
+					err = d.add(p, obj, n.Doc)
+				}
+			case *ast.InterfaceType:
+				for _, method := range n.Methods.List {
+					if len(method.Names) == 0 {
+						continue // embedded interface
+					}
+					if obj, ok := p.TypesInfo.Defs[method.Names[0]].(*types.Func); ok {
+						if err = d.add(p, obj, method.Doc); err != nil {
+							break
+						}
+					}
+				}
+			}
+			return err == nil
+		})
+		if err != nil {
+			break
+		}
+	}
+	return err
+}
+
+func (d *directives) add(p *packages.Package, obj *types.Func, doc *ast.CommentGroup) error {
+	if doc == nil {
+		return nil
+	}
+	for _, c := range doc.List {
+		text := strings.TrimSpace(c.Text)
+		if !strings.HasPrefix(text, "//coroutine:") {
+			continue
+		}
+		switch text {
+		case noYieldDirective:
+			d.noyield[obj] = struct{}{}
+		case yieldsDirective:
+			d.yields[obj] = struct{}{}
+		default:
+			return &Diagnostic{
+				Pos:      p.Fset.Position(c.Pos()),
+				Function: obj.FullName(),
+				Message:  fmt.Sprintf("unknown directive %s", text),
+			}
+		}
+	}
+	_, noyield := d.noyield[obj]
+	_, yields := d.yields[obj]
+	if noyield && yields {
+		return &Diagnostic{
+			Pos:      p.Fset.Position(obj.Pos()),
+			Function: obj.FullName(),
+			Message:  fmt.Sprintf("cannot be both %s and %s", noYieldDirective, yieldsDirective),
+		}
+	}
+	return nil
+}
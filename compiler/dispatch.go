@@ -112,7 +112,7 @@ func compileDispatch(stmt ast.Stmt, frame *ast.Ident, dispatchSpans map[ast.Stmt
 		}
 		assign, ok := s.Post.(*ast.AssignStmt)
 		if !ok {
-			panic("not implemented")
+			notImplemented(s.Post.Pos(), "for loop post iteration statement %T", s.Post)
 		}
 		if assign.Tok != token.ASSIGN {
 			for i := range assign.Lhs {
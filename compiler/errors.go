@@ -0,0 +1,151 @@
+package compiler
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Diagnostic is an error reported by the compiler at a position in the
+// source code, typically because a coroutine uses a language feature that
+// the compiler does not support.
+type Diagnostic struct {
+	// Pos is the position of the offending construct. It may be invalid
+	// if the construct was generated by the compiler.
+	Pos token.Position
+
+	// Function is the fully qualified name of the function declaration
+	// enclosing the construct.
+	Function string
+
+	// Message describes the problem.
+	Message string
+}
+
+func (d *Diagnostic) Error() string {
+	var b strings.Builder
+	if d.Pos.IsValid() {
+		b.WriteString(d.Pos.String())
+		b.WriteString(": ")
+	}
+	if d.Function != "" {
+		b.WriteString(d.Function)
+		b.WriteString(": ")
+	}
+	b.WriteString(d.Message)
+	return b.String()
+}
+
+// MarshalJSON encodes the diagnostic as a flat JSON object with the file,
+// line, column, function and message fields.
+func (d *Diagnostic) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		File     string `json:"file,omitempty"`
+		Line     int    `json:"line,omitempty"`
+		Column   int    `json:"column,omitempty"`
+		Function string `json:"function,omitempty"`
+		Message  string `json:"message"`
+	}{
+		File:     d.Pos.Filename,
+		Line:     d.Pos.Line,
+		Column:   d.Pos.Column,
+		Function: d.Function,
+		Message:  d.Message,
+	})
+}
+
+// Diagnostics is the error returned by the compiler when it found one or
+// more problems in the source code. Diagnostics are sorted by position.
+type Diagnostics []*Diagnostic
+
+func (d Diagnostics) Error() string {
+	switch len(d) {
+	case 0:
+		return "no diagnostics"
+	case 1:
+		return d[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d errors:", len(d))
+	for _, diag := range d {
+		b.WriteString("\n\t")
+		b.WriteString(diag.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the list of diagnostics, which allows errors.As to extract
+// individual diagnostics.
+func (d Diagnostics) Unwrap() []error {
+	errs := make([]error, len(d))
+	for i, diag := range d {
+		errs[i] = diag
+	}
+	return errs
+}
+
+func (d Diagnostics) sort() {
+	slices.SortStableFunc(d, func(a, b *Diagnostic) int {
+		if c := cmp.Compare(a.Pos.Filename, b.Pos.Filename); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Pos.Line, b.Pos.Line); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Pos.Column, b.Pos.Column)
+	})
+}
+
+// funcDeclName returns the fully qualified name of a function declaration.
+func funcDeclName(p *packages.Package, decl *ast.FuncDecl) string {
+	if obj, ok := p.TypesInfo.Defs[decl.Name].(*types.Func); ok {
+		return obj.FullName()
+	}
+	return p.PkgPath + "." + decl.Name.Name
+}
+
+// compileError is raised by the compilation passes when they encounter a
+// construct they cannot handle, typically deep in a recursive walk over
+// syntax trees or types. It unwinds the pass, and is converted to a
+// *Diagnostic error by recoverDiagnostic.
+type compileError struct {
+	pos token.Pos
+	msg string
+}
+
+// notImplemented raises a compileError for an unsupported construct. The
+// position may be token.NoPos when the construct is not tied to the source
+// code (e.g. a types.Type), in which case the position given to
+// recoverDiagnostic is reported instead.
+func notImplemented(pos token.Pos, format string, args ...any) {
+	panic(&compileError{pos: pos, msg: "not implemented: " + fmt.Sprintf(format, args...)})
+}
+
+// recoverDiagnostic converts a compileError raised by the pass into a
+// *Diagnostic stored in err. It must be called by a deferred statement;
+// other panics are propagated.
+func recoverDiagnostic(fset *token.FileSet, pos token.Pos, function string, err *error) {
+	e := recover()
+	if e == nil {
+		return
+	}
+	cerr, ok := e.(*compileError)
+	if !ok {
+		panic(e)
+	}
+	if cerr.pos.IsValid() {
+		pos = cerr.pos
+	}
+	diag := &Diagnostic{Function: function, Message: cerr.msg}
+	if fset != nil {
+		diag.Pos = fset.Position(pos)
+	}
+	*err = diag
+}
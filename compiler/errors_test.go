@@ -0,0 +1,140 @@
+package compiler
+
+import (
+	"encoding/json"
+	"errors"
+	"go/token"
+	"io"
+	"log"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildDiagnostics(t *testing.T) {
+	dir, err := filepath.Abs("testdata/diagnostics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Build(dir + "/...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Files) > 0 {
+		t.Errorf("files were generated despite diagnostics")
+	}
+
+	const pkg = "github.com/dispatchrun/coroutine/compiler/testdata/diagnostics"
+	file := filepath.Join(dir, "diagnostics.go")
+	subFile := filepath.Join(dir, "sub", "sub.go")
+
+	want := []Diagnostic{
+		{token.Position{Filename: file, Line: 10, Column: 2}, pkg + ".Goto", "not implemented: goto"},
+		{token.Position{Filename: file, Line: 11, Column: 1}, pkg + ".Goto", "not implemented: labels not attached to for/switch/select"},
+		{token.Position{Filename: file, Line: 15, Column: 2}, pkg + ".Go", "not implemented: go"},
+		{token.Position{Filename: file, Line: 17, Column: 2}, pkg + ".Go", "not implemented: go"},
+		{token.Position{Filename: subFile, Line: 9, Column: 3}, pkg + "/sub.Fallthrough", "not implemented: fallthrough"},
+	}
+	if len(res.Diagnostics) != len(want) {
+		t.Fatalf("expected %d diagnostics, got %d: %v", len(want), len(res.Diagnostics), res.Diagnostics)
+	}
+	for i, diag := range res.Diagnostics {
+		got := *diag
+		got.Pos.Offset = 0
+		if got != want[i] {
+			t.Errorf("unexpected diagnostic %d:\ngot  %v\nwant %v", i, &got, &want[i])
+		}
+	}
+
+	// Compile returns the same diagnostics as an error.
+	err = Compile(dir+"/...", Logger(log.New(io.Discard, "", 0)))
+	var diags Diagnostics
+	if !errors.As(err, &diags) {
+		t.Fatalf("expected Diagnostics error, got %v", err)
+	}
+	if len(diags) != len(want) {
+		t.Errorf("expected %d diagnostics, got %d", len(want), len(diags))
+	}
+	var diag *Diagnostic
+	if !errors.As(err, &diag) || diag != diags[0] {
+		t.Errorf("errors.As did not extract the first diagnostic")
+	}
+}
+
+func TestBuildNotImplemented(t *testing.T) {
+	const pkg = "github.com/dispatchrun/coroutine/compiler/testdata/notimplemented"
+
+	for _, test := range []struct {
+		name string
+		want Diagnostic
+	}{
+		{
+			name: "maptype",
+			want: Diagnostic{token.Position{Line: 5, Column: 1}, pkg + "/maptype.Generic", "not implemented: type argument map[string]int"},
+		},
+		{
+			name: "iface",
+			want: Diagnostic{token.Position{Line: 9, Column: 1}, pkg + "/iface.Generic", "not implemented: type argument fmt.Stringer (non-empty interface)"},
+		},
+		{
+			name: "embedded",
+			want: Diagnostic{token.Position{Line: 7, Column: 1}, pkg + "/embedded.Generic", "not implemented: type argument struct{" + pkg + "/embedded.Inner} (embedded field)"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := filepath.Abs(filepath.Join("testdata", "notimplemented", test.name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := Build(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Files) > 0 {
+				t.Errorf("files were generated despite diagnostics")
+			}
+			if len(res.Diagnostics) != 1 {
+				t.Fatalf("expected 1 diagnostic, got %d: %v", len(res.Diagnostics), res.Diagnostics)
+			}
+			got := *res.Diagnostics[0]
+			got.Pos.Offset = 0
+			want := test.want
+			want.Pos.Filename = filepath.Join(dir, test.name+".go")
+			if got != want {
+				t.Errorf("unexpected diagnostic:\ngot  %v\nwant %v", &got, &want)
+			}
+		})
+	}
+}
+
+func TestDiagnosticMarshalJSON(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		diag   *Diagnostic
+		expect string
+	}{
+		{
+			name: "position",
+			diag: &Diagnostic{
+				Pos:      token.Position{Filename: "/src/a.go", Offset: 42, Line: 3, Column: 7},
+				Function: "example.com/a.F",
+				Message:  "not implemented: goto",
+			},
+			expect: `{"file":"/src/a.go","line":3,"column":7,"function":"example.com/a.F","message":"not implemented: goto"}`,
+		},
+		{
+			name:   "no position",
+			diag:   &Diagnostic{Message: "function has more than one color"},
+			expect: `{"message":"function has more than one color"}`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := json.Marshal(test.diag)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != test.expect {
+				t.Errorf("unexpected JSON:\ngot  %s\nwant %s", b, test.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package compiler
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/ssa"
+)
+
+// explainFunction prints the shortest call chain from the named function to
+// one of the yield instances, which is the reason the function was colored.
+//
+// Each edge of the chain is annotated with the algorithm that produced it:
+// calls with a static callee are reported as "static", other (dynamic) calls
+// are attributed to the callgraph construction algorithm.
+func (c *compiler) explainFunction(cg *callgraph.Graph, yieldInstances functionColors, name string) error {
+	var roots []*ssa.Function
+	for fn := range cg.Nodes {
+		if fn != nil && matchFunctionName(fn, name) {
+			roots = append(roots, fn)
+		}
+	}
+	if len(roots) == 0 {
+		return fmt.Errorf("function %s not found in the callgraph", name)
+	}
+	slices.SortFunc(roots, func(a, b *ssa.Function) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	for _, fn := range roots {
+		path := c.shortestYieldPath(cg, yieldInstances, fn)
+		if path == nil {
+			fmt.Fprintf(c.output, "%s does not yield\n", explainFunctionName(fn))
+			continue
+		}
+		fmt.Fprintln(c.output, explainFunctionName(fn))
+		for _, edge := range path {
+			pos := c.prog.Fset.Position(edge.Pos())
+			fmt.Fprintf(c.output, "  -> %s (%s call at %s)\n", explainFunctionName(edge.Callee.Func), c.edgeAlgorithm(edge), pos)
+		}
+	}
+	return nil
+}
+
+// shortestYieldPath performs a breadth-first search of the callgraph from fn
+// and returns the edges leading to the closest yield instance, or nil if no
+// yield instance can be reached.
+//
+// Like colorFunctions, the search doesn't follow edges into and through the
+// coroutine package, nor edges pruned by the //coroutine:noyield directive.
+func (c *compiler) shortestYieldPath(cg *callgraph.Graph, yieldInstances functionColors, fn *ssa.Function) []*callgraph.Edge {
+	root := cg.Nodes[fn]
+	if root == nil {
+		return nil
+	}
+	if _, ok := yieldInstances[fn]; ok {
+		return []*callgraph.Edge{}
+	}
+
+	parents := map[*callgraph.Node]*callgraph.Edge{root: nil}
+	queue := []*callgraph.Node{root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, edge := range node.Out {
+			callee := edge.Callee
+			if _, ok := parents[callee]; ok {
+				continue
+			}
+			if c.directives.isPruned(edge) || c.directives.isNoYield(callee.Func) {
+				continue
+			}
+			parents[callee] = edge
+
+			if _, ok := yieldInstances[callee.Func]; ok {
+				var path []*callgraph.Edge
+				for e := edge; e != nil; e = parents[e.Caller] {
+					path = append(path, e)
+				}
+				slices.Reverse(path)
+				return path
+			}
+			if isCoroutinePackageFunc(callee.Func) {
+				continue
+			}
+			queue = append(queue, callee)
+		}
+	}
+	return nil
+}
+
+// explainFunctionName returns the name of a function. Instances of generic
+// functions are named after their origin followed by their type arguments,
+// like Yield[int, any], since the names that the ssa package gives to
+// instances are not formatted like Go type expressions.
+func explainFunctionName(fn *ssa.Function) string {
+	origin := fn.Origin()
+	if origin == nil {
+		return fn.String()
+	}
+	typeArgs := make([]string, len(fn.TypeArgs()))
+	for i, arg := range fn.TypeArgs() {
+		typeArgs[i] = types.TypeString(arg, nil)
+	}
+	return origin.String() + "[" + strings.Join(typeArgs, ", ") + "]"
+}
+
+func (c *compiler) edgeAlgorithm(edge *callgraph.Edge) string {
+	if edge.Site != nil && edge.Site.Common().StaticCallee() != nil {
+		return "static"
+	}
+	return c.callgraphType
+}
+
+func isCoroutinePackageFunc(fn *ssa.Function) bool {
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+	return fn.Pkg != nil && fn.Pkg.Pkg.Path() == coroutinePackage
+}
+
+// matchFunctionName returns true if name refers to fn. The name can be the
+// fully qualified name of the function (e.g. example.com/pkg.Func or
+// (*example.com/pkg.T).Method), or the same name without the leading import
+// path elements (e.g. pkg.Func or (*pkg.T).Method). Generic functions match
+// all their instances.
+func matchFunctionName(fn *ssa.Function, name string) bool {
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+	fullName := fn.String()
+	if fullName == name {
+		return true
+	}
+	if fn.Pkg == nil {
+		return false
+	}
+	pkgPath := fn.Pkg.Pkg.Path()
+	i := strings.LastIndexByte(pkgPath, '/')
+	if i < 0 {
+		return false
+	}
+	return strings.ReplaceAll(fullName, pkgPath[:i+1], "") == name
+}
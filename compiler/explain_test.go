@@ -0,0 +1,77 @@
+package compiler
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	dir, err := filepath.Abs("testdata/explain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const pkg = "github.com/dispatchrun/coroutine/compiler/testdata/explain"
+
+	for _, test := range []struct {
+		name     string
+		function string
+		options  []Option
+		output   string
+		err      string
+	}{
+		{
+			name:     "indirect call",
+			function: "explain.Main",
+			output: pkg + `.Main
+  -> ` + pkg + `.Run (static call at $DIR/explain.go:20:5)
+  -> (` + pkg + `.yielder).Yield (vta call at $DIR/explain.go:16:9)
+  -> github.com/dispatchrun/coroutine.Yield[int, any] (static call at $DIR/explain.go:12:27)
+`,
+		},
+		{
+			name:     "callgraph algorithm",
+			function: "explain.Run",
+			options:  []Option{CallgraphType("cha")},
+			output: pkg + `.Run
+  -> (` + pkg + `.yielder).Yield (cha call at $DIR/explain.go:16:9)
+  -> github.com/dispatchrun/coroutine.Yield[int, any] (static call at $DIR/explain.go:12:27)
+`,
+		},
+		{
+			name:     "method",
+			function: "(" + pkg + ".yielder).Yield",
+			output: "(" + pkg + `.yielder).Yield
+  -> github.com/dispatchrun/coroutine.Yield[int, any] (static call at $DIR/explain.go:12:27)
+`,
+		},
+		{
+			name:     "no yield",
+			function: "explain.NoYield",
+			output:   pkg + ".NoYield does not yield\n",
+		},
+		{
+			name:     "not found",
+			function: "explain.Missing",
+			err:      "function explain.Missing not found in the callgraph",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var output bytes.Buffer
+			_, err := Build(dir, append(test.options, Explain(test.function), Output(&output))...)
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Fatalf("unexpected error: got %v, want %s", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := strings.ReplaceAll(test.output, "$DIR", dir); output.String() != want {
+				t.Errorf("unexpected output:\n%s\nexpect:\n%s", output.String(), want)
+			}
+		})
+	}
+}
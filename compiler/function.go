@@ -1,6 +1,7 @@
 package compiler
 
 import (
+	"encoding/hex"
 	"fmt"
 	"go/ast"
 	"go/token"
@@ -18,6 +19,8 @@ import (
 type functype struct {
 	signature ast.Expr
 	closure   ast.Expr
+	// Stable identifier of function literals, see closureID.
+	id string
 }
 
 type funcscope struct {
@@ -58,7 +61,7 @@ type funcvar struct {
 	typ   ast.Expr
 }
 
-func collectFunctypes(p *packages.Package, name string, fn ast.Node, scope *funcscope, colors map[ast.Node]*types.Signature, functypes map[string]functype, g *genericInstance) {
+func collectFunctypes(p *packages.Package, name, id string, fn ast.Node, scope *funcscope, colors map[ast.Node]*types.Signature, digests map[*ast.FuncLit]string, functypes map[string]functype, g *genericInstance) {
 	type function struct {
 		node  ast.Node
 		scope *funcscope
@@ -212,6 +215,9 @@ func collectFunctypes(p *packages.Package, name string, fn ast.Node, scope *func
 			Fields: &ast.FieldList{List: fields},
 		}
 	}
+	if id != name {
+		functype.id = id
+	}
 	functypes[name] = functype
 
 	if len(anonFuncs) > 0 {
@@ -224,9 +230,11 @@ func collectFunctypes(p *packages.Package, name string, fn ast.Node, scope *func
 			index = 1
 		}
 
+		ids := map[string]int{}
 		for i, anonFunc := range anonFuncs[index:] {
 			anonFuncName := anonFuncLinkName(name, index+i+1)
-			collectFunctypes(p, anonFuncName, anonFunc.node, anonFunc.scope, colors, functypes, g)
+			anonFuncID := closureID(id, anonFunc.node.(*ast.FuncLit), digests, ids)
+			collectFunctypes(p, anonFuncName, anonFuncID, anonFunc.node, anonFunc.scope, colors, digests, functypes, g)
 		}
 	}
 }
@@ -258,7 +266,7 @@ func functionPath(p *packages.Package, f *ast.FuncDecl) string {
 		case *types.Named:
 			b.WriteString(t.Obj().Name())
 		default:
-			panic(fmt.Sprintf("not implemented: %T", t))
+			notImplemented(f.Recv.Pos(), "method receiver of type %s", recvType)
 		}
 		if isptr {
 			b.WriteByte(')')
@@ -269,37 +277,36 @@ func functionPath(p *packages.Package, f *ast.FuncDecl) string {
 	return b.String()
 }
 
-func (c *compiler) generateFunctypes(p *packages.Package, f *ast.File, colors map[ast.Node]*types.Signature) {
+// generateFunctypes registers the functions and closures declared in a file
+// for serialization, and returns the functions registered for each function
+// declaration.
+//
+// Types that cannot be registered are reported as a *Diagnostic error.
+func (c *compiler) generateFunctypes(p *packages.Package, f *ast.File, colors map[ast.Node]*types.Signature, digests map[*ast.FuncLit]string) (map[*ast.FuncDecl][]RegisteredFunc, error) {
 	functypes := map[string]functype{}
+	registered := map[*ast.FuncDecl][]RegisteredFunc{}
 
 	for _, decl := range f.Decls {
 		switch d := decl.(type) {
 		case *ast.FuncDecl:
-			obj := p.TypesInfo.ObjectOf(d.Name).(*types.Func)
-			fn := c.prog.FuncValue(obj)
-			if fn.TypeParams() != nil {
-				instances := c.generics[fn]
-				if len(instances) == 0 {
-					// This can occur when a generic function is never instantiated/used,
-					// or when it's instantiated in a package not known to the compiler.
-					continue
-				}
-				for _, instance := range instances {
-					g := newGenericInstance(fn, instance)
-					if g.partial() {
-						// Skip instances where not all type params have concrete types.
-						// I'm not sure why these are generated in the SSA program.
-						continue
-					}
-					scope := &funcscope{vars: map[string]*funcvar{}}
-					name := g.gcshapePath()
-					collectFunctypes(p, name, d, scope, colors, functypes, g)
-				}
-			} else {
-				scope := &funcscope{vars: map[string]*funcvar{}}
-				name := functionPath(p, d)
-				collectFunctypes(p, name, d, scope, colors, functypes, nil)
+			declFunctypes, err := c.collectDeclFunctypes(p, d, colors, digests)
+			if err != nil {
+				return nil, err
 			}
+
+			names := make([]string, 0, len(declFunctypes))
+			for name := range declFunctypes {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			for _, name := range names {
+				registered[d] = append(registered[d], RegisteredFunc{
+					Name:    name,
+					ID:      declFunctypes[name].id,
+					Closure: declFunctypes[name].closure != nil,
+				})
+			}
+			maps.Copy(functypes, declFunctypes)
 		}
 	}
 
@@ -343,6 +350,21 @@ func (c *compiler) generateFunctypes(p *packages.Package, f *ast.File, colors ma
 				},
 			},
 		})
+
+		if ft.id != "" {
+			init.List = append(init.List, &ast.ExprStmt{
+				X: &ast.CallExpr{
+					Fun: &ast.SelectorExpr{
+						X:   ast.NewIdent("_types"),
+						Sel: ast.NewIdent("RegisterFuncID"),
+					},
+					Args: []ast.Expr{
+						&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(name)},
+						&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(ft.id)},
+					},
+				},
+			})
+		}
 	}
 
 	if len(init.List) > 0 {
@@ -355,6 +377,41 @@ func (c *compiler) generateFunctypes(p *packages.Package, f *ast.File, colors ma
 				Body: init,
 			})
 	}
+	return registered, nil
+}
+
+// collectDeclFunctypes collects the functions and closures of a function
+// declaration, or of each of its instances if it is generic.
+func (c *compiler) collectDeclFunctypes(p *packages.Package, d *ast.FuncDecl, colors map[ast.Node]*types.Signature, digests map[*ast.FuncLit]string) (_ map[string]functype, err error) {
+	defer recoverDiagnostic(p.Fset, d.Pos(), funcDeclName(p, d), &err)
+
+	functypes := map[string]functype{}
+	obj := p.TypesInfo.ObjectOf(d.Name).(*types.Func)
+	fn := c.prog.FuncValue(obj)
+	if fn.TypeParams() != nil {
+		instances := c.generics[fn]
+		if len(instances) == 0 {
+			// This can occur when a generic function is never instantiated/used,
+			// or when it's instantiated in a package not known to the compiler.
+			return functypes, nil
+		}
+		for _, instance := range instances {
+			g := newGenericInstance(fn, instance)
+			if g.partial() {
+				// Skip instances where not all type params have concrete types.
+				// I'm not sure why these are generated in the SSA program.
+				continue
+			}
+			scope := &funcscope{vars: map[string]*funcvar{}}
+			name := g.gcshapePath()
+			collectFunctypes(p, name, name, d, scope, colors, digests, functypes, g)
+		}
+	} else {
+		scope := &funcscope{vars: map[string]*funcvar{}}
+		name := functionPath(p, d)
+		collectFunctypes(p, name, name, d, scope, colors, digests, functypes, nil)
+	}
+	return functypes, nil
 }
 
 // This function computes the name that the linker gives to anonymous functions,
@@ -366,6 +423,46 @@ func anonFuncLinkName(base string, index int) string {
 	return fmt.Sprintf("%s.func%d", base, index)
 }
 
+// The linker names of closures change when closures are added to or removed
+// from the function before them, so closures are registered with identifiers
+// derived from their syntax as well, which the states of durable coroutines
+// record instead of the linker names.
+//
+// The identifier of a closure has the form <parent>.func-<digest>, where the
+// parent is the identifier of the enclosing function and the digest is that
+// of the syntax of the function literal (see functionDigest). The n-th
+// closure with the same syntax in a function has the suffix -<n>.
+func closureID(parent string, lit *ast.FuncLit, digests map[*ast.FuncLit]string, ids map[string]int) string {
+	digest, ok := digests[lit]
+	if !ok {
+		// The function literal was generated by the compiler.
+		digest = funcLitDigest(lit)
+	}
+	id := fmt.Sprintf("%s.func-%s", parent, digest)
+	ids[id]++
+	if n := ids[id]; n > 1 {
+		id += "-" + strconv.Itoa(n)
+	}
+	return id
+}
+
+// funcLitDigests returns the digests of the function literals of a file, which
+// must be computed before the file is compiled.
+func funcLitDigests(f *ast.File) map[*ast.FuncLit]string {
+	digests := map[*ast.FuncLit]string{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.FuncLit); ok {
+			digests[lit] = funcLitDigest(lit)
+		}
+		return true
+	})
+	return digests
+}
+
+func funcLitDigest(lit *ast.FuncLit) string {
+	return hex.EncodeToString(functionDigest(lit.Type, lit.Body).Sum(nil)[:8])
+}
+
 func functionTypeOf(fn ast.Node) *ast.FuncType {
 	switch f := fn.(type) {
 	case *ast.FuncDecl:
@@ -455,13 +552,13 @@ func newGenericInstance(origin, instance *ssa.Function) *genericInstance {
 			case *types.Named:
 				g.recvType = pt
 			default:
-				panic(fmt.Sprintf("not implemented: %T", t))
+				notImplemented(token.NoPos, "method receiver of type %s", t)
 			}
 
 		case *types.Named:
 			g.recvType = t
 		default:
-			panic(fmt.Sprintf("not implemented: %T", t))
+			notImplemented(token.NoPos, "method receiver of type %s", t)
 		}
 	}
 
@@ -481,7 +578,7 @@ func newGenericInstance(origin, instance *ssa.Function) *genericInstance {
 func (g *genericInstance) typeArgOf(param *types.TypeParam) types.Type {
 	arg, ok := g.typeArgs[param]
 	if !ok {
-		panic(fmt.Sprintf("not type arg found for %s", param))
+		notImplemented(token.NoPos, "type parameter %s without type argument", param)
 	}
 	return arg
 }
@@ -572,7 +669,7 @@ func writeGoShapeType(b *strings.Builder, tt types.Type) {
 		if t.Empty() {
 			b.WriteString("interface{}")
 		} else {
-			panic(fmt.Sprintf("not implemented: %#v (%T)", tt, t))
+			notImplemented(token.NoPos, "type argument %s (non-empty interface)", tt)
 		}
 	case *types.Struct:
 		b.WriteString("struct { ")
@@ -583,7 +680,7 @@ func writeGoShapeType(b *strings.Builder, tt types.Type) {
 			f := t.Field(i)
 
 			if f.Embedded() {
-				panic(fmt.Sprintf("not implemented: %#v (%T)", tt, t))
+				notImplemented(token.NoPos, "type argument %s (embedded field)", tt)
 			}
 			b.WriteString(f.Pkg().Path())
 			b.WriteByte('.')
@@ -596,6 +693,6 @@ func writeGoShapeType(b *strings.Builder, tt types.Type) {
 		b.WriteString("[]")
 		writeGoShapeType(b, t.Elem())
 	default:
-		panic(fmt.Sprintf("not implemented: %#v (%T)", tt, t))
+		notImplemented(token.NoPos, "type argument %s", tt)
 	}
 }
@@ -0,0 +1,55 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestClosureID(t *testing.T) {
+	ids := func(src string) (ids []string) {
+		f, err := parser.ParseFile(token.NewFileSet(), "test.go", src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		digests := funcLitDigests(f)
+		seen := map[string]int{}
+		ast.Inspect(f.Decls[0].(*ast.FuncDecl).Body, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.FuncLit); ok {
+				ids = append(ids, closureID("test.F", lit, digests, seen))
+				return false
+			}
+			return true
+		})
+		return
+	}
+
+	before := ids(`package test
+func F() {
+	a := func() int { return 1 }
+	b := func() int { return 1 }
+	_, _ = a, b
+}`)
+	after := ids(`package test
+func F() {
+	c := func() int { return 2 }
+
+	// The identifiers do not depend on formatting and comments.
+	a := func() int {
+		return 1
+	}
+	b := func() int { return 1 }
+	_, _, _ = a, b, c
+}`)
+
+	if len(before) != 2 || len(after) != 3 {
+		t.Fatalf("unexpected identifiers: %v, %v", before, after)
+	}
+	if before[0] == before[1] {
+		t.Errorf("identical closures have the same identifier %s", before[0])
+	}
+	if before[0] != after[1] || before[1] != after[2] {
+		t.Errorf("identifiers changed when inserting a closure: %v, %v", before, after)
+	}
+}
@@ -0,0 +1,254 @@
+package compiler
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// Line directives map statements of the generated code back to the original
+// source, so that stack traces, profiles and coverage reports refer to code
+// that was written by users rather than to the generated files.
+//
+// Since comments cannot be attached to AST nodes reliably, statements are
+// first preceded by marker statements (identifiers with a reserved prefix)
+// referencing their original position, and markers are then replaced by
+// //line directives when the file is written. Markers for generated
+// statements reset the position to the generated file itself.
+const lineMarkerPrefix = "_coroutine_line_"
+
+// lineMarkers collects the positions referenced by markers inserted in the
+// generated code of a file.
+type lineMarkers struct {
+	fset *token.FileSet
+
+	// Position of the statements recorded by the desugarer (and other
+	// passes) for statements that they generated.
+	positions map[ast.Stmt]token.Pos
+
+	markers []token.Position
+
+	// Number of lines that the compiler added to (or removed from) the
+	// top of the original file when rewriting its build constraints.
+	offset int
+}
+
+func newLineMarkers(fset *token.FileSet) *lineMarkers {
+	return &lineMarkers{fset: fset, positions: map[ast.Stmt]token.Pos{}}
+}
+
+// addLineMarkers inserts markers before each statement of a compiled function.
+// The filename is the path to the file that the function was declared in;
+// positions in other files are ignored.
+func (l *lineMarkers) addLineMarkers(decl *ast.FuncDecl, filename string) {
+	if decl.Body == nil {
+		return
+	}
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.BlockStmt:
+			if !isClauseList(n.List) {
+				n.List = l.mark(n.List, filename)
+			}
+		case *ast.CaseClause:
+			n.Body = l.mark(n.Body, filename)
+		case *ast.CommClause:
+			n.Body = l.mark(n.Body, filename)
+		}
+		return true
+	})
+	// Reset the position at the end of the function so that the following
+	// declarations are not attributed to the original file.
+	decl.Body.List = append(decl.Body.List, l.marker(token.Position{}))
+}
+
+func isClauseList(stmts []ast.Stmt) bool {
+	if len(stmts) == 0 {
+		return false
+	}
+	switch stmts[0].(type) {
+	case *ast.CaseClause, *ast.CommClause:
+		return true
+	}
+	return false
+}
+
+func (l *lineMarkers) mark(stmts []ast.Stmt, filename string) []ast.Stmt {
+	marked := make([]ast.Stmt, 0, 2*len(stmts))
+	for _, stmt := range stmts {
+		if isLineMarker(stmt) {
+			return stmts // already marked
+		}
+		marked = append(marked, l.marker(l.position(stmt, filename)), stmt)
+	}
+	return marked
+}
+
+func (l *lineMarkers) marker(position token.Position) ast.Stmt {
+	id := len(l.markers)
+	l.markers = append(l.markers, position)
+	return &ast.ExprStmt{X: ast.NewIdent(lineMarkerPrefix + strconv.Itoa(id))}
+}
+
+func isLineMarker(stmt ast.Stmt) bool {
+	if s, ok := stmt.(*ast.ExprStmt); ok {
+		if ident, ok := s.X.(*ast.Ident); ok {
+			_, ok := parseLineMarker([]byte(ident.Name))
+			return ok
+		}
+	}
+	return false
+}
+
+// position returns the position of the statement in the original file, or
+// an invalid position if the statement was generated.
+func (l *lineMarkers) position(stmt ast.Stmt, filename string) token.Position {
+	pos := l.pos(stmt)
+	if !pos.IsValid() {
+		return token.Position{}
+	}
+	position := l.fset.Position(pos)
+	if position.Filename != filename {
+		return token.Position{}
+	}
+	return position
+}
+
+// firstPos returns the first valid position found in a statement, without
+// descending into nested statement lists.
+func firstPos(stmt ast.Stmt) (pos token.Pos) {
+	ast.Inspect(stmt, func(node ast.Node) bool {
+		if pos.IsValid() || node == nil {
+			return false
+		}
+		switch node.(type) {
+		case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause, *ast.FuncLit:
+			if node != stmt {
+				return false
+			}
+		}
+		if p := node.Pos(); p.IsValid() {
+			pos = p
+			return false
+		}
+		return true
+	})
+	return
+}
+
+// setPosition records that the generated statement originates from another
+// statement.
+func (l *lineMarkers) setPosition(gen, orig ast.Stmt) {
+	if gen != orig {
+		l.setPos(gen, l.pos(orig))
+	}
+}
+
+// setPos records the original position of a generated statement, unless it
+// was already recorded.
+func (l *lineMarkers) setPos(gen ast.Stmt, pos token.Pos) {
+	if l == nil || !pos.IsValid() {
+		return
+	}
+	if _, ok := l.positions[gen]; !ok {
+		l.positions[gen] = pos
+	}
+}
+
+// pos returns the original position of a statement.
+func (l *lineMarkers) pos(stmt ast.Stmt) token.Pos {
+	if l == nil {
+		return token.NoPos
+	}
+	if pos, ok := l.positions[stmt]; ok {
+		return pos
+	}
+	return firstPos(stmt)
+}
+
+// expand replaces the markers of the source code of a file written to path
+// with //line directives. Redundant directives are omitted.
+//
+// The source code must be formatted, since reformatting after expanding the
+// markers may change the line numbers.
+func (l *lineMarkers) expand(src []byte, path string) []byte {
+	dir := filepath.Dir(path)
+	base := filepath.Base(path)
+
+	lines := bytes.SplitAfter(src, []byte("\n"))
+	out := make([]byte, 0, len(src))
+
+	// The file and line that the next line is attributed to, or empty if
+	// lines are attributed to the generated file itself.
+	var file string
+	var line int
+
+	outLines := 0
+	for _, b := range lines {
+		id, ok := parseLineMarker(bytes.TrimSpace(b))
+		if !ok {
+			// Short function bodies are formatted on a single line, the
+			// markers they contain cannot be turned into directives.
+			b = inlineLineMarkers.ReplaceAll(b, nil)
+			out = append(out, b...)
+			outLines++
+			if file != "" {
+				line++
+			}
+			continue
+		}
+		position := l.markers[id]
+		switch {
+		case !position.IsValid():
+			if file == "" {
+				continue
+			}
+			file = ""
+			outLines++
+			out = fmt.Appendf(out, "//line %s:%d\n", base, outLines+1)
+		case position.Filename == file && position.Line+l.offset == line:
+			continue
+		default:
+			file, line = position.Filename, position.Line+l.offset
+			name := file
+			if rel, err := filepath.Rel(dir, file); err == nil {
+				name = rel
+			}
+			outLines++
+			out = fmt.Appendf(out, "//line %s:%d\n", name, line)
+		}
+	}
+	return out
+}
+
+// setOffset records the shift of line numbers between the original source of
+// a file and the source rewritten by the compiler, which is measured at the
+// package clause.
+func (l *lineMarkers) setOffset(f *ast.File, src []byte) error {
+	fset := token.NewFileSet()
+	rewritten, err := parser.ParseFile(fset, "", src, parser.PackageClauseOnly)
+	if err != nil {
+		return err
+	}
+	l.offset = fset.Position(rewritten.Package).Line - l.fset.Position(f.Package).Line
+	return nil
+}
+
+var inlineLineMarkers = regexp.MustCompile(`(` + lineMarkerPrefix + `[0-9]+; )|(; ` + lineMarkerPrefix + `[0-9]+)`)
+
+func parseLineMarker(b []byte) (int, bool) {
+	s, ok := bytes.CutPrefix(b, []byte(lineMarkerPrefix))
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.Atoi(string(s))
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
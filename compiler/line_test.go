@@ -0,0 +1,76 @@
+package compiler
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestLineMarkersExpand(t *testing.T) {
+	l := &lineMarkers{
+		markers: []token.Position{
+			{Filename: "/src/a.go", Line: 10},
+			{Filename: "/src/a.go", Line: 11},
+			{},
+			{Filename: "/src/a.go", Line: 20},
+			{},
+			{},
+		},
+	}
+
+	src := `package a
+
+func f() {
+	_coroutine_line_0
+	x := 1
+	_coroutine_line_1
+	y := 2
+	_coroutine_line_2
+	_f0.IP = 2
+	_coroutine_line_3
+	g(x, y)
+	_coroutine_line_4
+}
+
+func h() { _coroutine_line_5; g(0, 0) }
+`
+	expect := `package a
+
+func f() {
+//line a.go:10
+	x := 1
+	y := 2
+//line a_durable.go:8
+	_f0.IP = 2
+//line a.go:20
+	g(x, y)
+//line a_durable.go:12
+}
+
+func h() { g(0, 0) }
+`
+
+	if got := string(l.expand([]byte(src), "/src/a_durable.go")); got != expect {
+		t.Errorf("unexpected output:\n%s\nexpect:\n%s", got, expect)
+	}
+}
+
+func TestLineMarkersOffset(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "/src/a.go", "package a\n\nfunc f() {}\n", parser.PackageClauseOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := newLineMarkers(fset)
+	l.markers = []token.Position{{Filename: "/src/a.go", Line: 3}}
+	if err := l.setOffset(f, []byte("//go:build !durable\n\npackage a\n\nfunc f() {}\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	src := "package a\n\nfunc f() {\n\t_coroutine_line_0\n}\n"
+	expect := "package a\n\nfunc f() {\n//line a.go:5\n}\n"
+	if got := string(l.expand([]byte(src), "/src/a_durable.go")); got != expect {
+		t.Errorf("unexpected output:\n%s\nexpect:\n%s", got, expect)
+	}
+}
@@ -0,0 +1,227 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// localVars returns the variables declared in the body of a coroutine which
+// are never live across a yield point, and can therefore be kept as ordinary
+// local variables instead of being stored (and serialized) in the frame.
+//
+// When a coroutine is resumed, the dispatch mechanism jumps back to the
+// statement that yielded, and re-evaluates it. Any variable that may be read
+// by this statement or by the statements that follow it, without having been
+// assigned first, must be restored from the frame.
+//
+// The analysis is structural and conservative: a variable is local if it is
+// defined by a := assignment or a var declaration with values, and if every
+// reference to it appears in the statements that follow its definition in
+// the same block, none of which may yield. Variables that are captured by
+// function literals are always stored in the frame, since the closures may
+// outlive the yield points.
+//
+// Yield points are approximated by calls to functions other than builtins, in
+// the same way as the dispatch mechanism.
+func localVars(body *ast.BlockStmt, info *types.Info) map[types.Object]struct{} {
+	l := &liveness{
+		info:     info,
+		mayYield: findCalls(body, info),
+		lists:    map[ast.Node][]ast.Stmt{},
+		refs:     map[types.Object][]varRef{},
+		captured: map[types.Object]struct{}{},
+		writes:   map[*ast.Ident]struct{}{},
+	}
+	l.node(body)
+
+	locals := map[types.Object]struct{}{}
+	for obj, refs := range l.refs {
+		if _, ok := l.captured[obj]; ok {
+			continue
+		}
+		if l.isLocal(refs) {
+			locals[obj] = struct{}{}
+		}
+	}
+	return locals
+}
+
+type liveness struct {
+	info     *types.Info
+	mayYield map[ast.Node]struct{}
+
+	// Statement lists of blocks, case clauses and comm clauses.
+	lists map[ast.Node][]ast.Stmt
+	// Path of the statement being visited, as a sequence of indexes in
+	// nested statement lists.
+	path []listIndex
+
+	refs     map[types.Object][]varRef
+	captured map[types.Object]struct{}
+	// Identifiers which are assigned, which doesn't count as a use of the
+	// variable.
+	writes map[*ast.Ident]struct{}
+}
+
+type listIndex struct {
+	list  ast.Node
+	index int
+}
+
+type varRef struct {
+	path []listIndex
+	// def is true if the reference is the definition of the variable by a
+	// statement which assigns it a value.
+	def bool
+	// read is true if the reference reads the value of the variable.
+	read bool
+}
+
+func (l *liveness) node(n ast.Node) {
+	ast.Inspect(n, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.BlockStmt:
+			l.list(x, x.List)
+			return false
+		case *ast.CaseClause:
+			for _, expr := range x.List {
+				l.node(expr)
+			}
+			l.list(x, x.Body)
+			return false
+		case *ast.CommClause:
+			if x.Comm != nil {
+				l.node(x.Comm)
+			}
+			l.list(x, x.Body)
+			return false
+		case *ast.FuncLit:
+			ast.Inspect(x.Body, func(node ast.Node) bool {
+				if ident, ok := node.(*ast.Ident); ok {
+					if obj, ok := l.info.ObjectOf(ident).(*types.Var); ok {
+						l.captured[obj] = struct{}{}
+					}
+				}
+				return true
+			})
+			return false
+		case *ast.AssignStmt:
+			for _, lhs := range x.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					l.writes[ident] = struct{}{}
+				}
+			}
+		case *ast.IncDecStmt:
+			if ident, ok := x.X.(*ast.Ident); ok {
+				l.writes[ident] = struct{}{}
+			}
+		case *ast.Ident:
+			l.ref(x)
+		}
+		return true
+	})
+}
+
+func (l *liveness) list(owner ast.Node, stmts []ast.Stmt) {
+	l.lists[owner] = stmts
+	for i, stmt := range stmts {
+		l.path = append(l.path, listIndex{owner, i})
+		l.node(stmt)
+		l.path = l.path[:len(l.path)-1]
+	}
+}
+
+func (l *liveness) ref(ident *ast.Ident) {
+	obj, ok := l.info.ObjectOf(ident).(*types.Var)
+	if !ok || obj.IsField() || len(l.path) == 0 {
+		return
+	}
+	_, write := l.writes[ident]
+	ref := varRef{path: append([]listIndex(nil), l.path...), read: !write}
+	if l.info.Defs[ident] == obj {
+		top := l.path[len(l.path)-1]
+		ref.def = isValueDefinition(l.lists[top.list][top.index], ident)
+	}
+	l.refs[obj] = append(l.refs[obj], ref)
+}
+
+// isValueDefinition returns true if stmt is a := assignment or a var
+// declaration with values defining ident.
+func isValueDefinition(stmt ast.Stmt, ident *ast.Ident) bool {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		if s.Tok != token.DEFINE {
+			return false
+		}
+		for _, lhs := range s.Lhs {
+			if lhs == ident {
+				return true
+			}
+		}
+	case *ast.DeclStmt:
+		g, ok := s.Decl.(*ast.GenDecl)
+		if !ok || g.Tok != token.VAR {
+			return false
+		}
+		for _, spec := range g.Specs {
+			v := spec.(*ast.ValueSpec)
+			for _, name := range v.Names {
+				if name == ident {
+					return len(v.Values) > 0
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (l *liveness) isLocal(refs []varRef) bool {
+	def := refs[0]
+	if !def.def {
+		return false
+	}
+	// Variables that are only assigned would not compile when declared
+	// separately ("declared and not used").
+	read := false
+	for _, ref := range refs[1:] {
+		read = read || ref.read
+	}
+	if !read {
+		return false
+	}
+
+	// Find the innermost statement list containing all the references.
+	depth := 0
+	for ; depth < len(def.path); depth++ {
+		list := def.path[depth].list
+		same := true
+		for _, ref := range refs[1:] {
+			if depth >= len(ref.path) || ref.path[depth].list != list {
+				same = false
+				break
+			}
+		}
+		if !same {
+			break
+		}
+	}
+	// The definition must be a statement of that list, and not be nested in
+	// another statement.
+	if depth != len(def.path) {
+		return false
+	}
+	level := depth - 1
+	first, last := def.path[level].index, def.path[level].index
+	for _, ref := range refs[1:] {
+		last = max(last, ref.path[level].index)
+	}
+
+	stmts := l.lists[def.path[level].list]
+	for _, stmt := range stmts[first+1 : last+1] {
+		if _, ok := l.mayYield[stmt]; ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"testing"
+)
+
+func TestLocalVars(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		body   string
+		locals []string
+	}{
+		{
+			name:   "used before yield",
+			body:   `a := 1; b := a + 1; yield(b)`,
+			locals: []string{"a"},
+		},
+		{
+			name:   "used after yield",
+			body:   `a := 1; yield(0); yield(a)`,
+			locals: nil,
+		},
+		{
+			name:   "defined by yield",
+			body:   `a := yield(0); b := a * 2; _ = b`,
+			locals: []string{"a", "b"},
+		},
+		{
+			name:   "declared without value",
+			body:   `var a int; a = 1; _ = a`,
+			locals: nil,
+		},
+		{
+			name:   "captured",
+			body:   `a := 1; f := func() int { return a }; _ = f`,
+			locals: []string{"f"},
+		},
+		{
+			name:   "loop body",
+			body:   `for i := 0; i < 3; i++ { a := i * 2; b := a; yield(b) }`,
+			locals: []string{"a"},
+		},
+		{
+			name:   "used in nested block after yield",
+			body:   `a := 1; if true { yield(0); yield(a) }`,
+			locals: nil,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			src := "package a\n\nfunc yield(int) int { return 0 }\n\nfunc f() {\n" + test.body + "\n}\n"
+			f, err := parser.ParseFile(fset, "a.go", src, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			info := &types.Info{
+				Defs:  map[*ast.Ident]types.Object{},
+				Uses:  map[*ast.Ident]types.Object{},
+				Types: map[ast.Expr]types.TypeAndValue{},
+			}
+			if _, err := (&types.Config{}).Check("a", fset, []*ast.File{f}, info); err != nil {
+				t.Fatal(err)
+			}
+
+			var locals []string
+			for obj := range localVars(f.Decls[1].(*ast.FuncDecl).Body, info) {
+				locals = append(locals, obj.Name())
+			}
+			slices.Sort(locals)
+
+			if !slices.Equal(locals, test.locals) {
+				t.Errorf("unexpected local variables: got %v, want %v", locals, test.locals)
+			}
+		})
+	}
+}
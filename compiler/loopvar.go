@@ -0,0 +1,451 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"go/version"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+// Since Go 1.22, each iteration of a loop has its own instance of the
+// variables declared by the loop and in its body. The compiler hoists
+// variables to the coroutine frame, where a single field holds the variable
+// for all the iterations. The difference is observable when the variable
+// escapes the iteration, because it is captured by a function literal or
+// because its address is taken.
+//
+// The variables declared in loop bodies which escape are stored in cells,
+// which are allocated each time the declaration is executed. The frame holds
+// a pointer to the cell of the current iteration, and function literals bind
+// the cells when they are created rather than reading them from the frame
+// when they are called. Cells are regular heap allocations, which the
+// serializer shares between the frame and the closures.
+//
+// The variables declared by the init statement of for loops are copied to
+// variables declared at the beginning of the body, and copied back at the
+// end of each iteration, so that they can be handled like the variables
+// declared in the body.
+//
+// Files of an older Go version keep the variables declared by for clauses
+// and range clauses shared by all the iterations, and so does the compiled
+// code; the variables declared in loop bodies are still stored in cells.
+
+// perIterationLoopVars returns true if each iteration of the loops of a file
+// has its own instance of the variables declared by the loop, which depends
+// on the Go version of the file.
+func perIterationLoopVars(p *packages.Package, file *ast.File) bool {
+	return version.Compare(fileGoVersion(p, file), "go1.22") >= 0
+}
+
+// fileGoVersion returns the Go version of a file, which is the version of its
+// module unless the file has a //go:build constraint on the Go version. The
+// rules are the same as the type checker's.
+func fileGoVersion(p *packages.Package, file *ast.File) string {
+	if v := p.TypesInfo.FileVersions[file]; v != "" {
+		return v
+	}
+	if file.GoVersion != "" {
+		return maxVersion(file.GoVersion, "go1.21")
+	}
+	if p.Module != nil && p.Module.GoVersion != "" {
+		return "go" + p.Module.GoVersion
+	}
+	return "go1.16" // the version of modules without a go directive
+}
+
+func maxVersion(a, b string) string {
+	if version.Compare(a, b) < 0 {
+		return b
+	}
+	return a
+}
+
+// rangeLoopVars returns the variables declared by the range clauses of for
+// loops in a tree.
+func rangeLoopVars(tree ast.Node, info *types.Info) map[types.Object]struct{} {
+	vars := map[types.Object]struct{}{}
+	ast.Inspect(tree, func(node ast.Node) bool {
+		if s, ok := node.(*ast.RangeStmt); ok && s.Tok == token.DEFINE {
+			for _, expr := range []ast.Expr{s.Key, s.Value} {
+				if ident, ok := expr.(*ast.Ident); ok {
+					if obj := info.Defs[ident]; obj != nil {
+						vars[obj] = struct{}{}
+					}
+				}
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// escapingVars returns the variables which are captured by function literals
+// or which address is taken in a tree.
+func escapingVars(tree ast.Node, info *types.Info) map[types.Object]struct{} {
+	vars := map[types.Object]struct{}{}
+	escape := func(expr ast.Expr) {
+		if v := addressedVar(expr, info); v != nil {
+			vars[v] = struct{}{}
+		}
+	}
+	ast.Inspect(tree, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			ast.Inspect(n.Body, func(node ast.Node) bool {
+				if ident, ok := node.(*ast.Ident); ok {
+					if v, ok := info.ObjectOf(ident).(*types.Var); ok && !v.IsField() {
+						vars[v] = struct{}{}
+					}
+				}
+				return true
+			})
+			return false
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				escape(n.X)
+			}
+		case *ast.SliceExpr:
+			if t := info.TypeOf(n.X); t != nil {
+				if _, ok := t.Underlying().(*types.Array); ok {
+					escape(n.X)
+				}
+			}
+		case *ast.SelectorExpr:
+			// Calling a method with a pointer receiver on an addressable
+			// value implicitly takes its address.
+			if sel := info.Selections[n]; sel != nil && sel.Kind() == types.MethodVal && !sel.Indirect() {
+				recv := sel.Obj().Type().(*types.Signature).Recv()
+				if _, ok := recv.Type().(*types.Pointer); ok {
+					escape(n.X)
+				}
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// addressedVar returns the variable that holds the value an addressable
+// expression refers to, or nil if the value is not held by a variable.
+func addressedVar(expr ast.Expr, info *types.Info) *types.Var {
+	for {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.Ident:
+			if v, ok := info.ObjectOf(e).(*types.Var); ok && !v.IsField() {
+				return v
+			}
+			return nil
+		case *ast.SelectorExpr:
+			sel := info.Selections[e]
+			if sel == nil || sel.Kind() != types.FieldVal || sel.Indirect() {
+				return nil
+			}
+			expr = e.X
+		case *ast.IndexExpr:
+			t := info.TypeOf(e.X)
+			if t == nil {
+				return nil
+			}
+			if _, ok := t.Underlying().(*types.Array); !ok {
+				return nil
+			}
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
+
+// copyLoopVars rewrites the for loops of a function body which declare
+// variables that escape their body, so that each iteration declares its own
+// copy of the variables:
+//
+//	for i := 0; i < n; i++ { ... }
+//
+// becomes:
+//
+//	for _i := 0; _i < n; _i++ { i := _i; ...; _i = i }
+//
+// The variables are also copied back before continue statements.
+func copyLoopVars(body *ast.BlockStmt, info *types.Info) {
+	astutil.Apply(body, func(cursor *astutil.Cursor) bool {
+		switch n := cursor.Node().(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ForStmt:
+			var label *ast.Ident
+			if s, ok := cursor.Parent().(*ast.LabeledStmt); ok {
+				label = s.Label
+			}
+			copyForStmtVars(n, label, info)
+		}
+		return true
+	}, nil)
+}
+
+func copyForStmtVars(s *ast.ForStmt, label *ast.Ident, info *types.Info) {
+	init, ok := s.Init.(*ast.AssignStmt)
+	if !ok || init.Tok != token.DEFINE {
+		return
+	}
+	escaping := escapingVars(s.Body, info)
+
+	var names []*ast.Ident
+	var vars, copies []types.Object
+	for i, lhs := range init.Lhs {
+		name := lhs.(*ast.Ident)
+		obj := info.Defs[name]
+		if obj == nil {
+			continue
+		}
+		if _, ok := escaping[obj]; !ok {
+			continue
+		}
+		copy := types.NewVar(token.NoPos, obj.Pkg(), name.Name, obj.Type())
+		ident := ast.NewIdent(name.Name)
+		info.Defs[ident] = copy
+		init.Lhs[i] = ident
+
+		for _, node := range []ast.Node{s.Cond, s.Post} {
+			if node != nil {
+				replaceUses(node, obj, copy, info)
+			}
+		}
+		names = append(names, name)
+		vars = append(vars, obj)
+		copies = append(copies, copy)
+	}
+	if len(vars) == 0 {
+		return
+	}
+
+	// The definitions of the variables in the body reuse the identifiers of
+	// the init statement, which hold their type information.
+	defs := &ast.AssignStmt{Tok: token.DEFINE}
+	for i, name := range names {
+		defs.Lhs = append(defs.Lhs, name)
+		defs.Rhs = append(defs.Rhs, useIdent(copies[i], info))
+	}
+	copyBack := func() ast.Stmt {
+		assign := &ast.AssignStmt{Tok: token.ASSIGN}
+		for i, obj := range vars {
+			assign.Lhs = append(assign.Lhs, useIdent(copies[i], info))
+			assign.Rhs = append(assign.Rhs, useIdent(obj, info))
+		}
+		return assign
+	}
+
+	loops := 0
+	astutil.Apply(s.Body, func(cursor *astutil.Cursor) bool {
+		switch n := cursor.Node().(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ForStmt, *ast.RangeStmt:
+			loops++
+		case *ast.BranchStmt:
+			if n.Tok != token.CONTINUE {
+				break
+			}
+			if (n.Label == nil && loops == 0) || (n.Label != nil && label != nil && info.ObjectOf(n.Label) == info.ObjectOf(label)) {
+				cursor.Replace(&ast.BlockStmt{List: []ast.Stmt{copyBack(), n}})
+				return false
+			}
+		}
+		return true
+	}, func(cursor *astutil.Cursor) bool {
+		switch cursor.Node().(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			loops--
+		}
+		return true
+	})
+
+	list := make([]ast.Stmt, 0, len(s.Body.List)+2)
+	list = append(list, defs)
+	list = append(list, s.Body.List...)
+	list = append(list, copyBack())
+	s.Body.List = list
+}
+
+func useIdent(obj types.Object, info *types.Info) *ast.Ident {
+	ident := ast.NewIdent(obj.Name())
+	info.Uses[ident] = obj
+	return ident
+}
+
+// replaceUses replaces the uses of an object in a tree with uses of another.
+func replaceUses(tree ast.Node, obj, with types.Object, info *types.Info) {
+	astutil.Apply(tree, func(cursor *astutil.Cursor) bool {
+		if ident, ok := cursor.Node().(*ast.Ident); ok && info.Uses[ident] == obj {
+			cursor.Replace(useIdent(with, info))
+		}
+		return true
+	}, nil)
+}
+
+// loopCells returns the variables of a function body which must be stored in
+// cells: the variables declared in loop bodies which escape the iteration,
+// except the shared ones, which are the variables of range clauses moved to
+// loop bodies by desugaring in files of older Go versions.
+//
+// Only the variables declared by statements of statement lists are stored in
+// cells, since the compiler inserts the allocation of the cells before the
+// declaration.
+func loopCells(body *ast.BlockStmt, info *types.Info, shared map[types.Object]struct{}) map[types.Object]struct{} {
+	escaping := escapingVars(body, info)
+	cells := map[types.Object]struct{}{}
+	add := func(ident *ast.Ident) {
+		if obj := info.Defs[ident]; obj != nil {
+			_, escapes := escaping[obj]
+			_, isShared := shared[obj]
+			if escapes && !isShared {
+				cells[obj] = struct{}{}
+			}
+		}
+	}
+
+	loops := 0
+	astutil.Apply(body, func(cursor *astutil.Cursor) bool {
+		switch n := cursor.Node().(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ForStmt, *ast.RangeStmt:
+			loops++
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE && loops > 0 && cursor.Index() >= 0 {
+				for _, lhs := range n.Lhs {
+					add(lhs.(*ast.Ident))
+				}
+			}
+		case *ast.DeclStmt:
+			if g, ok := n.Decl.(*ast.GenDecl); ok && g.Tok == token.VAR && loops > 0 && cursor.Index() >= 0 {
+				for _, spec := range g.Specs {
+					for _, name := range spec.(*ast.ValueSpec).Names {
+						add(name)
+					}
+				}
+			}
+		}
+		return true
+	}, func(cursor *astutil.Cursor) bool {
+		switch cursor.Node().(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			loops--
+		}
+		return true
+	})
+	return cells
+}
+
+// newCell returns an expression allocating the cell of a variable of type t.
+func newCell(t ast.Expr, info *types.Info) ast.Expr {
+	fn := ast.NewIdent("new")
+	info.Uses[fn] = types.Universe.Lookup("new")
+	return &ast.CallExpr{Fun: fn, Args: []ast.Expr{t}}
+}
+
+// bindCells makes the function literals of a compiled function body bind the
+// cells of the frame that they refer to when they are created. The cells are
+// the fields of the frame listed in cells, with the types of the variables.
+//
+// The statements creating function literals are wrapped in blocks declaring
+// the bindings, which the closures capture by value:
+//
+//	{
+//		var _o1 *int = _f0.X1
+//		_f0.X2 = func() int { return *_o1 }
+//	}
+func (scope *scope) bindCells(p *packages.Package, body *ast.BlockStmt, frame *ast.Ident, cells map[string]types.Type) {
+	if len(cells) == 0 {
+		return
+	}
+
+	bind := func(stmt ast.Stmt, lits []*ast.FuncLit) ast.Stmt {
+		var specs []ast.Spec
+		bound := map[string]*ast.Ident{}
+		for _, lit := range lits {
+			astutil.Apply(lit.Body, func(cursor *astutil.Cursor) bool {
+				star, ok := cursor.Node().(*ast.StarExpr)
+				if !ok {
+					return true
+				}
+				sel, ok := star.X.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				if x, ok := sel.X.(*ast.Ident); !ok || x.Name != frame.Name {
+					return true
+				}
+				t, ok := cells[sel.Sel.Name]
+				if !ok {
+					return true
+				}
+				ident, ok := bound[sel.Sel.Name]
+				if !ok {
+					ident = ast.NewIdent(fmt.Sprintf("_o%d", scope.objectIndex))
+					scope.objectIndex++
+					ptr := types.NewPointer(t)
+					p.TypesInfo.Defs[ident] = types.NewVar(token.NoPos, p.Types, ident.Name, ptr)
+					bound[sel.Sel.Name] = ident
+					specs = append(specs, &ast.ValueSpec{
+						Names:  []*ast.Ident{ident},
+						Type:   typeExpr(p, ptr, nil),
+						Values: []ast.Expr{&ast.SelectorExpr{X: frame, Sel: ast.NewIdent(sel.Sel.Name)}},
+					})
+				}
+				cursor.Replace(&ast.StarExpr{X: ident})
+				return false
+			}, nil)
+		}
+		if len(specs) == 0 {
+			return stmt
+		}
+		return &ast.BlockStmt{List: []ast.Stmt{
+			&ast.DeclStmt{Decl: &ast.GenDecl{Tok: token.VAR, Specs: specs}},
+			stmt,
+		}}
+	}
+
+	// Function literals are bound by the innermost statement of a statement
+	// list which contains them, which is executed right before they are
+	// created.
+	var bindList func([]ast.Stmt)
+	bindList = func(stmts []ast.Stmt) {
+		for i, stmt := range stmts {
+			var lits []*ast.FuncLit
+			var visit func(ast.Node) bool
+			visit = func(node ast.Node) bool {
+				switch n := node.(type) {
+				case *ast.FuncLit:
+					lits = append(lits, n)
+					return false
+				case *ast.BlockStmt:
+					bindList(n.List)
+					return false
+				case *ast.CaseClause:
+					for _, expr := range n.List {
+						ast.Inspect(expr, visit)
+					}
+					bindList(n.Body)
+					return false
+				case *ast.CommClause:
+					if n.Comm != nil {
+						ast.Inspect(n.Comm, visit)
+					}
+					bindList(n.Body)
+					return false
+				}
+				return true
+			}
+			ast.Inspect(stmt, visit)
+			stmts[i] = bind(stmt, lits)
+		}
+	}
+	bindList(body.List)
+}
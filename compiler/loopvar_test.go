@@ -0,0 +1,76 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestPerIterationLoopVars(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		module   string
+		source   string
+		version  string
+		expected bool
+	}{
+		{
+			name:     "module version",
+			module:   "1.22",
+			source:   "package a\n",
+			version:  "go1.22",
+			expected: true,
+		},
+		{
+			name:     "older module version",
+			module:   "1.21",
+			source:   "package a\n",
+			version:  "go1.21",
+			expected: false,
+		},
+		{
+			name:     "older file version",
+			module:   "1.22",
+			source:   "//go:build go1.21\n\npackage a\n",
+			version:  "go1.21",
+			expected: false,
+		},
+		{
+			name:     "file version before go1.21",
+			module:   "1.22",
+			source:   "//go:build go1.18\n\npackage a\n",
+			version:  "go1.21",
+			expected: false,
+		},
+		{
+			name:     "no module",
+			source:   "package a\n",
+			version:  "go1.16",
+			expected: false,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "a.go", test.source, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &packages.Package{
+				TypesInfo: &types.Info{FileVersions: map[*ast.File]string{}},
+			}
+			if test.module != "" {
+				p.Module = &packages.Module{GoVersion: test.module}
+			}
+			if v := fileGoVersion(p, file); v != test.version {
+				t.Errorf("unexpected version: got %s, want %s", v, test.version)
+			}
+			if got := perIterationLoopVars(p, file); got != test.expected {
+				t.Errorf("unexpected per-iteration loop variables: got %t, want %t", got, test.expected)
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package compiler
 
+import (
+	"io"
+	"log"
+)
+
 // Option configures the compiler.
 type Option func(*compiler)
 
@@ -20,3 +25,101 @@ func DebugColors(enabled bool) Option {
 		c.debugColors = enabled
 	}
 }
+
+// Explain makes the compiler print the shortest call chain from the named
+// function to a yield point instead of compiling the module.
+func Explain(function string) Option {
+	return func(c *compiler) {
+		c.explain = function
+	}
+}
+
+// Logger sets the logger that receives progress information and warnings.
+func Logger(logger *log.Logger) Option {
+	return func(c *compiler) {
+		c.logger = logger
+	}
+}
+
+// Output sets the writer that receives the list of files, explanations and
+// debug information requested by the OnlyListFiles, Explain and DebugColors
+// options.
+func Output(w io.Writer) Option {
+	return func(c *compiler) {
+		c.output = w
+	}
+}
+
+// BuildTags sets the build tags that are satisfied while loading packages,
+// in addition to the default ones.
+func BuildTags(tags ...string) Option {
+	return func(c *compiler) {
+		c.buildTags = tags
+	}
+}
+
+// GOOS sets the target operating systems. When multiple values are given,
+// or combined with multiple GOARCH values, the module is compiled for each
+// combination and the generated files are merged, so that files behind
+// platform build constraints get compiled as well.
+func GOOS(goos ...string) Option {
+	return func(c *compiler) {
+		c.goos = goos
+	}
+}
+
+// GOARCH sets the target architectures. See GOOS for the handling of
+// multiple values.
+func GOARCH(goarch ...string) Option {
+	return func(c *compiler) {
+		c.goarch = goarch
+	}
+}
+
+// Cache enables the compilation cache, storing the generated files in dir.
+// Packages which inputs and function colors didn't change since they were
+// last compiled are not compiled again. The cache is disabled by default.
+func Cache(dir string) Option {
+	return func(c *compiler) {
+		if dir == "" {
+			c.cache = nil
+		} else {
+			c.cache = newCache(dir)
+		}
+	}
+}
+
+// Report makes Compile write a JSON report of the coroutines generated by the
+// compiler to path. The report describes the frame of each coroutine, the
+// number of points it can be resumed at, and the functions registered for
+// serialization. Build returns the same information in Result.Functions.
+func Report(path string) Option {
+	return func(c *compiler) {
+		c.report = path
+	}
+}
+
+// BuildTag sets the build tag that selects the generated files, which is
+// "durable" by default. Compiled source files are excluded from builds with
+// this tag, so that several durable variants of a module can be generated
+// with different tags.
+//
+// Programs generated with another tag than "durable" are built with both
+// their tag and the "coroutine.durable" tag, which selects the durable
+// implementation of the coroutine package:
+//
+//	go build -tags coroutine.durable,workflow
+func BuildTag(tag string) Option {
+	return func(c *compiler) {
+		c.buildTag = tag
+	}
+}
+
+// FileSuffix sets the suffix of generated files, which replaces the .go
+// extension of the source files they are generated from. The default is an
+// underscore followed by the build tag and .go, for example _durable.go.
+func FileSuffix(suffix string) Option {
+	return func(c *compiler) {
+		c.fileSuffix = suffix
+	}
+}
@@ -0,0 +1,236 @@
+package compiler
+
+import (
+	"cmp"
+	"encoding/json"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// FunctionReport describes a coroutine generated by the compiler, which can
+// be used to track the size of coroutine states and the number of functions
+// involved in coroutines across versions of a program.
+type FunctionReport struct {
+	// Package is the import path of the package of the function.
+	Package string `json:"package"`
+
+	// Name is the fully qualified name of the function. Function literals
+	// are reported with the name of the function declaration they appear in.
+	Name string `json:"name"`
+
+	// Literal is true if the coroutine is a function literal.
+	Literal bool `json:"literal,omitempty"`
+
+	// File and Line are the position of the function in the source code.
+	// The file is relative to the module directory.
+	File string `json:"file"`
+	Line int    `json:"line"`
+
+	// Yield and Send are the R and S type arguments of the color of the
+	// function, which are the types of values yielded by the coroutine and
+	// sent to it.
+	Yield string `json:"yield"`
+	Send  string `json:"send"`
+
+	// Frame are the fields of the struct that holds the state of the
+	// function while the coroutine is suspended.
+	Frame []FrameField `json:"frame"`
+
+	// FrameSize is the size of the frame struct in bytes, or zero if it
+	// cannot be determined because the function is generic.
+	FrameSize int64 `json:"frameSize,omitempty"`
+
+	// IPs is the number of instruction pointer values that the function can
+	// be resumed at.
+	IPs int `json:"ips"`
+
+	// YieldPoints is the number of calls which may yield in the function.
+	YieldPoints int `json:"yieldPoints"`
+
+	// Version identifies the frame layout and the numbering of instruction
+	// pointers of the function. Frames created by other versions of the
+	// function can be migrated with coroutine.RegisterMigration.
+	Version string `json:"version"`
+
+	// Registered are the functions and closures of the function declaration
+	// which were registered for serialization.
+	Registered []RegisteredFunc `json:"registered,omitempty"`
+}
+
+// FrameField is a field of a coroutine frame.
+type FrameField struct {
+	// Name is the name of the field.
+	Name string `json:"name"`
+
+	// Var is the name of the variable that the field holds in the source
+	// code, which is empty for fields generated by the compiler.
+	Var string `json:"var,omitempty"`
+
+	// Type is the type of the field.
+	Type string `json:"type"`
+
+	// Size is the size of the field in bytes, or zero if it cannot be
+	// determined because the function is generic.
+	Size int64 `json:"size,omitempty"`
+}
+
+// RegisteredFunc is a function registered with types.RegisterFunc or
+// types.RegisterClosure.
+type RegisteredFunc struct {
+	Name string `json:"name"`
+
+	// ID is the stable identifier of function literals, which is recorded
+	// in coroutine states instead of the name given by the linker.
+	ID string `json:"id,omitempty"`
+
+	Closure bool `json:"closure,omitempty"`
+}
+
+// newFunctionReport creates the report of a coroutine, without the name and
+// position of the function which are only known to the caller. The objects
+// are those of the fields of the frame, as returned by frameObjects, and the
+// fields of variables in the cells set hold pointers to the variables.
+func newFunctionReport(p *packages.Package, color *types.Signature, frameType *ast.StructType, objects []types.Object, cells map[types.Object]struct{}, ips, yieldPoints int) *FunctionReport {
+	report := &FunctionReport{
+		Package:     p.PkgPath,
+		Yield:       types.TypeString(color.Params().At(0).Type(), nil),
+		Send:        types.TypeString(color.Results().At(0).Type(), nil),
+		IPs:         ips,
+		YieldPoints: yieldPoints,
+	}
+
+	var vars []*types.Var
+	known := true
+	i := 0
+	for _, field := range frameType.Fields.List {
+		for _, name := range field.Names {
+			f := FrameField{Name: name.Name}
+
+			var t types.Type
+			if obj := objects[i]; obj != nil {
+				// Constants are hoisted to the frame with their default
+				// type.
+				t = types.Default(obj.Type())
+				if _, ok := cells[obj]; ok {
+					t = types.NewPointer(t)
+				}
+				if obj.Pos().IsValid() {
+					f.Var = obj.Name()
+				}
+			} else if name.Name == "IP" {
+				t = types.Typ[types.Int]
+			}
+			i++
+
+			if t == nil {
+				f.Type = types.ExprString(field.Type)
+				known = false
+			} else {
+				f.Type = types.TypeString(t, nil)
+				if !hasTypeParams(t) {
+					f.Size = p.TypesSizes.Sizeof(t)
+				}
+				vars = append(vars, types.NewField(token.NoPos, p.Types, name.Name, t, false))
+			}
+			report.Frame = append(report.Frame, f)
+		}
+	}
+
+	if known {
+		frame := types.NewStruct(vars, nil)
+		if !hasTypeParams(frame) {
+			report.FrameSize = p.TypesSizes.Sizeof(frame)
+		}
+	}
+	return report
+}
+
+// frameObjects returns the objects that the fields of a frame hold, which
+// must be called before the fields are renamed by renameObjects.
+func frameObjects(frameType *ast.StructType, info *types.Info) (objects []types.Object) {
+	for _, field := range frameType.Fields.List {
+		for _, name := range field.Names {
+			objects = append(objects, info.ObjectOf(name))
+		}
+	}
+	return
+}
+
+// hasTypeParams returns true if a type refers to type parameters, in which
+// case its size is not known.
+func hasTypeParams(t types.Type) bool {
+	seen := map[types.Type]struct{}{}
+
+	var visit func(types.Type) bool
+	visit = func(t types.Type) bool {
+		if _, ok := seen[t]; ok {
+			return false
+		}
+		seen[t] = struct{}{}
+
+		switch t := t.(type) {
+		case *types.TypeParam:
+			return true
+		case *types.Named:
+			for i := 0; i < t.TypeArgs().Len(); i++ {
+				if visit(t.TypeArgs().At(i)) {
+					return true
+				}
+			}
+			return t.TypeParams().Len() > 0 && t.TypeArgs().Len() == 0
+		case *types.Pointer:
+			return visit(t.Elem())
+		case *types.Slice:
+			return visit(t.Elem())
+		case *types.Array:
+			return visit(t.Elem())
+		case *types.Map:
+			return visit(t.Key()) || visit(t.Elem())
+		case *types.Chan:
+			return visit(t.Elem())
+		case *types.Struct:
+			for i := 0; i < t.NumFields(); i++ {
+				if visit(t.Field(i).Type()) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return visit(t)
+}
+
+func sortFunctionReports(reports []FunctionReport) {
+	slices.SortStableFunc(reports, func(a, b FunctionReport) int {
+		if c := cmp.Compare(a.Package, b.Package); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Line, b.Line)
+	})
+}
+
+// writeReport writes the reports of the functions compiled in a result to a
+// JSON file.
+func writeReport(path string, res *Result) error {
+	b, err := json.MarshalIndent(struct {
+		Functions []FunctionReport `json:"functions"`
+	}{
+		Functions: res.Functions,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0666)
+}
@@ -0,0 +1,63 @@
+package compiler
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+)
+
+// Result is the output of Build.
+type Result struct {
+	// Files are the files generated or modified by the compiler, sorted by
+	// path. The source files of compiled packages are modified to exclude
+	// them from builds with the durable build tag, and each of them has a
+	// generated counterpart with the _durable.go suffix, or the tag and
+	// suffix configured with the BuildTag and FileSuffix options.
+	Files []File
+
+	// Functions are the reports of the coroutines generated by the
+	// compiler, sorted by package and position.
+	Functions []FunctionReport
+
+	// Diagnostics are the problems found in the source code. When there are
+	// diagnostics, no files are generated.
+	Diagnostics Diagnostics
+
+	// GOROOT is the directory where the standard library must be vendored
+	// when the compiler has to mutate GOROOT packages, or the empty string
+	// otherwise. The paths of the files generated for those packages are in
+	// this directory.
+	GOROOT string
+}
+
+// File is a file generated or modified by the compiler.
+type File struct {
+	// Path is the absolute path of the file.
+	Path string
+
+	// Content is the formatted source code of the file.
+	Content []byte
+}
+
+// Write writes the files of a result to the file system, after vendoring
+// GOROOT packages if needed. Files which already have the expected content
+// are left untouched, so that their modification time doesn't change.
+func (r *Result) Write() error {
+	if r.GOROOT != "" {
+		if err := vendorGOROOT(r.GOROOT); err != nil {
+			return err
+		}
+	}
+	for _, f := range r.Files {
+		if b, err := os.ReadFile(f.Path); err == nil && bytes.Equal(b, f.Content) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(f.Path, f.Content, 0666); err != nil {
+			return err
+		}
+	}
+	return nil
+}
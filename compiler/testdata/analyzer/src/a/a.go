@@ -0,0 +1,46 @@
+package a
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/dispatchrun/coroutine"
+)
+
+func Yield(n int) { // want Yield:`yields func\(v int\) any`
+	coroutine.Yield[int, any](n)
+}
+
+func Channel(ch chan int) { // want Channel:`yields func\(v int\) any`
+	Yield(<-ch)
+}
+
+func MethodValue(b *bytes.Buffer) { // want MethodValue:`yields func\(v int\) any`
+	f := b.Len // want `variable f of a coroutine cannot be serialized: method value Len is not registered`
+	Yield(f())
+}
+
+func Closure(ctx context.Context) { // want Closure:`yields func\(v int\) any`
+	ctx, cancel := context.WithCancel(ctx) // want `variable cancel of a coroutine cannot be serialized: function returned by context.WithCancel is not registered`
+	var stop = context.AfterFunc(ctx, func() {}) // want `variable stop of a coroutine cannot be serialized: function returned by context.AfterFunc is not registered`
+	Yield(0)
+	stop()
+	cancel()
+}
+
+func Registered() { // want Registered:`yields func\(v int\) any`
+	f := func() int { return 1 }
+	Yield(f())
+}
+
+func Goto() { // want Goto:`yields func\(v int\) any`
+	goto end // want `not implemented: goto`
+end: // want `not implemented: labels not attached to for/switch/select`
+	Yield(0)
+}
+
+func NotColored(ch chan int) {
+	goto end
+end:
+	<-ch
+}
@@ -0,0 +1,11 @@
+package b
+
+import "a"
+
+type state struct {
+	done chan struct{}
+}
+
+func Caller(s state) { // want Caller:`yields func\(v int\) any`
+	a.Yield(1)
+}
@@ -0,0 +1,8 @@
+package c
+
+import "a"
+
+//coroutine:noyield
+func NoYield() { // want NoYield:`//coroutine:noyield`
+	a.Yield(2) // want `marked //coroutine:noyield but calls a.Yield which yields`
+}
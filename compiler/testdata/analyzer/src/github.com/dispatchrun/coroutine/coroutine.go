@@ -0,0 +1,6 @@
+package coroutine
+
+func Yield[R, S any](v R) S {
+	var s S
+	return s
+}
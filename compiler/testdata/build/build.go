@@ -0,0 +1,11 @@
+//go:build !durable
+
+package build
+
+import "github.com/dispatchrun/coroutine"
+
+func Count(n int) {
+	for i := 0; i < n; i++ {
+		coroutine.Yield[int, any](i)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !durable
+
+package build
+
+import "github.com/dispatchrun/coroutine"
+
+func CountDown(n int) {
+	for i := n; i > 0; i-- {
+		coroutine.Yield[int, any](i)
+	}
+}
@@ -0,0 +1,71 @@
+//go:build windows && durable
+
+package build
+
+import coroutine "github.com/dispatchrun/coroutine"
+import _types "github.com/dispatchrun/coroutine/types"
+
+//go:noinline
+func CountDown(_fn0 int) {
+	_c := coroutine.LoadContext[int, any]()
+//line build_windows.go:7
+	var _f0 *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata/build.CountDown@f573150e21b7853c"`
+		X0 int
+		X1 int
+	} = coroutine.Push[struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata/build.CountDown@f573150e21b7853c"`
+		X0 int
+		X1 int
+	}](&_c.Stack)
+//line build_windows_durable.go:22
+	if _f0.IP == 0 {
+//line build_windows.go:7
+		*_f0 = struct {
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata/build.CountDown@f573150e21b7853c"`
+			X0 int
+			X1 int
+		}{X0: _fn0}
+	}
+//line build_windows_durable.go:31
+	defer func() {
+		if !_c.Unwinding() {
+			coroutine.Pop(&_c.Stack)
+		}
+	}()
+	switch {
+	case _f0.IP < 2:
+//line build_windows.go:8
+		_f0.X1 = _f0.X0
+//line build_windows_durable.go:41
+		_f0.IP = 2
+		fallthrough
+	case _f0.IP < 3:
+//line build_windows.go:8
+		for ; _f0.X1 > 0; _f0.X1, _f0.IP = _f0.X1-1, 2 {
+			coroutine.Yield[int, any](_f0.X1)
+		}
+	}
+//line build_windows_durable.go:50
+}
+func init() {
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata/build.CountDown")
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata/build.CountDown@f573150e21b7853c"`
+		X0 int
+		X1 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata/build.CountDown@f573150e21b7853c"`
+		X0 int
+		X1 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+	})
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata/build.CountDown", "f573150e21b7853c")
+}
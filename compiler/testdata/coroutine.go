@@ -696,6 +696,38 @@ func RangeOverInt(n int) {
 	}
 }
 
+func LoopVarClosures(n int) {
+	var fs []func() int
+	for i := 0; i < n; i++ {
+		coroutine.Yield[int, any](i)
+		fs = append(fs, func() int { return i })
+	}
+
+	var ps []*int
+	for j := range n {
+		x := j * 10
+		ps = append(ps, &x)
+		fs = append(fs, func() int { return j })
+		coroutine.Yield[int, any](x)
+	}
+
+	for i := 0; i < n; i++ {
+		skip := func() { i++ }
+		coroutine.Yield[int, any](i)
+		if i%2 == 0 {
+			skip()
+			continue
+		}
+	}
+
+	for _, f := range fs {
+		coroutine.Yield[int, any](f())
+	}
+	for _, p := range ps {
+		coroutine.Yield[int, any](*p)
+	}
+}
+
 func ReflectType(types ...reflect.Type) {
 	for _, t := range types {
 		v := reflect.New(t).Elem()
@@ -830,3 +862,101 @@ func GenericSlice(n int) {
 		coroutine.Yield[int, any](x)
 	}
 }
+
+type Notifier interface {
+	//coroutine:noyield
+	Notify(int)
+}
+
+type yieldingNotifier struct{}
+
+func (yieldingNotifier) Notify(n int) { coroutine.Yield[int, any](n) }
+
+type countingNotifier struct{ count *int }
+
+func (c countingNotifier) Notify(n int) { *c.count += n }
+
+// notifyAll would be colored because of the yieldingNotifier implementation
+// of Notifier (and rejected because of the goto statement), but the noyield
+// directive on the method prunes the edge.
+func notifyAll(notifiers []Notifier, n int) {
+	i := 0
+loop:
+	if i < len(notifiers) {
+		notifiers[i].Notify(n)
+		i++
+		goto loop
+	}
+}
+
+func NoYieldDirective(n int) {
+	count := 0
+	notifiers := []Notifier{countingNotifier{&count}}
+	if n < 0 {
+		notifiers = append(notifiers, yieldingNotifier{})
+	}
+	for i := 1; i <= n; i++ {
+		notifyAll(notifiers, i)
+		coroutine.Yield[int, any](count)
+	}
+}
+
+// forcedYield is not on a path to a yield point, the directive forces the
+// compiler to color it anyway.
+//
+//coroutine:yields
+func forcedYield(n int) int {
+	return double(n)
+}
+
+func double(n int) int {
+	return n * 2
+}
+
+func YieldsDirective(n int) {
+	for i := 0; i < n; i++ {
+		coroutine.Yield[int, any](forcedYield(i))
+	}
+}
+
+// ChannelBuffer yields the values buffered in a channel, which is part of the
+// coroutine state between yields.
+func ChannelBuffer(n int) {
+	ch := make(chan int, n)
+	for i := 1; i <= n; i++ {
+		ch <- i * i
+	}
+	close(ch)
+	for {
+		v, ok := <-ch
+		if !ok {
+			break
+		}
+		coroutine.Yield[int, any](v)
+	}
+}
+
+// transientCache memoizes squares; the cache is not part of the state of
+// coroutines and starts empty when they are resumed.
+type transientCache struct {
+	squares map[int]int `coroutine:"-"`
+}
+
+func (c *transientCache) square(i int) int {
+	if c.squares == nil {
+		c.squares = make(map[int]int)
+	}
+	v, ok := c.squares[i]
+	if !ok {
+		v = i * i
+		c.squares[i] = v
+	}
+	return v
+}
+
+func TransientField(n int) {
+	c := transientCache{}
+	for i := 1; i <= n; i++ {
+		coroutine.Yield[int, any](c.square(i))
+	}
+}
@@ -23,22 +23,26 @@ func Identity(n int) { coroutine.Yield[int, any](n) }
 //go:noinline
 func SquareGenerator(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:26
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.SquareGenerator@c655cb4854991333"`
 		X0 int
 		X1 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.SquareGenerator@c655cb4854991333"`
 		X0 int
 		X1 int
 	}](&_c.Stack)
+//line coroutine_durable.go:37
 	if _f0.IP == 0 {
+//line coroutine.go:26
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.SquareGenerator@c655cb4854991333"`
 			X0 int
 			X1 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:46
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -46,32 +50,40 @@ func SquareGenerator(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:27
 		_f0.X1 = 1
+//line coroutine_durable.go:56
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
 		for ; _f0.X1 <= _f0.X0; _f0.X1, _f0.IP = _f0.X1+1, 2 {
+//line coroutine.go:28
 			coroutine.Yield[int, any](_f0.X1 * _f0.X1)
 		}
 	}
+//line coroutine_durable.go:65
 }
 
 //go:noinline
 func SquareGeneratorTwice(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:32
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.SquareGeneratorTwice@e842ebcb555b67e6"`
 		X0 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.SquareGeneratorTwice@e842ebcb555b67e6"`
 		X0 int
 	}](&_c.Stack)
+//line coroutine_durable.go:79
 	if _f0.IP == 0 {
+//line coroutine.go:32
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.SquareGeneratorTwice@e842ebcb555b67e6"`
 			X0 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:87
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -79,33 +91,41 @@ func SquareGeneratorTwice(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:33
 		SquareGenerator(_f0.X0)
+//line coroutine_durable.go:97
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:34
 		SquareGenerator(_f0.X0)
 	}
+//line coroutine_durable.go:104
 }
 
 //go:noinline
 func SquareGeneratorTwiceLoop(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:37
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.SquareGeneratorTwiceLoop@03d45dc05ad3ccd2"`
 		X0 int
 		X1 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.SquareGeneratorTwiceLoop@03d45dc05ad3ccd2"`
 		X0 int
 		X1 int
 	}](&_c.Stack)
+//line coroutine_durable.go:120
 	if _f0.IP == 0 {
+//line coroutine.go:37
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.SquareGeneratorTwiceLoop@03d45dc05ad3ccd2"`
 			X0 int
 			X1 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:129
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -113,38 +133,46 @@ func SquareGeneratorTwiceLoop(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:38
 		_f0.X1 = 0
+//line coroutine_durable.go:139
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:38
 		for ; _f0.X1 < 2; _f0.X1, _f0.IP = _f0.X1+1, 2 {
 			SquareGenerator(_f0.X0)
 		}
 	}
+//line coroutine_durable.go:148
 }
 
 //go:noinline
 func EvenSquareGenerator(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:43
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.EvenSquareGenerator@b0f75a8c0071d093"`
 		X0 int
 		X1 int
 		X2 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.EvenSquareGenerator@b0f75a8c0071d093"`
 		X0 int
 		X1 int
 		X2 int
 	}](&_c.Stack)
+//line coroutine_durable.go:166
 	if _f0.IP == 0 {
+//line coroutine.go:43
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.EvenSquareGenerator@b0f75a8c0071d093"`
 			X0 int
 			X1 int
 			X2 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:176
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -152,46 +180,55 @@ func EvenSquareGenerator(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:44
 		_f0.X1 = 1
+//line coroutine_durable.go:186
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 4:
 		for ; _f0.X1 <= _f0.X0; _f0.X1, _f0.IP = _f0.X1+1, 2 {
 			switch {
 			case _f0.IP < 3:
+//line coroutine.go:45
 				_f0.X2 = _f0.X1 % 2
+//line coroutine_durable.go:195
 				_f0.IP = 3
 				fallthrough
 			case _f0.IP < 4:
+//line coroutine.go:45
 				if _f0.X2 == 0 {
 					coroutine.Yield[int, any](_f0.X1 * _f0.X1)
 				}
 			}
 		}
 	}
+//line coroutine_durable.go:206
 }
 
 //go:noinline
 func NestedLoops(_fn0 int) (_ int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:51
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.NestedLoops@f14fdf4dd3902d03"`
 		X0 int
 		X1 int
 		X2 int
 		X3 int
 		X4 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.NestedLoops@f14fdf4dd3902d03"`
 		X0 int
 		X1 int
 		X2 int
 		X3 int
 		X4 int
 	}](&_c.Stack)
+//line coroutine_durable.go:228
 	if _f0.IP == 0 {
+//line coroutine.go:51
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.NestedLoops@f14fdf4dd3902d03"`
 			X0 int
 			X1 int
 			X2 int
@@ -199,6 +236,7 @@ func NestedLoops(_fn0 int) (_ int) {
 			X4 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:240
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -211,31 +249,40 @@ func NestedLoops(_fn0 int) (_ int) {
 	case _f0.IP < 7:
 		switch {
 		case _f0.IP < 3:
+//line coroutine.go:53
 			_f0.X2 = 1
+//line coroutine_durable.go:255
 			_f0.IP = 3
 			fallthrough
 		case _f0.IP < 7:
 			for ; _f0.X2 <= _f0.X0; _f0.X2, _f0.IP = _f0.X2+1, 3 {
 				switch {
 				case _f0.IP < 4:
+//line coroutine.go:54
 					_f0.X3 = 1
+//line coroutine_durable.go:264
 					_f0.IP = 4
 					fallthrough
 				case _f0.IP < 7:
 					for ; _f0.X3 <= _f0.X0; _f0.X3, _f0.IP = _f0.X3+1, 4 {
 						switch {
 						case _f0.IP < 5:
+//line coroutine.go:55
 							_f0.X4 = 1
+//line coroutine_durable.go:273
 							_f0.IP = 5
 							fallthrough
 						case _f0.IP < 7:
 							for ; _f0.X4 <= _f0.X0; _f0.X4, _f0.IP = _f0.X4+1, 5 {
 								switch {
 								case _f0.IP < 6:
+//line coroutine.go:56
 									coroutine.Yield[int, any](_f0.X2 * _f0.X3 * _f0.X4)
+//line coroutine_durable.go:282
 									_f0.IP = 6
 									fallthrough
 								case _f0.IP < 7:
+//line coroutine.go:57
 									_f0.X1++
 								}
 							}
@@ -244,37 +291,44 @@ func NestedLoops(_fn0 int) (_ int) {
 				}
 			}
 		}
+//line coroutine_durable.go:295
 		_f0.IP = 7
 		fallthrough
 	case _f0.IP < 8:
+//line coroutine.go:61
 
 		return _f0.X1
 	}
+//line coroutine_durable.go:303
 	panic("unreachable")
 }
 
 //go:noinline
 func FizzBuzzIfGenerator(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:64
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.FizzBuzzIfGenerator@f72a8e002a46241c"`
 		X0 int
 		X1 int
 		X2 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.FizzBuzzIfGenerator@f72a8e002a46241c"`
 		X0 int
 		X1 int
 		X2 int
 	}](&_c.Stack)
+//line coroutine_durable.go:322
 	if _f0.IP == 0 {
+//line coroutine.go:64
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.FizzBuzzIfGenerator@f72a8e002a46241c"`
 			X0 int
 			X1 int
 			X2 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:332
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -282,25 +336,35 @@ func FizzBuzzIfGenerator(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:65
 		_f0.X1 = 1
+//line coroutine_durable.go:342
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 7:
 		for ; _f0.X1 <= _f0.X0; _f0.X1, _f0.IP = _f0.X1+1, 2 {
+//line coroutine.go:66
 			if _f0.X1%
 				3 == 0 && _f0.X1%5 == 0 {
+//line coroutine.go:67
 				coroutine.Yield[int, any](FizzBuzz)
 			} else {
+//line coroutine.go:68
 				if _f0.X1%
 					3 == 0 {
+//line coroutine.go:69
 					coroutine.Yield[int, any](Fizz)
 				} else {
+//line coroutine_durable.go:359
 					switch {
 					case _f0.IP < 5:
+//line coroutine.go:70
 						_f0.X2 = _f0.X1 % 5
+//line coroutine_durable.go:364
 						_f0.IP = 5
 						fallthrough
 					case _f0.IP < 7:
+//line coroutine.go:70
 						if _f0.X2 == 0 {
 							coroutine.Yield[int, any](Buzz)
 						} else {
@@ -312,29 +376,33 @@ func FizzBuzzIfGenerator(_fn0 int) {
 			}
 		}
 	}
+//line coroutine_durable.go:380
 }
 
 //go:noinline
 func FizzBuzzSwitchGenerator(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:78
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.FizzBuzzSwitchGenerator@b600f193aa9c22ca"`
 		X0 int
 		X1 int
 		X2 bool
 		X3 bool
 		X4 bool
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.FizzBuzzSwitchGenerator@b600f193aa9c22ca"`
 		X0 int
 		X1 int
 		X2 bool
 		X3 bool
 		X4 bool
 	}](&_c.Stack)
+//line coroutine_durable.go:402
 	if _f0.IP == 0 {
+//line coroutine.go:78
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.FizzBuzzSwitchGenerator@b600f193aa9c22ca"`
 			X0 int
 			X1 int
 			X2 bool
@@ -342,6 +410,7 @@ func FizzBuzzSwitchGenerator(_fn0 int) {
 			X4 bool
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:414
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -349,7 +418,9 @@ func FizzBuzzSwitchGenerator(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:79
 		_f0.X1 = 1
+//line coroutine_durable.go:424
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 9:
@@ -358,32 +429,43 @@ func FizzBuzzSwitchGenerator(_fn0 int) {
 			default:
 				switch {
 				case _f0.IP < 3:
+//line coroutine.go:81
 					_f0.X2 = _f0.X1%
 						3 == 0 && _f0.X1%5 == 0
+//line coroutine_durable.go:436
 					_f0.IP = 3
 					fallthrough
 				case _f0.IP < 9:
 					if _f0.X2 {
+//line coroutine.go:82
 						coroutine.Yield[int, any](FizzBuzz)
 					} else {
+//line coroutine_durable.go:444
 						switch {
 						case _f0.IP < 5:
+//line coroutine.go:83
 							_f0.X3 = _f0.X1%
 								3 == 0
+//line coroutine_durable.go:450
 							_f0.IP = 5
 							fallthrough
 						case _f0.IP < 9:
 							if _f0.X3 {
+//line coroutine.go:84
 								coroutine.Yield[int, any](Fizz)
 							} else {
+//line coroutine_durable.go:458
 								switch {
 								case _f0.IP < 7:
+//line coroutine.go:85
 									_f0.X4 = _f0.X1%
 										5 == 0
+//line coroutine_durable.go:464
 									_f0.IP = 7
 									fallthrough
 								case _f0.IP < 9:
 									if _f0.X4 {
+//line coroutine.go:86
 										coroutine.Yield[int, any](Buzz)
 									} else {
 
@@ -397,13 +479,14 @@ func FizzBuzzSwitchGenerator(_fn0 int) {
 			}
 		}
 	}
+//line coroutine_durable.go:483
 }
 
 //go:noinline
 func Shadowing(_ int) {
 	_c := coroutine.LoadContext[int, any]()
 	var _f0 *struct {
-		IP  int
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Shadowing@5d6f70650829da51"`
 		X0  int
 		X1  int
 		X2  int
@@ -428,7 +511,7 @@ func Shadowing(_ int) {
 		X21 uintptr
 		X22 int
 	} = coroutine.Push[struct {
-		IP  int
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Shadowing@5d6f70650829da51"`
 		X0  int
 		X1  int
 		X2  int
@@ -453,25 +536,32 @@ func Shadowing(_ int) {
 		X21 uintptr
 		X22 int
 	}](&_c.Stack)
+//line coroutine.go:134
 
 	const _o0 = 11
 
 	const _o1 = 12
+//line coroutine.go:145
 
 	type _o2 uint16
 
 	type _o3 uint32
+//line coroutine.go:152
 
 	const _o4 = 1
+//line coroutine.go:153
 	type _o5 [_o4]uint8
+//line coroutine.go:155
 
 	type _o6 [_o4]uint8
 
 	const _o7 = unsafe.Sizeof(_o6{}) * 2
+//line coroutine.go:158
 	type _o8 [_o7]uint8
+//line coroutine_durable.go:562
 	if _f0.IP == 0 {
 		*_f0 = struct {
-			IP  int
+			IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Shadowing@5d6f70650829da51"`
 			X0  int
 			X1  int
 			X2  int
@@ -504,57 +594,77 @@ func Shadowing(_ int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:94
 		_f0.X0 = 0
+//line coroutine_durable.go:600
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:95
 		coroutine.Yield[int, any](_f0.X0)
+//line coroutine_durable.go:606
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 5:
 		switch {
 		case _f0.IP < 4:
+//line coroutine.go:97
 			_f0.X1 = 1
+//line coroutine_durable.go:614
 			_f0.IP = 4
 			fallthrough
 		case _f0.IP < 5:
+//line coroutine.go:97
 			if true {
 				coroutine.Yield[int, any](_f0.X1)
 			}
 		}
+//line coroutine_durable.go:623
 		_f0.IP = 5
 		fallthrough
 	case _f0.IP < 6:
+//line coroutine.go:100
 
 		coroutine.Yield[int, any](_f0.X0)
+//line coroutine_durable.go:630
 		_f0.IP = 6
 		fallthrough
 	case _f0.IP < 8:
 		switch {
 		case _f0.IP < 7:
+//line coroutine.go:102
 			_f0.X2 = 1
+//line coroutine_durable.go:638
 			_f0.IP = 7
 			fallthrough
 		case _f0.IP < 8:
+//line coroutine.go:102
 			for ; _f0.X2 < 3; _f0.X2, _f0.IP = _f0.X2+1, 7 {
 				coroutine.Yield[int, any](_f0.X2)
 			}
 		}
+//line coroutine_durable.go:647
 		_f0.IP = 8
 		fallthrough
 	case _f0.IP < 9:
+//line coroutine.go:105
 
 		coroutine.Yield[int, any](_f0.X0)
+//line coroutine_durable.go:654
 		_f0.IP = 9
 		fallthrough
 	case _f0.IP < 16:
 		switch {
 		case _f0.IP < 10:
+//line coroutine.go:107
 			_f0.X3 = 1
+//line coroutine_durable.go:662
 			_f0.IP = 10
 			fallthrough
 		case _f0.IP < 11:
+//line coroutine.go:107
 			_f0.X4 = _f0.X3
+//line coroutine_durable.go:668
 			_f0.IP = 11
 			fallthrough
 		case _f0.IP < 16:
@@ -562,8 +672,10 @@ func Shadowing(_ int) {
 			default:
 				switch {
 				case _f0.IP < 12:
+//line coroutine.go:108
 					_f0.X5 = _f0.X4 ==
 						1
+//line coroutine_durable.go:679
 					_f0.IP = 12
 					fallthrough
 				case _f0.IP < 16:
@@ -572,23 +684,30 @@ func Shadowing(_ int) {
 						case _f0.IP < 15:
 							switch {
 							case _f0.IP < 13:
+//line coroutine.go:109
 								_f0.X6 = 2
+//line coroutine_durable.go:690
 								_f0.IP = 13
 								fallthrough
 							case _f0.IP < 14:
+//line coroutine.go:109
 								_f0.X7 = _f0.X6
+//line coroutine_durable.go:696
 								_f0.IP = 14
 								fallthrough
 							case _f0.IP < 15:
 								switch {
 								default:
+//line coroutine.go:111
 
 									coroutine.Yield[int, any](_f0.X6)
 								}
 							}
+//line coroutine_durable.go:707
 							_f0.IP = 15
 							fallthrough
 						case _f0.IP < 16:
+//line coroutine.go:113
 
 							coroutine.Yield[int, any](_f0.X3)
 						}
@@ -596,39 +715,52 @@ func Shadowing(_ int) {
 				}
 			}
 		}
+//line coroutine_durable.go:719
 		_f0.IP = 16
 		fallthrough
 	case _f0.IP < 17:
+//line coroutine.go:116
 
 		coroutine.Yield[int, any](_f0.X0)
+//line coroutine_durable.go:726
 		_f0.IP = 17
 		fallthrough
 	case _f0.IP < 21:
 		switch {
 		case _f0.IP < 18:
+//line coroutine.go:118
 			_f0.X8 = 1
+//line coroutine_durable.go:734
 			_f0.IP = 18
 			fallthrough
 		case _f0.IP < 20:
 			switch {
 			case _f0.IP < 19:
+//line coroutine.go:120
 				_f0.X9 = 2
+//line coroutine_durable.go:742
 				_f0.IP = 19
 				fallthrough
 			case _f0.IP < 20:
+//line coroutine.go:121
 				coroutine.Yield[int, any](_f0.X9)
 			}
+//line coroutine_durable.go:749
 			_f0.IP = 20
 			fallthrough
 		case _f0.IP < 21:
+//line coroutine.go:123
 
 			coroutine.Yield[int, any](_f0.X8)
 		}
+//line coroutine_durable.go:757
 		_f0.IP = 21
 		fallthrough
 	case _f0.IP < 22:
+//line coroutine.go:126
 
 		coroutine.Yield[int, any](_f0.X0)
+//line coroutine_durable.go:764
 		_f0.IP = 22
 		fallthrough
 	case _f0.IP < 23:
@@ -638,17 +770,23 @@ func Shadowing(_ int) {
 	case _f0.IP < 25:
 		switch {
 		case _f0.IP < 24:
+//line coroutine.go:129
 			_f0.X11 = 1
+//line coroutine_durable.go:776
 			_f0.IP = 24
 			fallthrough
 		case _f0.IP < 25:
+//line coroutine.go:130
 			coroutine.Yield[int, any](_f0.X11)
 		}
+//line coroutine_durable.go:783
 		_f0.IP = 25
 		fallthrough
 	case _f0.IP < 26:
+//line coroutine.go:132
 
 		coroutine.Yield[int, any](_f0.X10)
+//line coroutine_durable.go:790
 		_f0.IP = 26
 		fallthrough
 	case _f0.IP < 29:
@@ -656,107 +794,145 @@ func Shadowing(_ int) {
 		case _f0.IP < 28:
 			switch {
 			case _f0.IP < 27:
+//line coroutine.go:138
 				_f0.X12 = 13
+//line coroutine_durable.go:800
 				_f0.IP = 27
 				fallthrough
 			case _f0.IP < 28:
+//line coroutine.go:139
 				coroutine.Yield[int, any](_f0.X12)
 			}
+//line coroutine_durable.go:807
 			_f0.IP = 28
 			fallthrough
 		case _f0.IP < 29:
+//line coroutine.go:141
 
 			coroutine.Yield[int, any](_o1)
 		}
+//line coroutine_durable.go:815
 		_f0.IP = 29
 		fallthrough
 	case _f0.IP < 30:
+//line coroutine.go:143
 
 		coroutine.Yield[int, any](_o0)
+//line coroutine_durable.go:822
 		_f0.IP = 30
 		fallthrough
 	case _f0.IP < 33:
 		switch {
 		case _f0.IP < 31:
+//line coroutine.go:148
 			_f0.X13 = unsafe.Sizeof(_o3(0))
+//line coroutine_durable.go:830
 			_f0.IP = 31
 			fallthrough
 		case _f0.IP < 32:
+//line coroutine.go:148
 			_f0.X14 = int(_f0.X13)
+//line coroutine_durable.go:836
 			_f0.IP = 32
 			fallthrough
 		case _f0.IP < 33:
+//line coroutine.go:148
 			coroutine.Yield[int, any](_f0.X14)
 		}
+//line coroutine_durable.go:843
 		_f0.IP = 33
 		fallthrough
 	case _f0.IP < 34:
+//line coroutine.go:150
 		_f0.X15 = unsafe.Sizeof(_o2(0))
+//line coroutine_durable.go:849
 		_f0.IP = 34
 		fallthrough
 	case _f0.IP < 35:
+//line coroutine.go:150
 		_f0.X16 = int(_f0.X15)
+//line coroutine_durable.go:855
 		_f0.IP = 35
 		fallthrough
 	case _f0.IP < 36:
+//line coroutine.go:150
 		coroutine.Yield[int, any](_f0.X16)
+//line coroutine_durable.go:861
 		_f0.IP = 36
 		fallthrough
 	case _f0.IP < 42:
 		switch {
 		case _f0.IP < 37:
+//line coroutine.go:156
 			_f0.X17 = unsafe.Sizeof(_o6{})
+//line coroutine_durable.go:869
 			_f0.IP = 37
 			fallthrough
 		case _f0.IP < 38:
+//line coroutine.go:156
 			_f0.X18 = int(_f0.X17)
+//line coroutine_durable.go:875
 			_f0.IP = 38
 			fallthrough
 		case _f0.IP < 39:
+//line coroutine.go:156
 			coroutine.Yield[int, any](_f0.X18)
+//line coroutine_durable.go:881
 			_f0.IP = 39
 			fallthrough
 		case _f0.IP < 40:
+//line coroutine.go:159
 			_f0.X19 = unsafe.Sizeof(_o8{})
+//line coroutine_durable.go:887
 			_f0.IP = 40
 			fallthrough
 		case _f0.IP < 41:
+//line coroutine.go:159
 			_f0.X20 = int(_f0.X19)
+//line coroutine_durable.go:893
 			_f0.IP = 41
 			fallthrough
 		case _f0.IP < 42:
+//line coroutine.go:159
 			coroutine.Yield[int, any](_f0.X20)
 		}
+//line coroutine_durable.go:900
 		_f0.IP = 42
 		fallthrough
 	case _f0.IP < 43:
+//line coroutine.go:161
 		_f0.X21 = unsafe.Sizeof(_o5{})
+//line coroutine_durable.go:906
 		_f0.IP = 43
 		fallthrough
 	case _f0.IP < 44:
+//line coroutine.go:161
 		_f0.X22 = int(_f0.X21)
+//line coroutine_durable.go:912
 		_f0.IP = 44
 		fallthrough
 	case _f0.IP < 45:
+//line coroutine.go:161
 		coroutine.Yield[int, any](_f0.X22)
 	}
+//line coroutine_durable.go:919
 }
 
 //go:noinline
 func RangeSliceIndexGenerator(_ int) {
 	_c := coroutine.LoadContext[int, any]()
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeSliceIndexGenerator@d5e40a2939c39085"`
 		X0 []int
 		X1 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeSliceIndexGenerator@d5e40a2939c39085"`
 		X0 []int
 		X1 int
 	}](&_c.Stack)
 	if _f0.IP == 0 {
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeSliceIndexGenerator@d5e40a2939c39085"`
 			X0 []int
 			X1 int
 		}{}
@@ -768,40 +944,46 @@ func RangeSliceIndexGenerator(_ int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:165
 		_f0.X0 = []int{10, 20, 30}
+//line coroutine_durable.go:950
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 4:
 		switch {
 		case _f0.IP < 3:
+//line coroutine.go:165
 			_f0.X1 = 0
+//line coroutine_durable.go:958
 			_f0.IP = 3
 			fallthrough
 		case _f0.IP < 4:
 			for ; _f0.X1 < len(_f0.X0); _f0.X1, _f0.IP = _f0.X1+1, 3 {
+//line coroutine.go:166
 				coroutine.Yield[int, any](_f0.X1)
 			}
 		}
 	}
+//line coroutine_durable.go:968
 }
 
 //go:noinline
 func RangeArrayIndexValueGenerator(_ int) {
 	_c := coroutine.LoadContext[int, any]()
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeArrayIndexValueGenerator@8a5fd5286ef4fb01"`
 		X0 [3]int
 		X1 int
 		X2 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeArrayIndexValueGenerator@8a5fd5286ef4fb01"`
 		X0 [3]int
 		X1 int
 		X2 int
 	}](&_c.Stack)
 	if _f0.IP == 0 {
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeArrayIndexValueGenerator@8a5fd5286ef4fb01"`
 			X0 [3]int
 			X1 int
 			X2 int
@@ -814,51 +996,61 @@ func RangeArrayIndexValueGenerator(_ int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:171
 		_f0.X0 = [...]int{10, 20, 30}
+//line coroutine_durable.go:1002
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 6:
 		switch {
 		case _f0.IP < 3:
+//line coroutine.go:171
 			_f0.X1 = 0
+//line coroutine_durable.go:1010
 			_f0.IP = 3
 			fallthrough
 		case _f0.IP < 6:
 			for ; _f0.X1 < len(_f0.X0); _f0.X1, _f0.IP = _f0.X1+1, 3 {
 				switch {
 				case _f0.IP < 4:
+//line coroutine.go:171
 					_f0.X2 = _f0.X0[_f0.X1]
+//line coroutine_durable.go:1019
 					_f0.IP = 4
 					fallthrough
 				case _f0.IP < 5:
+//line coroutine.go:172
 					coroutine.Yield[int, any](_f0.X1)
+//line coroutine_durable.go:1025
 					_f0.IP = 5
 					fallthrough
 				case _f0.IP < 6:
+//line coroutine.go:173
 					coroutine.Yield[int, any](_f0.X2)
 				}
 			}
 		}
 	}
+//line coroutine_durable.go:1035
 }
 
 //go:noinline
 func TypeSwitchingGenerator(_ int) {
 	_c := coroutine.LoadContext[int, any]()
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.TypeSwitchingGenerator@6123141c1f6af071"`
 		X0 []any
 		X1 int
 		X2 any
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.TypeSwitchingGenerator@6123141c1f6af071"`
 		X0 []any
 		X1 int
 		X2 any
 	}](&_c.Stack)
 	if _f0.IP == 0 {
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.TypeSwitchingGenerator@6123141c1f6af071"`
 			X0 []any
 			X1 int
 			X2 any
@@ -871,7 +1063,9 @@ func TypeSwitchingGenerator(_ int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:178
 		_f0.X0 = []any{int8(10), int16(20), int32(30), int64(40)}
+//line coroutine_durable.go:1069
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 12:
@@ -884,12 +1078,15 @@ func TypeSwitchingGenerator(_ int) {
 			for ; _f0.X1 < len(_f0.X0); _f0.X1, _f0.IP = _f0.X1+1, 3 {
 				switch {
 				case _f0.IP < 4:
+//line coroutine.go:178
 					_f0.X2 = _f0.X0[_f0.X1]
+//line coroutine_durable.go:1084
 					_f0.IP = 4
 					fallthrough
 				case _f0.IP < 8:
 					switch _f0.X2.(type) {
 					case int8:
+//line coroutine.go:181
 						coroutine.Yield[int, any](1)
 					case int16:
 						coroutine.Yield[int, any](2)
@@ -898,9 +1095,11 @@ func TypeSwitchingGenerator(_ int) {
 					case int64:
 						coroutine.Yield[int, any](8)
 					}
+//line coroutine_durable.go:1099
 					_f0.IP = 8
 					fallthrough
 				case _f0.IP < 12:
+//line coroutine.go:189
 					switch v := _f0.X2.(type) {
 					case int8:
 						coroutine.Yield[int, any](int(v))
@@ -915,49 +1114,48 @@ func TypeSwitchingGenerator(_ int) {
 			}
 		}
 	}
+//line coroutine_durable.go:1118
 }
 
 //go:noinline
 func LoopBreakAndContinue(_ int) {
 	_c := coroutine.LoadContext[int, any]()
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopBreakAndContinue@4f6231e9ad0c4daa"`
 		X0 int
 		X1 int
 		X2 int
 		X3 int
-		X4 int
+		X4 bool
 		X5 bool
-		X6 bool
-		X7 int
+		X6 int
+		X7 bool
 		X8 bool
-		X9 bool
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopBreakAndContinue@4f6231e9ad0c4daa"`
 		X0 int
 		X1 int
 		X2 int
 		X3 int
-		X4 int
+		X4 bool
 		X5 bool
-		X6 bool
-		X7 int
+		X6 int
+		X7 bool
 		X8 bool
-		X9 bool
 	}](&_c.Stack)
+	var _o0 int
 	if _f0.IP == 0 {
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopBreakAndContinue@4f6231e9ad0c4daa"`
 			X0 int
 			X1 int
 			X2 int
 			X3 int
-			X4 int
+			X4 bool
 			X5 bool
-			X6 bool
-			X7 int
+			X6 int
+			X7 bool
 			X8 bool
-			X9 bool
 		}{}
 	}
 	defer func() {
@@ -969,90 +1167,132 @@ func LoopBreakAndContinue(_ int) {
 	case _f0.IP < 6:
 		switch {
 		case _f0.IP < 2:
+//line coroutine.go:203
 			_f0.X0 = 0
+//line coroutine_durable.go:1173
 			_f0.IP = 2
 			fallthrough
 		case _f0.IP < 6:
+//line coroutine.go:203
 		_l0:
 			for ; _f0.X0 < 10; _f0.X0, _f0.IP = _f0.X0+1, 2 {
+//line coroutine_durable.go:1180
 				switch {
 				case _f0.IP < 4:
+//line coroutine.go:204
 					{
-						_f0.X1 = _f0.X0 % 2
-						if _f0.X1 == 0 {
+//line coroutine.go:204
+						_o0 = _f0.X0 % 2
+//line coroutine.go:204
+						if _o0 == 0 {
 							continue _l0
 						}
 					}
+//line coroutine_durable.go:1192
 					_f0.IP = 4
 					fallthrough
 				case _f0.IP < 5:
+//line coroutine.go:207
 					if _f0.X0 >
 						5 {
+//line coroutine.go:208
 						break _l0
 					}
+//line coroutine_durable.go:1202
 					_f0.IP = 5
 					fallthrough
 				case _f0.IP < 6:
+//line coroutine.go:210
 
 					coroutine.Yield[int, any](_f0.X0)
 				}
 			}
 		}
+//line coroutine_durable.go:1212
 		_f0.IP = 6
 		fallthrough
 	case _f0.IP < 18:
 		switch {
 		case _f0.IP < 7:
-			_f0.X2 = 0
+//line coroutine.go:214
+			_f0.X1 = 0
+//line coroutine_durable.go:1220
 			_f0.IP = 7
 			fallthrough
 		case _f0.IP < 18:
+//line coroutine.go:214
 		_l1:
-			for ; _f0.X2 < 2; _f0.X2, _f0.IP = _f0.X2+1, 7 {
+			for ; _f0.X1 < 2; _f0.X1, _f0.IP = _f0.X1+1, 7 {
+//line coroutine_durable.go:1227
 				switch {
 				case _f0.IP < 8:
-					_f0.X3 = 0
+//line coroutine.go:215
+					_f0.X2 = 0
+//line coroutine_durable.go:1232
 					_f0.IP = 8
 					fallthrough
 				case _f0.IP < 18:
+//line coroutine.go:215
 				_l2:
-					for ; _f0.X3 < 3; _f0.X3, _f0.IP = _f0.X3+1, 8 {
+					for ; _f0.X2 < 3; _f0.X2, _f0.IP = _f0.X2+1, 8 {
+//line coroutine_durable.go:1239
 						switch {
 						case _f0.IP < 9:
-							coroutine.Yield[int, any](_f0.X3)
+//line coroutine.go:216
+							coroutine.Yield[int, any](_f0.X2)
+//line coroutine_durable.go:1244
 							_f0.IP = 9
 							fallthrough
 						case _f0.IP < 18:
+//line coroutine.go:217
 							{
-								_f0.X4 = _f0.X3
+//line coroutine.go:217
+								_f0.X3 = _f0.X2
+//line coroutine_durable.go:1252
 								switch {
 								default:
+//line coroutine.go:218
 									{
-										_f0.X5 = _f0.X4 ==
+//line coroutine.go:218
+										_f0.X4 = _f0.X3 ==
 
 											0
-										if _f0.X5 {
+//line coroutine_durable.go:1261
+										if _f0.X4 {
+//line coroutine.go:219
 											continue _l2
 										} else {
-											_f0.X6 = _f0.X4 ==
+//line coroutine.go:220
+											_f0.X5 = _f0.X3 ==
 
 												1
-											if _f0.X6 {
+//line coroutine_durable.go:1270
+											if _f0.X5 {
+//line coroutine.go:221
 												{
-													_f0.X7 = _f0.X2
+//line coroutine.go:221
+													_f0.X6 = _f0.X1
+//line coroutine_durable.go:1276
 													switch {
 													default:
+//line coroutine.go:222
 														{
-															_f0.X8 = _f0.X7 ==
+//line coroutine.go:222
+															_f0.X7 = _f0.X6 ==
 
 																0
-															if _f0.X8 {
+//line coroutine_durable.go:1285
+															if _f0.X7 {
+//line coroutine.go:223
 																continue _l1
 															} else {
-																_f0.X9 = _f0.X7 ==
+//line coroutine.go:224
+																_f0.X8 = _f0.X6 ==
 
 																	1
-																if _f0.X9 {
+//line coroutine_durable.go:1294
+																if _f0.X8 {
+//line coroutine.go:225
 																	break _l1
 																}
 															}
@@ -1070,13 +1310,15 @@ func LoopBreakAndContinue(_ int) {
 			}
 		}
 	}
+//line coroutine_durable.go:1314
 }
 
 //go:noinline
 func RangeOverMaps(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:232
 	var _f0 *struct {
-		IP  int
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeOverMaps@f783f24fa9201e8f"`
 		X0  int
 		X1  map[int]int
 		X2  map[int]int
@@ -1105,7 +1347,7 @@ func RangeOverMaps(_fn0 int) {
 		X23 int
 		X24 bool
 	} = coroutine.Push[struct {
-		IP  int
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeOverMaps@f783f24fa9201e8f"`
 		X0  int
 		X1  map[int]int
 		X2  map[int]int
@@ -1134,9 +1376,11 @@ func RangeOverMaps(_fn0 int) {
 		X23 int
 		X24 bool
 	}](&_c.Stack)
+//line coroutine_durable.go:1380
 	if _f0.IP == 0 {
+//line coroutine.go:232
 		*_f0 = struct {
-			IP  int
+			IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeOverMaps@f783f24fa9201e8f"`
 			X0  int
 			X1  map[int]int
 			X2  map[int]int
@@ -1166,6 +1410,7 @@ func RangeOverMaps(_fn0 int) {
 			X24 bool
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:1414
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -1173,35 +1418,47 @@ func RangeOverMaps(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:233
 		_f0.X1 = map[int]int{}
+//line coroutine_durable.go:1424
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:234
 		for range _f0.X1 {
 			panic("unreachable")
 		}
+//line coroutine_durable.go:1432
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 4:
+//line coroutine.go:237
 		for _ = range _f0.X1 {
 			panic("unreachable")
 		}
+//line coroutine_durable.go:1440
 		_f0.IP = 4
 		fallthrough
 	case _f0.IP < 5:
+//line coroutine.go:240
 		for _, _ = range _f0.X1 {
 			panic("unreachable")
 		}
+//line coroutine_durable.go:1448
 		_f0.IP = 5
 		fallthrough
 	case _f0.IP < 6:
+//line coroutine.go:243
 		_f0.X1[_f0.X0] = _f0.X0 * 10
+//line coroutine_durable.go:1454
 		_f0.IP = 6
 		fallthrough
 	case _f0.IP < 9:
 		switch {
 		case _f0.IP < 7:
+//line coroutine.go:244
 			_f0.X2 = _f0.X1
+//line coroutine_durable.go:1462
 			_f0.IP = 7
 			fallthrough
 		case _f0.IP < 9:
@@ -1212,17 +1469,21 @@ func RangeOverMaps(_fn0 int) {
 				fallthrough
 			case _f0.IP < 9:
 				for ; _f0.X3 < len(_f0.X2); _f0.X3, _f0.IP = _f0.X3+1, 8 {
+//line coroutine.go:245
 
 					coroutine.Yield[int, any](0)
 				}
 			}
 		}
+//line coroutine_durable.go:1479
 		_f0.IP = 9
 		fallthrough
 	case _f0.IP < 17:
 		switch {
 		case _f0.IP < 10:
+//line coroutine.go:247
 			_f0.X4 = _f0.X1
+//line coroutine_durable.go:1487
 			_f0.IP = 10
 			fallthrough
 		case _f0.IP < 12:
@@ -1250,17 +1511,22 @@ func RangeOverMaps(_fn0 int) {
 					for ; _f0.X7 < len(_f0.X6); _f0.X7, _f0.IP = _f0.X7+1, 14 {
 						switch {
 						case _f0.IP < 15:
+//line coroutine.go:247
 							_f0.X8 = _f0.X6[_f0.X7]
+//line coroutine_durable.go:1517
 							_f0.IP = 15
 							fallthrough
 						case _f0.IP < 17:
 							switch {
 							case _f0.IP < 16:
+//line coroutine.go:247
 								_, _f0.X9 = _f0.X4[_f0.X8]
+//line coroutine_durable.go:1525
 								_f0.IP = 16
 								fallthrough
 							case _f0.IP < 17:
 								if _f0.X9 {
+//line coroutine.go:248
 
 									coroutine.Yield[int, any](_f0.X8)
 								}
@@ -1270,12 +1536,15 @@ func RangeOverMaps(_fn0 int) {
 				}
 			}
 		}
+//line coroutine_durable.go:1540
 		_f0.IP = 17
 		fallthrough
 	case _f0.IP < 26:
 		switch {
 		case _f0.IP < 18:
+//line coroutine.go:250
 			_f0.X10 = _f0.X1
+//line coroutine_durable.go:1548
 			_f0.IP = 18
 			fallthrough
 		case _f0.IP < 20:
@@ -1303,24 +1572,31 @@ func RangeOverMaps(_fn0 int) {
 					for ; _f0.X13 < len(_f0.X12); _f0.X13, _f0.IP = _f0.X13+1, 22 {
 						switch {
 						case _f0.IP < 23:
+//line coroutine.go:250
 							_f0.X14 = _f0.X12[_f0.X13]
+//line coroutine_durable.go:1578
 							_f0.IP = 23
 							fallthrough
 						case _f0.IP < 26:
 							switch {
 							case _f0.IP < 24:
+//line coroutine.go:250
 								_f0.X15, _f0.X16 = _f0.X10[_f0.X14]
+//line coroutine_durable.go:1586
 								_f0.IP = 24
 								fallthrough
 							case _f0.IP < 26:
 								if _f0.X16 {
 									switch {
 									case _f0.IP < 25:
+//line coroutine.go:251
 
 										coroutine.Yield[int, any](_f0.X14)
+//line coroutine_durable.go:1596
 										_f0.IP = 25
 										fallthrough
 									case _f0.IP < 26:
+//line coroutine.go:252
 										coroutine.Yield[int, any](_f0.X15)
 									}
 								}
@@ -1330,26 +1606,35 @@ func RangeOverMaps(_fn0 int) {
 				}
 			}
 		}
+//line coroutine_durable.go:1610
 		_f0.IP = 26
 		fallthrough
 	case _f0.IP < 27:
+//line coroutine.go:259
 		_f0.X17 = make(map[int]struct{}, _f0.X0)
+//line coroutine_durable.go:1616
 		_f0.IP = 27
 		fallthrough
 	case _f0.IP < 28:
+//line coroutine.go:260
 		for _f0.X18 = 0; _f0.X18 < _f0.X0; _f0.X18++ {
 			_f0.X17[_f0.X18] = struct{}{}
 		}
+//line coroutine_durable.go:1624
 		_f0.IP = 28
 		fallthrough
 	case _f0.IP < 29:
+//line coroutine.go:263
 		coroutine.Yield[int, any](len(_f0.X17))
+//line coroutine_durable.go:1630
 		_f0.IP = 29
 		fallthrough
 	case _f0.IP < 38:
 		switch {
 		case _f0.IP < 30:
+//line coroutine.go:264
 			_f0.X19 = _f0.X17
+//line coroutine_durable.go:1638
 			_f0.IP = 30
 			fallthrough
 		case _f0.IP < 32:
@@ -1377,24 +1662,31 @@ func RangeOverMaps(_fn0 int) {
 					for ; _f0.X22 < len(_f0.X21); _f0.X22, _f0.IP = _f0.X22+1, 34 {
 						switch {
 						case _f0.IP < 35:
+//line coroutine.go:264
 							_f0.X23 = _f0.X21[_f0.X22]
+//line coroutine_durable.go:1668
 							_f0.IP = 35
 							fallthrough
 						case _f0.IP < 38:
 							switch {
 							case _f0.IP < 36:
+//line coroutine.go:264
 								_, _f0.X24 = _f0.X19[_f0.X23]
+//line coroutine_durable.go:1676
 								_f0.IP = 36
 								fallthrough
 							case _f0.IP < 38:
 								if _f0.X24 {
 									switch {
 									case _f0.IP < 37:
+//line coroutine.go:265
 
 										delete(_f0.X17, _f0.X23)
+//line coroutine_durable.go:1686
 										_f0.IP = 37
 										fallthrough
 									case _f0.IP < 38:
+//line coroutine.go:266
 										coroutine.Yield[int, any](len(_f0.X17))
 									}
 								}
@@ -1405,30 +1697,35 @@ func RangeOverMaps(_fn0 int) {
 			}
 		}
 	}
+//line coroutine_durable.go:1701
 }
 
 //go:noinline
 func Range(_fn0 int, _fn1 func(int)) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:270
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range@40c5ac9c364c8d40"`
 		X0 int
 		X1 func(int)
 		X2 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range@40c5ac9c364c8d40"`
 		X0 int
 		X1 func(int)
 		X2 int
 	}](&_c.Stack)
+//line coroutine_durable.go:1719
 	if _f0.IP == 0 {
+//line coroutine.go:270
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range@40c5ac9c364c8d40"`
 			X0 int
 			X1 func(int)
 			X2 int
 		}{X0: _fn0, X1: _fn1}
 	}
+//line coroutine_durable.go:1729
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -1436,14 +1733,18 @@ func Range(_fn0 int, _fn1 func(int)) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:271
 		_f0.X2 = 0
+//line coroutine_durable.go:1739
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
 		for ; _f0.X2 < _f0.X0; _f0.X2, _f0.IP = _f0.X2+1, 2 {
+//line coroutine.go:272
 			_f0.X1(_f0.X2)
 		}
 	}
+//line coroutine_durable.go:1748
 }
 
 //go:noinline
@@ -1451,28 +1752,34 @@ func Double(n int) { coroutine.Yield[int, any](2 * n) }
 
 //go:noinline
 func RangeTriple(n int) {
+//line coroutine.go:281
 	Range(n, func(i int) { coroutine.Yield[int, any](3 * i) })
+//line coroutine_durable.go:1758
 }
 
 //go:noinline
 func RangeTripleFuncValue(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:286
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeTripleFuncValue@30a2b825a9a2e158"`
 		X0 int
 		X1 func(int)
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeTripleFuncValue@30a2b825a9a2e158"`
 		X0 int
 		X1 func(int)
 	}](&_c.Stack)
+//line coroutine_durable.go:1774
 	if _f0.IP == 0 {
+//line coroutine.go:286
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeTripleFuncValue@30a2b825a9a2e158"`
 			X0 int
 			X1 func(int)
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:1783
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -1480,37 +1787,45 @@ func RangeTripleFuncValue(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:287
 		_f0.X1 = func(i int) { coroutine.Yield[int, any](3 * i) }
+//line coroutine_durable.go:1793
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:290
 
 		Range(_f0.X0, _f0.X1)
 	}
+//line coroutine_durable.go:1801
 }
 
 //go:noinline
 func RangeReverseClosureCaptureByValue(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:293
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeReverseClosureCaptureByValue@f0643c5b617867a9"`
 		X0 int
 		X1 int
 		X2 func()
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeReverseClosureCaptureByValue@f0643c5b617867a9"`
 		X0 int
 		X1 int
 		X2 func()
 	}](&_c.Stack)
+//line coroutine_durable.go:1819
 	if _f0.IP == 0 {
+//line coroutine.go:293
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeReverseClosureCaptureByValue@f0643c5b617867a9"`
 			X0 int
 			X1 int
 			X2 func()
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:1829
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -1518,39 +1833,47 @@ func RangeReverseClosureCaptureByValue(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:294
 		_f0.X1 = 0
+//line coroutine_durable.go:1839
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:295
 		_f0.X2 = func() { coroutine.Yield[int, any](_f0.X0 - (_f0.X1 + 1)) }
+//line coroutine_durable.go:1845
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 5:
 		for ; _f0.X1 < _f0.X0; _f0.IP = 3 {
 			switch {
 			case _f0.IP < 4:
+//line coroutine.go:300
 				_f0.X2()
+//line coroutine_durable.go:1854
 				_f0.IP = 4
 				fallthrough
 			case _f0.IP < 5:
+//line coroutine.go:301
 				_f0.X1++
 			}
 		}
 	}
+//line coroutine_durable.go:1863
 }
 
 //go:noinline
 func Range10ClosureCapturingValues() {
 	_c := coroutine.LoadContext[int, any]()
 	var _f1 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues@75f89957a2e7d54a"`
 		X0 int
 		X1 int
 		X2 func() bool
 		X3 bool
 		X4 bool
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues@75f89957a2e7d54a"`
 		X0 int
 		X1 int
 		X2 func() bool
@@ -1559,7 +1882,7 @@ func Range10ClosureCapturingValues() {
 	}](&_c.Stack)
 	if _f1.IP == 0 {
 		*_f1 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues@75f89957a2e7d54a"`
 			X0 int
 			X1 int
 			X2 func() bool
@@ -1574,24 +1897,30 @@ func Range10ClosureCapturingValues() {
 	}()
 	switch {
 	case _f1.IP < 2:
+//line coroutine.go:306
 		_f1.X0 = 0
+//line coroutine_durable.go:1903
 		_f1.IP = 2
 		fallthrough
 	case _f1.IP < 3:
+//line coroutine.go:307
 		_f1.X1 = 10
+//line coroutine_durable.go:1909
 		_f1.IP = 3
 		fallthrough
 	case _f1.IP < 4:
+//line coroutine.go:308
 		_f1.X2 = func() (_ bool) {
+//line coroutine_durable.go:1915
 			_c := coroutine.LoadContext[int, any]()
 			var _f0 *struct {
-				IP int
+				IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues@11f7b3a23c59b515"`
 			} = coroutine.Push[struct {
-				IP int
+				IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues@11f7b3a23c59b515"`
 			}](&_c.Stack)
 			if _f0.IP == 0 {
 				*_f0 = struct {
-					IP int
+					IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues@11f7b3a23c59b515"`
 				}{}
 			}
 			defer func() {
@@ -1604,37 +1933,51 @@ func Range10ClosureCapturingValues() {
 				if _f1.X0 < _f1.X1 {
 					switch {
 					case _f0.IP < 2:
+//line coroutine.go:310
 						coroutine.Yield[int, any](_f1.X0)
+//line coroutine_durable.go:1939
 						_f0.IP = 2
 						fallthrough
 					case _f0.IP < 3:
+//line coroutine.go:311
 						_f1.X0++
+//line coroutine_durable.go:1945
 						_f0.IP = 3
 						fallthrough
 					case _f0.IP < 4:
+//line coroutine.go:312
 						return true
 					}
 				}
+//line coroutine_durable.go:1953
 				_f0.IP = 4
 				fallthrough
 			case _f0.IP < 5:
+//line coroutine.go:314
 
 				return false
 			}
+//line coroutine_durable.go:1961
 			panic("unreachable")
 		}
 		_f1.IP = 4
 		fallthrough
 	case _f1.IP < 7:
+//line coroutine.go:317
 	_l0:
 		for ; ; _f1.IP = 4 {
+//line coroutine_durable.go:1970
 			switch {
 			case _f1.IP < 5:
+//line coroutine.go:317
 				_f1.X3 = _f1.X2()
+//line coroutine_durable.go:1975
 				_f1.IP = 5
 				fallthrough
 			case _f1.IP < 6:
+//line coroutine.go:317
 				_f1.X4 = !_f1.X3
+//line coroutine_durable.go:1981
 				_f1.IP = 6
 				fallthrough
 			case _f1.IP < 7:
@@ -1650,34 +1993,30 @@ func Range10ClosureCapturingValues() {
 func Range10ClosureCapturingPointers() {
 	_c := coroutine.LoadContext[int, any]()
 	var _f1 *struct {
-		IP int
-		X0 int
-		X1 int
-		X2 *int
-		X3 *int
-		X4 func() bool
-		X5 bool
-		X6 bool
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers@9aad2ee0908977dc"`
+		X0 *int
+		X1 *int
+		X2 func() bool
+		X3 bool
+		X4 bool
 	} = coroutine.Push[struct {
-		IP int
-		X0 int
-		X1 int
-		X2 *int
-		X3 *int
-		X4 func() bool
-		X5 bool
-		X6 bool
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers@9aad2ee0908977dc"`
+		X0 *int
+		X1 *int
+		X2 func() bool
+		X3 bool
+		X4 bool
 	}](&_c.Stack)
+	var _o0 int
+	var _o1 int
 	if _f1.IP == 0 {
 		*_f1 = struct {
-			IP int
-			X0 int
-			X1 int
-			X2 *int
-			X3 *int
-			X4 func() bool
-			X5 bool
-			X6 bool
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers@9aad2ee0908977dc"`
+			X0 *int
+			X1 *int
+			X2 func() bool
+			X3 bool
+			X4 bool
 		}{}
 	}
 	defer func() {
@@ -1687,28 +2026,36 @@ func Range10ClosureCapturingPointers() {
 	}()
 	switch {
 	case _f1.IP < 2:
-		_f1.X0, _f1.X1 = 0, 10
+//line coroutine.go:322
+		_o0, _o1 = 0, 10
+//line coroutine_durable.go:2032
 		_f1.IP = 2
 		fallthrough
 	case _f1.IP < 3:
-		_f1.X2 = &_f1.X0
+//line coroutine.go:323
+		_f1.X0 = &_o0
+//line coroutine_durable.go:2038
 		_f1.IP = 3
 		fallthrough
 	case _f1.IP < 4:
-		_f1.X3 = &_f1.X1
+//line coroutine.go:324
+		_f1.X1 = &_o1
+//line coroutine_durable.go:2044
 		_f1.IP = 4
 		fallthrough
 	case _f1.IP < 5:
-		_f1.X4 = func() (_ bool) {
+//line coroutine.go:325
+		_f1.X2 = func() (_ bool) {
+//line coroutine_durable.go:2050
 			_c := coroutine.LoadContext[int, any]()
 			var _f0 *struct {
-				IP int
+				IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers@313a80ad44866651"`
 			} = coroutine.Push[struct {
-				IP int
+				IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers@313a80ad44866651"`
 			}](&_c.Stack)
 			if _f0.IP == 0 {
 				*_f0 = struct {
-					IP int
+					IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers@313a80ad44866651"`
 				}{}
 			}
 			defer func() {
@@ -1718,44 +2065,60 @@ func Range10ClosureCapturingPointers() {
 			}()
 			switch {
 			case _f0.IP < 4:
-				if *_f1.X2 < *_f1.X3 {
+//line coroutine.go:326
+				if *_f1.X0 < *_f1.X1 {
+//line coroutine_durable.go:2071
 					switch {
 					case _f0.IP < 2:
-						coroutine.Yield[int, any](*_f1.X2)
+//line coroutine.go:327
+						coroutine.Yield[int, any](*_f1.X0)
+//line coroutine_durable.go:2076
 						_f0.IP = 2
 						fallthrough
 					case _f0.IP < 3:
-						(*_f1.X2)++
+//line coroutine.go:328
+						(*_f1.X0)++
+//line coroutine_durable.go:2082
 						_f0.IP = 3
 						fallthrough
 					case _f0.IP < 4:
+//line coroutine.go:329
 						return true
 					}
 				}
+//line coroutine_durable.go:2090
 				_f0.IP = 4
 				fallthrough
 			case _f0.IP < 5:
+//line coroutine.go:331
 
 				return false
 			}
+//line coroutine_durable.go:2098
 			panic("unreachable")
 		}
 		_f1.IP = 5
 		fallthrough
 	case _f1.IP < 8:
+//line coroutine.go:334
 	_l0:
 		for ; ; _f1.IP = 5 {
+//line coroutine_durable.go:2107
 			switch {
 			case _f1.IP < 6:
-				_f1.X5 = _f1.X4()
+//line coroutine.go:334
+				_f1.X3 = _f1.X2()
+//line coroutine_durable.go:2112
 				_f1.IP = 6
 				fallthrough
 			case _f1.IP < 7:
-				_f1.X6 = !_f1.X5
+//line coroutine.go:334
+				_f1.X4 = !_f1.X3
+//line coroutine_durable.go:2118
 				_f1.IP = 7
 				fallthrough
 			case _f1.IP < 8:
-				if _f1.X6 {
+				if _f1.X4 {
 					break _l0
 				}
 			}
@@ -1767,7 +2130,7 @@ func Range10ClosureCapturingPointers() {
 func Range10ClosureHeterogenousCapture() {
 	_c := coroutine.LoadContext[int, any]()
 	var _f1 *struct {
-		IP  int
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture@53399881f6748171"`
 		X0  int8
 		X1  int16
 		X2  int32
@@ -1783,7 +2146,7 @@ func Range10ClosureHeterogenousCapture() {
 		X12 bool
 		X13 bool
 	} = coroutine.Push[struct {
-		IP  int
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture@53399881f6748171"`
 		X0  int8
 		X1  int16
 		X2  int32
@@ -1801,7 +2164,7 @@ func Range10ClosureHeterogenousCapture() {
 	}](&_c.Stack)
 	if _f1.IP == 0 {
 		*_f1 = struct {
-			IP  int
+			IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture@53399881f6748171"`
 			X0  int8
 			X1  int16
 			X2  int32
@@ -1825,7 +2188,9 @@ func Range10ClosureHeterogenousCapture() {
 	}()
 	switch {
 	case _f1.IP < 11:
+//line coroutine.go:340
 		{
+//line coroutine.go:340
 			_f1.X0 = 0
 			_f1.X1 = 1
 			_f1.X2 = 2
@@ -1835,19 +2200,24 @@ func Range10ClosureHeterogenousCapture() {
 			_f1.X6 = 6
 			_f1.X7 = 7
 			_f1.X8 = 8
+//line coroutine_durable.go:2204
 			_f1.X9 = func() int { return int(_f1.X8) + 1 }
 		}
 		_f1.IP = 11
 		fallthrough
 	case _f1.IP < 12:
+//line coroutine.go:352
 		_f1.X10 = 0
+//line coroutine_durable.go:2212
 		_f1.IP = 12
 		fallthrough
 	case _f1.IP < 13:
+//line coroutine.go:353
 		_f1.X11 = func() (_ bool) {
+//line coroutine_durable.go:2218
 			_c := coroutine.LoadContext[int, any]()
 			var _f0 *struct {
-				IP  int
+				IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture@3426ebc46755e597"`
 				X0  int
 				X1  int
 				X2  bool
@@ -1861,7 +2231,7 @@ func Range10ClosureHeterogenousCapture() {
 				X10 bool
 				X11 bool
 			} = coroutine.Push[struct {
-				IP  int
+				IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture@3426ebc46755e597"`
 				X0  int
 				X1  int
 				X2  bool
@@ -1877,7 +2247,7 @@ func Range10ClosureHeterogenousCapture() {
 			}](&_c.Stack)
 			if _f0.IP == 0 {
 				*_f0 = struct {
-					IP  int
+					IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture@3426ebc46755e597"`
 					X0  int
 					X1  int
 					X2  bool
@@ -1904,42 +2274,55 @@ func Range10ClosureHeterogenousCapture() {
 			case _f0.IP < 13:
 				switch {
 				case _f0.IP < 3:
+//line coroutine.go:355
 					_f0.X1 = _f1.X10
+//line coroutine_durable.go:2280
 					_f0.IP = 3
 					fallthrough
 				case _f0.IP < 13:
 					switch {
 					default:
+//line coroutine.go:356
 						if _f0.X2 = _f0.X1 ==
 
 							0; _f0.X2 {
+//line coroutine.go:357
 							_f0.X0 = int(_f1.X0)
 						} else if _f0.X3 = _f0.X1 ==
 							1; _f0.X3 {
+//line coroutine.go:359
 							_f0.X0 = int(_f1.X1)
 						} else if _f0.X4 = _f0.X1 ==
 							2; _f0.X4 {
+//line coroutine.go:361
 							_f0.X0 = int(_f1.X2)
 						} else if _f0.X5 = _f0.X1 ==
 							3; _f0.X5 {
+//line coroutine.go:363
 							_f0.X0 = int(_f1.X3)
 						} else if _f0.X6 = _f0.X1 ==
 							4; _f0.X6 {
+//line coroutine.go:365
 							_f0.X0 = int(_f1.X4)
 						} else if _f0.X7 = _f0.X1 ==
 							5; _f0.X7 {
+//line coroutine.go:367
 							_f0.X0 = int(_f1.X5)
 						} else if _f0.X8 = _f0.X1 ==
 							6; _f0.X8 {
+//line coroutine.go:369
 							_f0.X0 = int(_f1.X6)
 						} else if _f0.X9 = _f0.X1 ==
 							7; _f0.X9 {
+//line coroutine.go:371
 							_f0.X0 = int(_f1.X7)
 						} else if _f0.X10 = _f0.X1 ==
 							8; _f0.X10 {
+//line coroutine.go:373
 							_f0.X0 = int(_f1.X8)
 						} else if _f0.X11 = _f0.X1 ==
 							9; _f0.X11 {
+//line coroutine_durable.go:2326
 							_f0.X0 = _f1.X9()
 						}
 					}
@@ -1947,31 +2330,43 @@ func Range10ClosureHeterogenousCapture() {
 				_f0.IP = 13
 				fallthrough
 			case _f0.IP < 14:
+//line coroutine.go:377
 
 				coroutine.Yield[int, any](_f0.X0)
+//line coroutine_durable.go:2337
 				_f0.IP = 14
 				fallthrough
 			case _f0.IP < 15:
+//line coroutine.go:378
 				_f1.X10++
+//line coroutine_durable.go:2343
 				_f0.IP = 15
 				fallthrough
 			case _f0.IP < 16:
+//line coroutine.go:379
 				return _f1.X10 < 10
 			}
+//line coroutine_durable.go:2350
 			panic("unreachable")
 		}
 		_f1.IP = 13
 		fallthrough
 	case _f1.IP < 16:
+//line coroutine.go:382
 	_l0:
 		for ; ; _f1.IP = 13 {
+//line coroutine_durable.go:2359
 			switch {
 			case _f1.IP < 14:
+//line coroutine.go:382
 				_f1.X12 = _f1.X11()
+//line coroutine_durable.go:2364
 				_f1.IP = 14
 				fallthrough
 			case _f1.IP < 15:
+//line coroutine.go:382
 				_f1.X13 = !_f1.X12
+//line coroutine_durable.go:2370
 				_f1.IP = 15
 				fallthrough
 			case _f1.IP < 16:
@@ -1987,7 +2382,7 @@ func Range10ClosureHeterogenousCapture() {
 func Range10Heterogenous() {
 	_c := coroutine.LoadContext[int, any]()
 	var _f0 *struct {
-		IP  int
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10Heterogenous@70be744490c3d6da"`
 		X0  int8
 		X1  int16
 		X2  int32
@@ -2000,7 +2395,7 @@ func Range10Heterogenous() {
 		X9  int
 		X10 int
 	} = coroutine.Push[struct {
-		IP  int
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10Heterogenous@70be744490c3d6da"`
 		X0  int8
 		X1  int16
 		X2  int32
@@ -2015,7 +2410,7 @@ func Range10Heterogenous() {
 	}](&_c.Stack)
 	if _f0.IP == 0 {
 		*_f0 = struct {
-			IP  int
+			IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10Heterogenous@70be744490c3d6da"`
 			X0  int8
 			X1  int16
 			X2  int32
@@ -2036,7 +2431,9 @@ func Range10Heterogenous() {
 	}()
 	switch {
 	case _f0.IP < 10:
+//line coroutine.go:388
 		{
+//line coroutine.go:388
 			_f0.X0 = 0
 			_f0.X1 = 1
 			_f0.X2 = 2
@@ -2047,24 +2444,31 @@ func Range10Heterogenous() {
 			_f0.X7 = 7
 			_f0.X8 = 8
 		}
+//line coroutine_durable.go:2448
 		_f0.IP = 10
 		fallthrough
 	case _f0.IP < 23:
 		switch {
 		case _f0.IP < 11:
+//line coroutine.go:399
 			_f0.X9 = 0
+//line coroutine_durable.go:2456
 			_f0.IP = 11
 			fallthrough
 		case _f0.IP < 23:
+//line coroutine.go:399
 			for ; _f0.X9 < 10; _f0.X9, _f0.IP = _f0.X9+1, 11 {
+//line coroutine_durable.go:2462
 				switch {
 				case _f0.IP < 12:
 					_f0.IP = 12
 					fallthrough
 				case _f0.IP < 22:
+//line coroutine.go:401
 
 					switch _f0.X9 {
 					case 0:
+//line coroutine.go:403
 						_f0.X10 = int(_f0.X0)
 					case 1:
 						_f0.X10 = int(_f0.X1)
@@ -2085,21 +2489,25 @@ func Range10Heterogenous() {
 					case 9:
 						_f0.X10 = int(_f0.X9)
 					}
+//line coroutine_durable.go:2493
 					_f0.IP = 22
 					fallthrough
 				case _f0.IP < 23:
+//line coroutine.go:423
 					coroutine.Yield[int, any](_f0.X10)
 				}
 			}
 		}
 	}
+//line coroutine_durable.go:2503
 }
 
 //go:noinline
 func Select(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:427
 	var _f0 *struct {
-		IP  int
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Select@179080c426ded742"`
 		X0  int
 		X1  int
 		X2  int
@@ -2121,7 +2529,7 @@ func Select(_fn0 int) {
 		X18 bool
 		X19 int
 	} = coroutine.Push[struct {
-		IP  int
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Select@179080c426ded742"`
 		X0  int
 		X1  int
 		X2  int
@@ -2143,9 +2551,11 @@ func Select(_fn0 int) {
 		X18 bool
 		X19 int
 	}](&_c.Stack)
+//line coroutine_durable.go:2555
 	if _f0.IP == 0 {
+//line coroutine.go:427
 		*_f0 = struct {
-			IP  int
+			IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Select@179080c426ded742"`
 			X0  int
 			X1  int
 			X2  int
@@ -2168,6 +2578,7 @@ func Select(_fn0 int) {
 			X19 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:2582
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -2203,6 +2614,7 @@ func Select(_fn0 int) {
 						fallthrough
 					case _f0.IP < 6:
 						if _f0.X3 {
+//line coroutine.go:430
 
 							coroutine.Yield[int, any](-1)
 						}
@@ -2210,12 +2622,15 @@ func Select(_fn0 int) {
 				}
 			}
 		}
+//line coroutine_durable.go:2626
 		_f0.IP = 6
 		fallthrough
 	case _f0.IP < 24:
 		switch {
 		case _f0.IP < 7:
+//line coroutine.go:433
 			_f0.X4 = 0
+//line coroutine_durable.go:2634
 			_f0.IP = 7
 			fallthrough
 		case _f0.IP < 24:
@@ -2228,11 +2643,15 @@ func Select(_fn0 int) {
 						_f0.IP = 8
 						fallthrough
 					case _f0.IP < 9:
+//line coroutine.go:435
 						_f0.X6 = time.After(0)
+//line coroutine_durable.go:2649
 						_f0.IP = 9
 						fallthrough
 					case _f0.IP < 10:
+//line coroutine.go:440
 						_f0.X7 = time.After(1 * time.Second)
+//line coroutine_durable.go:2655
 						_f0.IP = 10
 						fallthrough
 					case _f0.IP < 12:
@@ -2263,17 +2682,22 @@ func Select(_fn0 int) {
 									if _f0.X9 {
 										switch {
 										case _f0.IP < 15:
+//line coroutine.go:436
 											if _f0.X4 >=
 												5 {
+//line coroutine.go:437
 												break _l2
 											}
+//line coroutine_durable.go:2692
 											_f0.IP = 15
 											fallthrough
 										case _f0.IP < 16:
+//line coroutine.go:439
 
 											coroutine.Yield[int, any](_f0.X4)
 										}
 									} else if _f0.X10 = _f0.X8 == 2; _f0.X10 {
+//line coroutine.go:441
 
 										panic("unreachable")
 									}
@@ -2281,6 +2705,7 @@ func Select(_fn0 int) {
 							}
 						}
 					}
+//line coroutine_durable.go:2709
 					_f0.IP = 17
 					fallthrough
 				case _f0.IP < 24:
@@ -2290,7 +2715,9 @@ func Select(_fn0 int) {
 						_f0.IP = 18
 						fallthrough
 					case _f0.IP < 19:
+//line coroutine.go:446
 						_f0.X12 = time.After(0)
+//line coroutine_durable.go:2721
 						_f0.IP = 19
 						fallthrough
 					case _f0.IP < 20:
@@ -2319,13 +2746,17 @@ func Select(_fn0 int) {
 									if _f0.X14 {
 										switch {
 										case _f0.IP < 23:
+//line coroutine.go:447
 											if _f0.X4 >=
 												6 {
+//line coroutine.go:448
 												break _l3
 											}
+//line coroutine_durable.go:2756
 											_f0.IP = 23
 											fallthrough
 										case _f0.IP < 24:
+//line coroutine.go:450
 
 											coroutine.Yield[int, any](_f0.X4 * 10)
 										}
@@ -2337,6 +2768,7 @@ func Select(_fn0 int) {
 				}
 			}
 		}
+//line coroutine_durable.go:2772
 		_f0.IP = 24
 		fallthrough
 	case _f0.IP < 31:
@@ -2346,7 +2778,9 @@ func Select(_fn0 int) {
 			_f0.IP = 25
 			fallthrough
 		case _f0.IP < 26:
+//line coroutine.go:455
 			_f0.X16 = time.After(0)
+//line coroutine_durable.go:2784
 			_f0.IP = 26
 			fallthrough
 		case _f0.IP < 27:
@@ -2374,10 +2808,13 @@ func Select(_fn0 int) {
 						if _f0.X18 {
 							switch {
 							case _f0.IP < 30:
+//line coroutine.go:456
 								_f0.X19 = 0
+//line coroutine_durable.go:2814
 								_f0.IP = 30
 								fallthrough
 							case _f0.IP < 31:
+//line coroutine.go:456
 								for ; _f0.X19 < 3; _f0.X19, _f0.IP = _f0.X19+1, 30 {
 									coroutine.Yield[int, any](_f0.X19)
 								}
@@ -2388,13 +2825,14 @@ func Select(_fn0 int) {
 			}
 		}
 	}
+//line coroutine_durable.go:2829
 }
 
 //go:noinline
 func YieldingExpressionDesugaring() {
 	_c := coroutine.LoadContext[int, any]()
 	var _f0 *struct {
-		IP  int
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingExpressionDesugaring@886ef314e15a6e12"`
 		X0  int
 		X1  int
 		X2  int
@@ -2422,24 +2860,23 @@ func YieldingExpressionDesugaring() {
 		X24 int
 		X25 int
 		X26 int
-		X27 int
-		X28 bool
+		X27 bool
+		X28 int
 		X29 int
-		X30 int
-		X31 bool
+		X30 bool
+		X31 int
 		X32 int
 		X33 int
-		X34 int
-		X35 bool
+		X34 bool
+		X35 int
 		X36 int
 		X37 int
-		X38 int
-		X39 bool
+		X38 bool
+		X39 int
 		X40 int
-		X41 int
-		X42 any
+		X41 any
 	} = coroutine.Push[struct {
-		IP  int
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingExpressionDesugaring@886ef314e15a6e12"`
 		X0  int
 		X1  int
 		X2  int
@@ -2467,26 +2904,26 @@ func YieldingExpressionDesugaring() {
 		X24 int
 		X25 int
 		X26 int
-		X27 int
-		X28 bool
+		X27 bool
+		X28 int
 		X29 int
-		X30 int
-		X31 bool
+		X30 bool
+		X31 int
 		X32 int
 		X33 int
-		X34 int
-		X35 bool
+		X34 bool
+		X35 int
 		X36 int
 		X37 int
-		X38 int
-		X39 bool
+		X38 bool
+		X39 int
 		X40 int
-		X41 int
-		X42 any
+		X41 any
 	}](&_c.Stack)
+	var _o0 int
 	if _f0.IP == 0 {
 		*_f0 = struct {
-			IP  int
+			IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingExpressionDesugaring@886ef314e15a6e12"`
 			X0  int
 			X1  int
 			X2  int
@@ -2514,22 +2951,21 @@ func YieldingExpressionDesugaring() {
 			X24 int
 			X25 int
 			X26 int
-			X27 int
-			X28 bool
+			X27 bool
+			X28 int
 			X29 int
-			X30 int
-			X31 bool
+			X30 bool
+			X31 int
 			X32 int
 			X33 int
-			X34 int
-			X35 bool
+			X34 bool
+			X35 int
 			X36 int
 			X37 int
-			X38 int
-			X39 bool
+			X38 bool
+			X39 int
 			X40 int
-			X41 int
-			X42 any
+			X41 any
 		}{}
 	}
 	defer func() {
@@ -2541,23 +2977,33 @@ func YieldingExpressionDesugaring() {
 	case _f0.IP < 21:
 		switch {
 		case _f0.IP < 2:
+//line coroutine.go:463
 			_f0.X0 = b(1)
+//line coroutine_durable.go:2983
 			_f0.IP = 2
 			fallthrough
 		case _f0.IP < 3:
+//line coroutine.go:463
 			_f0.X1 = a(_f0.X0)
+//line coroutine_durable.go:2989
 			_f0.IP = 3
 			fallthrough
 		case _f0.IP < 4:
+//line coroutine.go:463
 			_f0.X2 = b(2)
+//line coroutine_durable.go:2995
 			_f0.IP = 4
 			fallthrough
 		case _f0.IP < 5:
+//line coroutine.go:463
 			_f0.X3 = a(_f0.X2)
+//line coroutine_durable.go:3001
 			_f0.IP = 5
 			fallthrough
 		case _f0.IP < 6:
+//line coroutine.go:463
 			_f0.X4 = _f0.X1 == _f0.X3
+//line coroutine_durable.go:3007
 			_f0.IP = 6
 			fallthrough
 		case _f0.IP < 21:
@@ -2565,63 +3011,90 @@ func YieldingExpressionDesugaring() {
 			} else {
 				switch {
 				case _f0.IP < 8:
+//line coroutine.go:464
 					_f0.X5 = b(3)
+//line coroutine_durable.go:3017
 					_f0.IP = 8
 					fallthrough
 				case _f0.IP < 9:
+//line coroutine.go:464
 					_f0.X6 = a(_f0.X5)
+//line coroutine_durable.go:3023
 					_f0.IP = 9
 					fallthrough
 				case _f0.IP < 10:
+//line coroutine.go:464
 					_f0.X7 = b(4)
+//line coroutine_durable.go:3029
 					_f0.IP = 10
 					fallthrough
 				case _f0.IP < 11:
+//line coroutine.go:464
 					_f0.X8 = a(_f0.X7)
+//line coroutine_durable.go:3035
 					_f0.IP = 11
 					fallthrough
 				case _f0.IP < 12:
+//line coroutine.go:464
 					_f0.X9 = _f0.X8 - 1
+//line coroutine_durable.go:3041
 					_f0.IP = 12
 					fallthrough
 				case _f0.IP < 13:
+//line coroutine.go:464
 					_f0.X10 = _f0.X6 == _f0.X9
+//line coroutine_durable.go:3047
 					_f0.IP = 13
 					fallthrough
 				case _f0.IP < 21:
 					if _f0.X10 {
 						switch {
 						case _f0.IP < 14:
+//line coroutine.go:465
 							_f0.X11 = b(5)
+//line coroutine_durable.go:3056
 							_f0.IP = 14
 							fallthrough
 						case _f0.IP < 15:
+//line coroutine.go:465
 							_f0.X12 = a(_f0.X11)
+//line coroutine_durable.go:3062
 							_f0.IP = 15
 							fallthrough
 						case _f0.IP < 16:
+//line coroutine.go:465
 							_f0.X13 = _f0.X12 * 10
+//line coroutine_durable.go:3068
 							_f0.IP = 16
 							fallthrough
 						case _f0.IP < 17:
+//line coroutine.go:465
 							coroutine.Yield[int, any](_f0.X13)
 						}
 					} else {
+//line coroutine_durable.go:3076
 						switch {
 						case _f0.IP < 18:
+//line coroutine.go:466
 							_f0.X14 = b(100)
+//line coroutine_durable.go:3081
 							_f0.IP = 18
 							fallthrough
 						case _f0.IP < 19:
+//line coroutine.go:466
 							_f0.X15 = a(_f0.X14)
+//line coroutine_durable.go:3087
 							_f0.IP = 19
 							fallthrough
 						case _f0.IP < 20:
+//line coroutine.go:466
 							_f0.X16 = _f0.X15 == 100
+//line coroutine_durable.go:3093
 							_f0.IP = 20
 							fallthrough
 						case _f0.IP < 21:
 							if _f0.X16 {
+//line coroutine.go:467
 								panic("unreachable")
 							}
 						}
@@ -2629,16 +3102,21 @@ func YieldingExpressionDesugaring() {
 				}
 			}
 		}
+//line coroutine_durable.go:3106
 		_f0.IP = 21
 		fallthrough
 	case _f0.IP < 29:
 		switch {
 		case _f0.IP < 22:
+//line coroutine.go:471
 			_f0.X17 = b(6)
+//line coroutine_durable.go:3114
 			_f0.IP = 22
 			fallthrough
 		case _f0.IP < 23:
+//line coroutine.go:471
 			_f0.X18 = a(_f0.X17)
+//line coroutine_durable.go:3120
 			_f0.IP = 23
 			fallthrough
 		case _f0.IP < 29:
@@ -2648,19 +3126,27 @@ func YieldingExpressionDesugaring() {
 				case _f0.IP < 28:
 					switch {
 					case _f0.IP < 24:
+//line coroutine.go:471
 						_f0.X19 = b(8)
+//line coroutine_durable.go:3132
 						_f0.IP = 24
 						fallthrough
 					case _f0.IP < 25:
+//line coroutine.go:471
 						_f0.X20 = a(_f0.X19)
+//line coroutine_durable.go:3138
 						_f0.IP = 25
 						fallthrough
 					case _f0.IP < 26:
+//line coroutine.go:471
 						_f0.X21 = _f0.X18 < _f0.X20
+//line coroutine_durable.go:3144
 						_f0.IP = 26
 						fallthrough
 					case _f0.IP < 27:
+//line coroutine.go:471
 						_f0.X22 = !_f0.X21
+//line coroutine_durable.go:3150
 						_f0.IP = 27
 						fallthrough
 					case _f0.IP < 28:
@@ -2671,24 +3157,32 @@ func YieldingExpressionDesugaring() {
 					_f0.IP = 28
 					fallthrough
 				case _f0.IP < 29:
+//line coroutine.go:472
 					coroutine.Yield[int, any](70)
 				}
 			}
 		}
+//line coroutine_durable.go:3166
 		_f0.IP = 29
 		fallthrough
 	case _f0.IP < 51:
 		switch {
 		case _f0.IP < 30:
+//line coroutine.go:475
 			_f0.X23 = b(9)
+//line coroutine_durable.go:3174
 			_f0.IP = 30
 			fallthrough
 		case _f0.IP < 31:
-			_f0.X24 = a(_f0.X23)
+//line coroutine.go:475
+			_o0 = a(_f0.X23)
+//line coroutine_durable.go:3180
 			_f0.IP = 31
 			fallthrough
 		case _f0.IP < 32:
-			_f0.X25 = _f0.X24
+//line coroutine.go:475
+			_f0.X24 = _o0
+//line coroutine_durable.go:3186
 			_f0.IP = 32
 			fallthrough
 		case _f0.IP < 51:
@@ -2696,83 +3190,119 @@ func YieldingExpressionDesugaring() {
 			default:
 				switch {
 				case _f0.IP < 33:
-					_f0.X26 = b(10)
+//line coroutine.go:478
+					_f0.X25 = b(10)
+//line coroutine_durable.go:3196
 					_f0.IP = 33
 					fallthrough
 				case _f0.IP < 34:
-					_f0.X27 = a(_f0.X26)
+//line coroutine.go:478
+					_f0.X26 = a(_f0.X25)
+//line coroutine_durable.go:3202
 					_f0.IP = 34
 					fallthrough
 				case _f0.IP < 35:
-					_f0.X28 = _f0.X25 == _f0.X27
+//line coroutine.go:478
+					_f0.X27 = _f0.X24 == _f0.X26
+//line coroutine_durable.go:3208
 					_f0.IP = 35
 					fallthrough
 				case _f0.IP < 51:
-					if _f0.X28 {
+					if _f0.X27 {
+//line coroutine.go:479
 						panic("unreachable")
 					} else {
+//line coroutine_durable.go:3216
 						switch {
 						case _f0.IP < 37:
-							_f0.X29 = b(11)
+//line coroutine.go:480
+							_f0.X28 = b(11)
+//line coroutine_durable.go:3221
 							_f0.IP = 37
 							fallthrough
 						case _f0.IP < 38:
-							_f0.X30 = a(_f0.X29)
+//line coroutine.go:480
+							_f0.X29 = a(_f0.X28)
+//line coroutine_durable.go:3227
 							_f0.IP = 38
 							fallthrough
 						case _f0.IP < 39:
-							_f0.X31 = _f0.X25 == _f0.X30
+//line coroutine.go:480
+							_f0.X30 = _f0.X24 == _f0.X29
+//line coroutine_durable.go:3233
 							_f0.IP = 39
 							fallthrough
 						case _f0.IP < 51:
-							if _f0.X31 {
+							if _f0.X30 {
+//line coroutine.go:481
 								panic("unreachable")
 							} else {
+//line coroutine_durable.go:3241
 								switch {
 								case _f0.IP < 41:
-									_f0.X32 = b(12)
+//line coroutine.go:482
+									_f0.X31 = b(12)
+//line coroutine_durable.go:3246
 									_f0.IP = 41
 									fallthrough
 								case _f0.IP < 42:
-									_f0.X33 = a(_f0.X32)
+//line coroutine.go:482
+									_f0.X32 = a(_f0.X31)
+//line coroutine_durable.go:3252
 									_f0.IP = 42
 									fallthrough
 								case _f0.IP < 43:
-									_f0.X34 = _f0.X33 - 3
+//line coroutine.go:482
+									_f0.X33 = _f0.X32 - 3
+//line coroutine_durable.go:3258
 									_f0.IP = 43
 									fallthrough
 								case _f0.IP < 44:
-									_f0.X35 = _f0.X25 == _f0.X34
+//line coroutine.go:482
+									_f0.X34 = _f0.X24 == _f0.X33
+//line coroutine_durable.go:3264
 									_f0.IP = 44
 									fallthrough
 								case _f0.IP < 51:
-									if _f0.X35 {
+									if _f0.X34 {
 										switch {
 										case _f0.IP < 45:
-											_f0.X36 = b(13)
+//line coroutine.go:483
+											_f0.X35 = b(13)
+//line coroutine_durable.go:3273
 											_f0.IP = 45
 											fallthrough
 										case _f0.IP < 46:
-											a(_f0.X36)
+//line coroutine.go:483
+											a(_f0.X35)
 										}
 									} else {
+//line coroutine_durable.go:3281
 										switch {
 										case _f0.IP < 47:
-											_f0.X37 = b(14)
+//line coroutine.go:484
+											_f0.X36 = b(14)
+//line coroutine_durable.go:3286
 											_f0.IP = 47
 											fallthrough
 										case _f0.IP < 48:
-											_f0.X38 = a(_f0.X37)
+//line coroutine.go:484
+											_f0.X37 = a(_f0.X36)
+//line coroutine_durable.go:3292
 											_f0.IP = 48
 											fallthrough
 										case _f0.IP < 49:
-											_f0.X39 = _f0.X25 == _f0.X38
+//line coroutine.go:484
+											_f0.X38 = _f0.X24 == _f0.X37
+//line coroutine_durable.go:3298
 											_f0.IP = 49
 											fallthrough
 										case _f0.IP < 51:
-											if _f0.X39 {
+											if _f0.X38 {
+//line coroutine.go:485
 												panic("unreachable")
 											} else {
+//line coroutine.go:477
 												panic("unreachable")
 											}
 										}
@@ -2784,24 +3314,32 @@ func YieldingExpressionDesugaring() {
 				}
 			}
 		}
+//line coroutine_durable.go:3318
 		_f0.IP = 51
 		fallthrough
 	case _f0.IP < 57:
 		switch {
 		case _f0.IP < 52:
-			_f0.X40 = b(15)
+//line coroutine.go:488
+			_f0.X39 = b(15)
+//line coroutine_durable.go:3326
 			_f0.IP = 52
 			fallthrough
 		case _f0.IP < 53:
-			_f0.X41 = a(_f0.X40)
+//line coroutine.go:488
+			_f0.X40 = a(_f0.X39)
+//line coroutine_durable.go:3332
 			_f0.IP = 53
 			fallthrough
 		case _f0.IP < 54:
-			_f0.X42 = any(_f0.X41)
+//line coroutine.go:488
+			_f0.X41 = any(_f0.X40)
+//line coroutine_durable.go:3338
 			_f0.IP = 54
 			fallthrough
 		case _f0.IP < 57:
-			switch x := _f0.X42.(type) {
+//line coroutine.go:488
+			switch x := _f0.X41.(type) {
 			case bool:
 				panic("unreachable")
 			case int:
@@ -2811,24 +3349,29 @@ func YieldingExpressionDesugaring() {
 			}
 		}
 	}
+//line coroutine_durable.go:3353
 }
 
 //go:noinline
 func a(_fn0 int) (_ int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:500
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.a@4952375a366b8d89"`
 		X0 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.a@4952375a366b8d89"`
 		X0 int
 	}](&_c.Stack)
+//line coroutine_durable.go:3367
 	if _f0.IP == 0 {
+//line coroutine.go:500
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.a@4952375a366b8d89"`
 			X0 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:3375
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -2836,31 +3379,39 @@ func a(_fn0 int) (_ int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:501
 		coroutine.Yield[int, any](_f0.X0)
+//line coroutine_durable.go:3385
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:502
 		return _f0.X0
 	}
+//line coroutine_durable.go:3392
 	panic("unreachable")
 }
 
 //go:noinline
 func b(_fn0 int) (_ int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:505
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.b@d8fc9b93c2a1a2a9"`
 		X0 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.b@d8fc9b93c2a1a2a9"`
 		X0 int
 	}](&_c.Stack)
+//line coroutine_durable.go:3407
 	if _f0.IP == 0 {
+//line coroutine.go:505
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.b@d8fc9b93c2a1a2a9"`
 			X0 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:3415
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -2868,12 +3419,16 @@ func b(_fn0 int) (_ int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:506
 		coroutine.Yield[int, any](-_f0.X0)
+//line coroutine_durable.go:3425
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:507
 		return _f0.X0
 	}
+//line coroutine_durable.go:3432
 	panic("unreachable")
 }
 
@@ -2881,13 +3436,13 @@ func b(_fn0 int) (_ int) {
 func YieldingDurations() {
 	_c := coroutine.LoadContext[int, any]()
 	var _f1 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations@3f9d8b15962ed143"`
 		X0 *time.Duration
 		X1 time.Duration
 		X2 func()
 		X3 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations@3f9d8b15962ed143"`
 		X0 *time.Duration
 		X1 time.Duration
 		X2 func()
@@ -2895,7 +3450,7 @@ func YieldingDurations() {
 	}](&_c.Stack)
 	if _f1.IP == 0 {
 		*_f1 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations@3f9d8b15962ed143"`
 			X0 *time.Duration
 			X1 time.Duration
 			X2 func()
@@ -2909,34 +3464,42 @@ func YieldingDurations() {
 	}()
 	switch {
 	case _f1.IP < 2:
+//line coroutine.go:511
 		_f1.X0 = new(time.Duration)
+//line coroutine_durable.go:3470
 		_f1.IP = 2
 		fallthrough
 	case _f1.IP < 3:
+//line coroutine.go:512
 		_f1.X1 = time.Duration(100)
+//line coroutine_durable.go:3476
 		_f1.IP = 3
 		fallthrough
 	case _f1.IP < 4:
+//line coroutine.go:512
 		*_f1.X0 = _f1.X1
+//line coroutine_durable.go:3482
 		_f1.IP = 4
 		fallthrough
 	case _f1.IP < 5:
+//line coroutine.go:514
 		_f1.X2 = func() {
+//line coroutine_durable.go:3488
 			_c := coroutine.LoadContext[int, any]()
 			var _f0 *struct {
-				IP int
+				IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations@3ae61304e1366d0f"`
 				X0 int64
 				X1 int
 				X2 time.Duration
 			} = coroutine.Push[struct {
-				IP int
+				IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations@3ae61304e1366d0f"`
 				X0 int64
 				X1 int
 				X2 time.Duration
 			}](&_c.Stack)
 			if _f0.IP == 0 {
 				*_f0 = struct {
-					IP int
+					IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations@3ae61304e1366d0f"`
 					X0 int64
 					X1 int
 					X2 time.Duration
@@ -2949,67 +3512,85 @@ func YieldingDurations() {
 			}()
 			switch {
 			case _f0.IP < 2:
+//line coroutine.go:515
 				_f0.X0 = _f1.X0.
 					Nanoseconds()
+//line coroutine_durable.go:3519
 				_f0.IP = 2
 				fallthrough
 			case _f0.IP < 3:
+//line coroutine.go:515
 				_f0.X1 = int(_f0.X0)
+//line coroutine_durable.go:3525
 				_f0.IP = 3
 				fallthrough
 			case _f0.IP < 4:
+//line coroutine.go:516
 				_f0.X2 = time.Duration(_f0.X1 + 1)
+//line coroutine_durable.go:3531
 				_f0.IP = 4
 				fallthrough
 			case _f0.IP < 5:
+//line coroutine.go:516
 				*_f1.X0 = _f0.X2
+//line coroutine_durable.go:3537
 				_f0.IP = 5
 				fallthrough
 			case _f0.IP < 6:
+//line coroutine.go:517
 				coroutine.Yield[int, any](_f0.X1)
 			}
 		}
+//line coroutine_durable.go:3545
 		_f1.IP = 5
 		fallthrough
 	case _f1.IP < 7:
 		switch {
 		case _f1.IP < 6:
+//line coroutine.go:519
 			_f1.X3 = 0
+//line coroutine_durable.go:3553
 			_f1.IP = 6
 			fallthrough
 		case _f1.IP < 7:
+//line coroutine.go:519
 			for ; _f1.X3 < 10; _f1.X3, _f1.IP = _f1.X3+1, 6 {
 				_f1.X2()
 			}
 		}
 	}
+//line coroutine_durable.go:3563
 }
 
 //go:noinline
 func YieldAndDeferAssign(_fn0 *int, _fn1, _fn2 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:524
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldAndDeferAssign@e17c0ca722b4aa32"`
 		X0 *int
 		X1 int
 		X2 int
 		X3 []func()
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldAndDeferAssign@e17c0ca722b4aa32"`
 		X0 *int
 		X1 int
 		X2 int
 		X3 []func()
 	}](&_c.Stack)
+//line coroutine_durable.go:3583
 	if _f0.IP == 0 {
+//line coroutine.go:524
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldAndDeferAssign@e17c0ca722b4aa32"`
 			X0 *int
 			X1 int
 			X2 int
 			X3 []func()
 		}{X0: _fn0, X1: _fn1, X2: _fn2}
 	}
+//line coroutine_durable.go:3594
 	defer func() {
 		if !_c.Unwinding() {
 			defer coroutine.Pop(&_c.Stack)
@@ -3021,34 +3602,45 @@ func YieldAndDeferAssign(_fn0 *int, _fn1, _fn2 int) {
 	switch {
 	case _f0.IP < 2:
 		_f0.X3 = append(_f0.X3, func() {
+//line coroutine.go:526
 			*_f0.X0 = _f0.X2
 		})
+//line coroutine_durable.go:3609
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:528
 		coroutine.Yield[int, any](_f0.X1)
 	}
+//line coroutine_durable.go:3616
 }
 
 //go:noinline
 func RangeYieldAndDeferAssign(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:531
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeYieldAndDeferAssign@13e68f62d35f31e9"`
 		X0 int
 		X1 int
+		X2 *int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeYieldAndDeferAssign@13e68f62d35f31e9"`
 		X0 int
 		X1 int
+		X2 *int
 	}](&_c.Stack)
+//line coroutine_durable.go:3634
 	if _f0.IP == 0 {
+//line coroutine.go:531
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeYieldAndDeferAssign@13e68f62d35f31e9"`
 			X0 int
 			X1 int
+			X2 *int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:3644
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -3056,12 +3648,33 @@ func RangeYieldAndDeferAssign(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:532
 		_f0.X1 = 0
+//line coroutine_durable.go:3654
 		_f0.IP = 2
 		fallthrough
-	case _f0.IP < 3:
+	case _f0.IP < 6:
 		for ; _f0.X1 < _f0.X0; _f0.IP = 2 {
-			YieldAndDeferAssign(&_f0.X1, _f0.X1, _f0.X1+1)
+			switch {
+			case _f0.IP < 3:
+				_f0.X2 = new(int)
+				_f0.IP = 3
+				fallthrough
+			case _f0.IP < 4:
+//line coroutine.go:532
+				*_f0.X2 = _f0.X1
+//line coroutine_durable.go:3667
+				_f0.IP = 4
+				fallthrough
+			case _f0.IP < 5:
+//line coroutine.go:533
+				YieldAndDeferAssign(&*_f0.X2, *_f0.X2, *_f0.X2+1)
+//line coroutine_durable.go:3673
+				_f0.IP = 5
+				fallthrough
+			case _f0.IP < 6:
+				_f0.X1 = *_f0.X2
+			}
 		}
 	}
 }
@@ -3071,22 +3684,26 @@ type MethodGeneratorState struct{ i int }
 //go:noinline
 func (_fn0 *MethodGeneratorState) MethodGenerator(_fn1 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:539
 	var _f0 *struct {
-		IP int
+		IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.MethodGeneratorState).MethodGenerator@2be97cd35ecee81b"`
 		X0 *MethodGeneratorState
 		X1 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.MethodGeneratorState).MethodGenerator@2be97cd35ecee81b"`
 		X0 *MethodGeneratorState
 		X1 int
 	}](&_c.Stack)
+//line coroutine_durable.go:3698
 	if _f0.IP == 0 {
+//line coroutine.go:539
 		*_f0 = struct {
-			IP int
+			IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.MethodGeneratorState).MethodGenerator@2be97cd35ecee81b"`
 			X0 *MethodGeneratorState
 			X1 int
 		}{X0: _fn0, X1: _fn1}
 	}
+//line coroutine_durable.go:3707
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -3094,36 +3711,44 @@ func (_fn0 *MethodGeneratorState) MethodGenerator(_fn1 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:540
 		_f0.X0.
 			i = 0
+//line coroutine_durable.go:3718
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:540
 		for ; _f0.X0.i <= _f0.X1; _f0.X0.i, _f0.IP = _f0.X0.i+1, 2 {
 			coroutine.Yield[int, any](_f0.X0.i)
 		}
 	}
+//line coroutine_durable.go:3727
 }
 
 //go:noinline
 func VarArgs(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:545
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.VarArgs@9525cd9dd0377507"`
 		X0 int
 		X1 []int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.VarArgs@9525cd9dd0377507"`
 		X0 int
 		X1 []int
 	}](&_c.Stack)
+//line coroutine_durable.go:3743
 	if _f0.IP == 0 {
+//line coroutine.go:545
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.VarArgs@9525cd9dd0377507"`
 			X0 int
 			X1 []int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:3752
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -3131,45 +3756,55 @@ func VarArgs(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:546
 		_f0.X1 = make([]int, _f0.X0)
+//line coroutine_durable.go:3762
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:547
 		for i := range _f0.X1 {
 			_f0.X1[i] = i
 		}
+//line coroutine_durable.go:3770
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 4:
+//line coroutine.go:550
 		varArgs(_f0.X1...)
 	}
+//line coroutine_durable.go:3777
 }
 
 //go:noinline
 func varArgs(_fn0 ...int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:553
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.varArgs@5d0430e3ba77537f"`
 		X0 []int
 		X1 []int
 		X2 int
 		X3 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.varArgs@5d0430e3ba77537f"`
 		X0 []int
 		X1 []int
 		X2 int
 		X3 int
 	}](&_c.Stack)
+//line coroutine_durable.go:3797
 	if _f0.IP == 0 {
+//line coroutine.go:553
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.varArgs@5d0430e3ba77537f"`
 			X0 []int
 			X1 []int
 			X2 int
 			X3 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:3808
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -3177,7 +3812,9 @@ func varArgs(_fn0 ...int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:554
 		_f0.X1 = _f0.X0
+//line coroutine_durable.go:3818
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 5:
@@ -3190,34 +3827,42 @@ func varArgs(_fn0 ...int) {
 			for ; _f0.X2 < len(_f0.X1); _f0.X2, _f0.IP = _f0.X2+1, 3 {
 				switch {
 				case _f0.IP < 4:
+//line coroutine.go:554
 					_f0.X3 = _f0.X1[_f0.X2]
+//line coroutine_durable.go:3833
 					_f0.IP = 4
 					fallthrough
 				case _f0.IP < 5:
+//line coroutine.go:555
 
 					coroutine.Yield[int, any](_f0.X3)
 				}
 			}
 		}
 	}
+//line coroutine_durable.go:3844
 }
 
 //go:noinline
 func ReturnNamedValue() (_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:559
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ReturnNamedValue@01f171c2439ae3fc"`
 		X0 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ReturnNamedValue@01f171c2439ae3fc"`
 		X0 int
 	}](&_c.Stack)
+//line coroutine_durable.go:3858
 	if _f0.IP == 0 {
+//line coroutine.go:559
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ReturnNamedValue@01f171c2439ae3fc"`
 			X0 int
 		}{}
 	}
+//line coroutine_durable.go:3866
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -3225,20 +3870,28 @@ func ReturnNamedValue() (_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:560
 		_f0.X0 = 5
+//line coroutine_durable.go:3876
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:561
 		coroutine.Yield[int, any](11)
+//line coroutine_durable.go:3882
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 4:
+//line coroutine.go:562
 		_f0.X0 = 42
+//line coroutine_durable.go:3888
 		_f0.IP = 4
 		fallthrough
 	case _f0.IP < 5:
+//line coroutine.go:563
 		return _f0.X0
 	}
+//line coroutine_durable.go:3895
 	panic("unreachable")
 }
 
@@ -3249,19 +3902,23 @@ type Box struct {
 //go:noinline
 func (_fn0 *Box) YieldAndInc() {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:570
 	var _f0 *struct {
-		IP int
+		IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.Box).YieldAndInc@bcf6e5dfbabedd75"`
 		X0 *Box
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.Box).YieldAndInc@bcf6e5dfbabedd75"`
 		X0 *Box
 	}](&_c.Stack)
+//line coroutine_durable.go:3914
 	if _f0.IP == 0 {
+//line coroutine.go:570
 		*_f0 = struct {
-			IP int
+			IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.Box).YieldAndInc@bcf6e5dfbabedd75"`
 			X0 *Box
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:3922
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -3269,17 +3926,22 @@ func (_fn0 *Box) YieldAndInc() {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:571
 		coroutine.Yield[int, any](_f0.X0.x)
+//line coroutine_durable.go:3932
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:572
 		_f0.X0.
 			x++
 	}
+//line coroutine_durable.go:3940
 }
 
 //go:noinline
 func (_fn0 *Box) Closure(_fn1 int) (_ func(int)) {
+//line coroutine.go:575
 	var _f0 *struct {
 		IP int
 		X0 *Box
@@ -3289,21 +3951,27 @@ func (_fn0 *Box) Closure(_fn1 int) (_ func(int)) {
 		X0 *Box
 		X1 int
 	}{X0: _fn0, X1: _fn1}
+//line coroutine.go:576
 	return func(_fn0 int) {
+//line coroutine_durable.go:3957
 		_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:576
 		var _f1 *struct {
-			IP int
+			IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.Box).Closure@167793d5b527ddc4"`
 			X0 int
 		} = coroutine.Push[struct {
-			IP int
+			IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.Box).Closure@167793d5b527ddc4"`
 			X0 int
 		}](&_c.Stack)
+//line coroutine_durable.go:3967
 		if _f1.IP == 0 {
+//line coroutine.go:576
 			*_f1 = struct {
-				IP int
+				IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.Box).Closure@167793d5b527ddc4"`
 				X0 int
 			}{X0: _fn0}
 		}
+//line coroutine_durable.go:3975
 		defer func() {
 			if !_c.Unwinding() {
 				coroutine.Pop(&_c.Stack)
@@ -3311,20 +3979,28 @@ func (_fn0 *Box) Closure(_fn1 int) (_ func(int)) {
 		}()
 		switch {
 		case _f1.IP < 2:
+//line coroutine.go:577
 			coroutine.Yield[int, any](_f0.X0.x)
+//line coroutine_durable.go:3985
 			_f1.IP = 2
 			fallthrough
 		case _f1.IP < 3:
+//line coroutine.go:578
 			coroutine.Yield[int, any](_f0.X1)
+//line coroutine_durable.go:3991
 			_f1.IP = 3
 			fallthrough
 		case _f1.IP < 4:
+//line coroutine.go:579
 			coroutine.Yield[int, any](_f1.X0)
+//line coroutine_durable.go:3997
 			_f1.IP = 4
 			fallthrough
 		case _f1.IP < 5:
+//line coroutine.go:580
 			_f0.X0.
 				x++
+//line coroutine_durable.go:4004
 			_f1.IP = 5
 			fallthrough
 		case _f1.IP < 6:
@@ -3332,36 +4008,42 @@ func (_fn0 *Box) Closure(_fn1 int) (_ func(int)) {
 			_f1.IP = 6
 			fallthrough
 		case _f1.IP < 7:
+//line coroutine.go:582
 			_f1.X0++
 		}
 	}
+//line coroutine_durable.go:4016
 }
 
 //go:noinline
 func StructClosure(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:586
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.StructClosure@74f284527bcdfdd9"`
 		X0 int
 		X1 Box
 		X2 func(int)
 		X3 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.StructClosure@74f284527bcdfdd9"`
 		X0 int
 		X1 Box
 		X2 func(int)
 		X3 int
 	}](&_c.Stack)
+//line coroutine_durable.go:4036
 	if _f0.IP == 0 {
+//line coroutine.go:586
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.StructClosure@74f284527bcdfdd9"`
 			X0 int
 			X1 Box
 			X2 func(int)
 			X3 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:4047
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -3369,25 +4051,33 @@ func StructClosure(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:587
 		_f0.X1 = Box{10}
+//line coroutine_durable.go:4057
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:588
 		_f0.X2 = _f0.X1.Closure(100)
+//line coroutine_durable.go:4063
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 5:
 		switch {
 		case _f0.IP < 4:
+//line coroutine.go:589
 			_f0.X3 = 0
+//line coroutine_durable.go:4071
 			_f0.IP = 4
 			fallthrough
 		case _f0.IP < 5:
 			for ; _f0.X3 < _f0.X0; _f0.X3, _f0.IP = _f0.X3+1, 4 {
+//line coroutine.go:590
 				_f0.X2(1000)
 			}
 		}
 	}
+//line coroutine_durable.go:4081
 }
 
 type GenericBox[T integer] struct {
@@ -3401,6 +4091,7 @@ func (b *GenericBox[T]) YieldAndInc() {
 
 //go:noinline
 func (_fn0 *GenericBox[T]) Closure(_fn1 T) (_ func(T)) {
+//line coroutine.go:603
 	var _f0 *struct {
 		IP int
 		X0 *GenericBox[T]
@@ -3410,21 +4101,27 @@ func (_fn0 *GenericBox[T]) Closure(_fn1 T) (_ func(T)) {
 		X0 *GenericBox[T]
 		X1 T
 	}{X0: _fn0, X1: _fn1}
+//line coroutine.go:604
 	return func(_fn0 T) {
+//line coroutine_durable.go:4107
 		_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:604
 		var _f1 *struct {
-			IP int
+			IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.GenericBox[T]).Closure@ee338ddca2e7b38c"`
 			X0 T
 		} = coroutine.Push[struct {
-			IP int
+			IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.GenericBox[T]).Closure@ee338ddca2e7b38c"`
 			X0 T
 		}](&_c.Stack)
+//line coroutine_durable.go:4117
 		if _f1.IP == 0 {
+//line coroutine.go:604
 			*_f1 = struct {
-				IP int
+				IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.GenericBox[T]).Closure@ee338ddca2e7b38c"`
 				X0 T
 			}{X0: _fn0}
 		}
+//line coroutine_durable.go:4125
 		defer func() {
 			if !_c.Unwinding() {
 				coroutine.Pop(&_c.Stack)
@@ -3432,20 +4129,28 @@ func (_fn0 *GenericBox[T]) Closure(_fn1 T) (_ func(T)) {
 		}()
 		switch {
 		case _f1.IP < 2:
+//line coroutine.go:605
 			coroutine.Yield[T, any](_f0.X0.x)
+//line coroutine_durable.go:4135
 			_f1.IP = 2
 			fallthrough
 		case _f1.IP < 3:
+//line coroutine.go:606
 			coroutine.Yield[T, any](_f0.X1)
+//line coroutine_durable.go:4141
 			_f1.IP = 3
 			fallthrough
 		case _f1.IP < 4:
+//line coroutine.go:607
 			coroutine.Yield[T, any](_f1.X0)
+//line coroutine_durable.go:4147
 			_f1.IP = 4
 			fallthrough
 		case _f1.IP < 5:
+//line coroutine.go:608
 			_f0.X0.
 				x++
+//line coroutine_durable.go:4154
 			_f1.IP = 5
 			fallthrough
 		case _f1.IP < 6:
@@ -3453,36 +4158,42 @@ func (_fn0 *GenericBox[T]) Closure(_fn1 T) (_ func(T)) {
 			_f1.IP = 6
 			fallthrough
 		case _f1.IP < 7:
+//line coroutine.go:610
 			_f1.X0++
 		}
 	}
+//line coroutine_durable.go:4166
 }
 
 //go:noinline
 func StructGenericClosure(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:614
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.StructGenericClosure@c222701158173da6"`
 		X0 int
 		X1 GenericBox[int]
 		X2 func(int)
 		X3 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.StructGenericClosure@c222701158173da6"`
 		X0 int
 		X1 GenericBox[int]
 		X2 func(int)
 		X3 int
 	}](&_c.Stack)
+//line coroutine_durable.go:4186
 	if _f0.IP == 0 {
+//line coroutine.go:614
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.StructGenericClosure@c222701158173da6"`
 			X0 int
 			X1 GenericBox[int]
 			X2 func(int)
 			X3 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:4197
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -3490,52 +4201,68 @@ func StructGenericClosure(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:615
 		_f0.X1 = GenericBox[int]{10}
+//line coroutine_durable.go:4207
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:616
 		_f0.X2 = _f0.X1.Closure(100)
+//line coroutine_durable.go:4213
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 5:
 		switch {
 		case _f0.IP < 4:
+//line coroutine.go:617
 			_f0.X3 = 0
+//line coroutine_durable.go:4221
 			_f0.IP = 4
 			fallthrough
 		case _f0.IP < 5:
 			for ; _f0.X3 < _f0.X0; _f0.X3, _f0.IP = _f0.X3+1, 4 {
+//line coroutine.go:618
 				_f0.X2(1000)
 			}
 		}
 	}
+//line coroutine_durable.go:4231
 }
 
 //go:noinline
-func IdentityGeneric[T any](n T) { coroutine.Yield[T, any](n) }
+func IdentityGeneric[T any](n T) {
+//line coroutine.go:623
+	coroutine.Yield[T, any](n)
+//line coroutine_durable.go:4238
+}
 
 //go:noinline
 func IdentityGenericInt(n int) { IdentityGeneric[int](n) }
 
 //go:noinline
 func IdentityGenericClosure[T any](_fn0 T) {
-	_c := coroutine.LoadContext[int, any]()
+	_c := coroutine.LoadContext[T, any]()
+//line coroutine.go:630
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericClosure@732a63fa0e983c46"`
 		X0 T
 		X1 func()
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericClosure@732a63fa0e983c46"`
 		X0 T
 		X1 func()
 	}](&_c.Stack)
+//line coroutine_durable.go:4257
 	if _f0.IP == 0 {
+//line coroutine.go:630
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericClosure@732a63fa0e983c46"`
 			X0 T
 			X1 func()
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:4266
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -3543,20 +4270,27 @@ func IdentityGenericClosure[T any](_fn0 T) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:631
 		_f0.X1 = buildClosure(_f0.X0)
+//line coroutine_durable.go:4276
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:632
 		_f0.X1()
+//line coroutine_durable.go:4282
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 4:
+//line coroutine.go:633
 		_f0.X1()
 	}
+//line coroutine_durable.go:4289
 }
 
 //go:noinline
 func buildClosure[T any](_fn0 T) (_ func()) {
+//line coroutine.go:636
 	var _f0 *struct {
 		IP int
 		X0 T
@@ -3564,11 +4298,17 @@ func buildClosure[T any](_fn0 T) (_ func()) {
 		IP int
 		X0 T
 	}{X0: _fn0}
+//line coroutine.go:637
 	return func() { coroutine.Yield[T, any](_f0.X0) }
+//line coroutine_durable.go:4304
 }
 
 //go:noinline
-func IdentityGenericClosureInt(n int) { IdentityGenericClosure[int](n) }
+func IdentityGenericClosureInt(n int) {
+//line coroutine.go:643
+	IdentityGenericClosure[int](n)
+//line coroutine_durable.go:4311
+}
 
 type integer interface {
 	~int | ~int8 | ~int16 | ~int32 | ~int64
@@ -3579,10 +4319,15 @@ type IdentityGenericStruct[T integer] struct {
 }
 
 //go:noinline
-func (i *IdentityGenericStruct[T]) Run() { coroutine.Yield[T, any](i.n) }
+func (i *IdentityGenericStruct[T]) Run() {
+//line coroutine.go:655
+	coroutine.Yield[T, any](i.n)
+//line coroutine_durable.go:4326
+}
 
 //go:noinline
 func (_fn0 *IdentityGenericStruct[T]) Closure(_fn1 T) (_ func(T)) {
+//line coroutine.go:658
 	var _f0 *struct {
 		IP int
 		X0 *IdentityGenericStruct[T]
@@ -3592,21 +4337,27 @@ func (_fn0 *IdentityGenericStruct[T]) Closure(_fn1 T) (_ func(T)) {
 		X0 *IdentityGenericStruct[T]
 		X1 T
 	}{X0: _fn0, X1: _fn1}
+//line coroutine.go:659
 	return func(_fn0 T) {
+//line coroutine_durable.go:4343
 		_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:659
 		var _f1 *struct {
-			IP int
+			IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericStruct[T]).Closure@9abb1b77509c5fd6"`
 			X0 T
 		} = coroutine.Push[struct {
-			IP int
+			IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericStruct[T]).Closure@9abb1b77509c5fd6"`
 			X0 T
 		}](&_c.Stack)
+//line coroutine_durable.go:4353
 		if _f1.IP == 0 {
+//line coroutine.go:659
 			*_f1 = struct {
-				IP int
+				IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericStruct[T]).Closure@9abb1b77509c5fd6"`
 				X0 T
 			}{X0: _fn0}
 		}
+//line coroutine_durable.go:4361
 		defer func() {
 			if !_c.Unwinding() {
 				coroutine.Pop(&_c.Stack)
@@ -3614,16 +4365,22 @@ func (_fn0 *IdentityGenericStruct[T]) Closure(_fn1 T) (_ func(T)) {
 		}()
 		switch {
 		case _f1.IP < 2:
+//line coroutine.go:660
 			coroutine.Yield[T, any](_f0.X0.n)
+//line coroutine_durable.go:4371
 			_f1.IP = 2
 			fallthrough
 		case _f1.IP < 3:
+//line coroutine.go:661
 			_f0.X0.
 				n++
+//line coroutine_durable.go:4378
 			_f1.IP = 3
 			fallthrough
 		case _f1.IP < 4:
+//line coroutine.go:662
 			coroutine.Yield[T, any](_f0.X1)
+//line coroutine_durable.go:4384
 			_f1.IP = 4
 			fallthrough
 		case _f1.IP < 5:
@@ -3631,33 +4388,43 @@ func (_fn0 *IdentityGenericStruct[T]) Closure(_fn1 T) (_ func(T)) {
 			_f1.IP = 5
 			fallthrough
 		case _f1.IP < 6:
+//line coroutine.go:664
 			coroutine.Yield[T, any](_f1.X0)
 		}
 	}
+//line coroutine_durable.go:4396
 }
 
 //go:noinline
-func IdentityGenericStructInt(n int) { (&IdentityGenericStruct[int]{n: n}).Run() }
+func IdentityGenericStructInt(n int) {
+//line coroutine.go:669
+	(&IdentityGenericStruct[int]{n: n}).Run()
+//line coroutine_durable.go:4403
+}
 
 //go:noinline
 func IdentityGenericStructClosureInt(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:672
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericStructClosureInt@b36c92788dd15dea"`
 		X0 int
 		X1 func(int)
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericStructClosureInt@b36c92788dd15dea"`
 		X0 int
 		X1 func(int)
 	}](&_c.Stack)
+//line coroutine_durable.go:4419
 	if _f0.IP == 0 {
+//line coroutine.go:672
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericStructClosureInt@b36c92788dd15dea"`
 			X0 int
 			X1 func(int)
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:4428
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -3665,40 +4432,50 @@ func IdentityGenericStructClosureInt(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:673
 		_f0.X1 = (&IdentityGenericStruct[int]{n: _f0.X0}).Closure(100)
+//line coroutine_durable.go:4438
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:674
 		_f0.X1(23)
+//line coroutine_durable.go:4444
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 4:
+//line coroutine.go:675
 		_f0.X1(45)
 	}
+//line coroutine_durable.go:4451
 }
 
 //go:noinline
 func IndirectClosure(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:678
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.IndirectClosure@5aaff2d6b996df9e"`
 		X0 int
 		X1 *Box
 		X2 func()
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.IndirectClosure@5aaff2d6b996df9e"`
 		X0 int
 		X1 *Box
 		X2 func()
 	}](&_c.Stack)
+//line coroutine_durable.go:4469
 	if _f0.IP == 0 {
+//line coroutine.go:678
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.IndirectClosure@5aaff2d6b996df9e"`
 			X0 int
 			X1 *Box
 			X2 func()
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:4479
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -3706,42 +4483,56 @@ func IndirectClosure(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:679
 		_f0.X1 = &Box{_f0.X0}
+//line coroutine_durable.go:4489
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:680
 		_f0.X2 = indirectClosure(_f0.X1)
+//line coroutine_durable.go:4495
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 4:
+//line coroutine.go:681
 		_f0.X2()
+//line coroutine_durable.go:4501
 		_f0.IP = 4
 		fallthrough
 	case _f0.IP < 5:
+//line coroutine.go:682
 		_f0.X2()
+//line coroutine_durable.go:4507
 		_f0.IP = 5
 		fallthrough
 	case _f0.IP < 6:
+//line coroutine.go:683
 		_f0.X2()
 	}
+//line coroutine_durable.go:4514
 }
 
 //go:noinline
 func indirectClosure(_fn0 interface{ YieldAndInc() }) (_ func()) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:686
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.indirectClosure@2af7e513f568f296"`
 		X0 interface{ YieldAndInc() }
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.indirectClosure@2af7e513f568f296"`
 		X0 interface{ YieldAndInc() }
 	}](&_c.Stack)
+//line coroutine_durable.go:4528
 	if _f0.IP == 0 {
+//line coroutine.go:686
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.indirectClosure@2af7e513f568f296"`
 			X0 interface{ YieldAndInc() }
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:4536
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -3749,40 +4540,48 @@ func indirectClosure(_fn0 interface{ YieldAndInc() }) (_ func()) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:687
 		coroutine.Yield[int, any](-1)
+//line coroutine_durable.go:4546
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:688
 		return func() {
 			_f0.X0.
 				YieldAndInc()
 		}
 	}
+//line coroutine_durable.go:4556
 	panic("unreachable")
 }
 
 //go:noinline
 func RangeOverInt(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:693
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeOverInt@adf4d48d38f24b72"`
 		X0 int
 		X1 int
 		X2 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeOverInt@adf4d48d38f24b72"`
 		X0 int
 		X1 int
 		X2 int
 	}](&_c.Stack)
+//line coroutine_durable.go:4575
 	if _f0.IP == 0 {
+//line coroutine.go:693
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeOverInt@adf4d48d38f24b72"`
 			X0 int
 			X1 int
 			X2 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:4585
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -3790,67 +4589,104 @@ func RangeOverInt(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:694
 		_f0.X1 = _f0.X0
+//line coroutine_durable.go:4595
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 4:
 		switch {
 		case _f0.IP < 3:
+//line coroutine.go:694
 			_f0.X2 = 0
+//line coroutine_durable.go:4603
 			_f0.IP = 3
 			fallthrough
 		case _f0.IP < 4:
 			for ; _f0.X2 < _f0.X1; _f0.X2, _f0.IP = _f0.X2+1, 3 {
+//line coroutine.go:695
 
 				coroutine.Yield[int, any](_f0.X2)
 			}
 		}
 	}
+//line coroutine_durable.go:4614
 }
 
 //go:noinline
-func ReflectType(_fn0 ...reflect.Type) {
+func LoopVarClosures(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:699
 	var _f0 *struct {
-		IP int
-		X0 []reflect.Type
-		X1 []reflect.Type
-		X2 int
-		X3 reflect.Type
-		X4 reflect.Value
-		X5 reflect.Value
-		X6 bool
-		X7 bool
-		X8 uint64
-		X9 int
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures@106e0b93f4be2405"`
+		X0  int
+		X1  []func() int
+		X2  int
+		X3  *int
+		X4  []*int
+		X5  int
+		X6  int
+		X7  *int
+		X8  *int
+		X9  int
+		X10 *int
+		X11 func()
+		X12 []func() int
+		X13 int
+		X14 func() int
+		X15 int
+		X16 []*int
+		X17 int
+		X18 *int
 	} = coroutine.Push[struct {
-		IP int
-		X0 []reflect.Type
-		X1 []reflect.Type
-		X2 int
-		X3 reflect.Type
-		X4 reflect.Value
-		X5 reflect.Value
-		X6 bool
-		X7 bool
-		X8 uint64
-		X9 int
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures@106e0b93f4be2405"`
+		X0  int
+		X1  []func() int
+		X2  int
+		X3  *int
+		X4  []*int
+		X5  int
+		X6  int
+		X7  *int
+		X8  *int
+		X9  int
+		X10 *int
+		X11 func()
+		X12 []func() int
+		X13 int
+		X14 func() int
+		X15 int
+		X16 []*int
+		X17 int
+		X18 *int
 	}](&_c.Stack)
+//line coroutine_durable.go:4664
 	if _f0.IP == 0 {
+//line coroutine.go:699
 		*_f0 = struct {
-			IP int
-			X0 []reflect.Type
-			X1 []reflect.Type
-			X2 int
-			X3 reflect.Type
-			X4 reflect.Value
-			X5 reflect.Value
-			X6 bool
-			X7 bool
-			X8 uint64
-			X9 int
+			IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures@106e0b93f4be2405"`
+			X0  int
+			X1  []func() int
+			X2  int
+			X3  *int
+			X4  []*int
+			X5  int
+			X6  int
+			X7  *int
+			X8  *int
+			X9  int
+			X10 *int
+			X11 func()
+			X12 []func() int
+			X13 int
+			X14 func() int
+			X15 int
+			X16 []*int
+			X17 int
+			X18 *int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:4690
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -3858,72 +4694,406 @@ func ReflectType(_fn0 ...reflect.Type) {
 	}()
 	switch {
 	case _f0.IP < 2:
-		_f0.X1 = _f0.X0
 		_f0.IP = 2
 		fallthrough
-	case _f0.IP < 13:
+	case _f0.IP < 8:
 		switch {
 		case _f0.IP < 3:
+//line coroutine.go:701
 			_f0.X2 = 0
+//line coroutine_durable.go:4705
 			_f0.IP = 3
 			fallthrough
-		case _f0.IP < 13:
-			for ; _f0.X2 < len(_f0.X1); _f0.X2, _f0.IP = _f0.X2+1, 3 {
+		case _f0.IP < 8:
+			for ; _f0.X2 < _f0.X0; _f0.X2, _f0.IP = _f0.X2+1, 3 {
 				switch {
 				case _f0.IP < 4:
-					_f0.X3 = _f0.X1[_f0.X2]
+					_f0.X3 = new(int)
 					_f0.IP = 4
 					fallthrough
 				case _f0.IP < 5:
-					_f0.X4 = reflect.New(_f0.X3)
+//line coroutine.go:701
+					*_f0.X3 = _f0.X2
+//line coroutine_durable.go:4718
 					_f0.IP = 5
 					fallthrough
 				case _f0.IP < 6:
-					_f0.X5 = _f0.X4.Elem()
+//line coroutine.go:702
+					coroutine.Yield[int, any](*_f0.X3)
+//line coroutine_durable.go:4724
 					_f0.IP = 6
 					fallthrough
-				case _f0.IP < 9:
-					switch {
-					case _f0.IP < 7:
-						_f0.X6 = _f0.X5.
-							CanUint()
-						_f0.IP = 7
-						fallthrough
-					case _f0.IP < 8:
-						_f0.X7 = !_f0.X6
-						_f0.IP = 8
-						fallthrough
-					case _f0.IP < 9:
-						if _f0.X7 {
-							panic("expected uint type")
-						}
+				case _f0.IP < 7:
+//line coroutine.go:703
+					{
+//line coroutine_durable.go:4730
+						var _o0 *int = _f0.X3
+//line coroutine.go:703
+						_f0.X1 = append(_f0.X1, func() int { return *_o0 })
+					}
+//line coroutine_durable.go:4735
+					_f0.IP = 7
+					fallthrough
+				case _f0.IP < 8:
+					_f0.X2 = *_f0.X3
+				}
+			}
+		}
+		_f0.IP = 8
+		fallthrough
+	case _f0.IP < 9:
+		_f0.IP = 9
+		fallthrough
+	case _f0.IP < 18:
+		switch {
+		case _f0.IP < 10:
+//line coroutine.go:707
+			_f0.X5 = _f0.X0
+//line coroutine_durable.go:4753
+			_f0.IP = 10
+			fallthrough
+		case _f0.IP < 18:
+			switch {
+			case _f0.IP < 11:
+				_f0.X6 = 0
+				_f0.IP = 11
+				fallthrough
+			case _f0.IP < 18:
+				for ; _f0.X6 < _f0.X5; _f0.X6, _f0.IP = _f0.X6+1, 11 {
+					switch {
+					case _f0.IP < 12:
+						_f0.X7 = new(int)
+						_f0.IP = 12
+						fallthrough
+					case _f0.IP < 13:
+//line coroutine.go:707
+						*_f0.X7 = _f0.X6
+//line coroutine_durable.go:4772
+						_f0.IP = 13
+						fallthrough
+					case _f0.IP < 14:
+						_f0.X8 = new(int)
+						_f0.IP = 14
+						fallthrough
+					case _f0.IP < 15:
+//line coroutine.go:708
+						*_f0.X8 = *_f0.X7 * 10
+//line coroutine_durable.go:4782
+						_f0.IP = 15
+						fallthrough
+					case _f0.IP < 16:
+//line coroutine.go:709
+						_f0.X4 = append(_f0.X4, &*_f0.X8)
+//line coroutine_durable.go:4788
+						_f0.IP = 16
+						fallthrough
+					case _f0.IP < 17:
+//line coroutine.go:710
+						{
+//line coroutine_durable.go:4794
+							var _o1 *int = _f0.X7
+//line coroutine.go:710
+							_f0.X1 = append(_f0.X1, func() int { return *_o1 })
+						}
+//line coroutine_durable.go:4799
+						_f0.IP = 17
+						fallthrough
+					case _f0.IP < 18:
+//line coroutine.go:711
+						coroutine.Yield[int, any](*_f0.X8)
+					}
+				}
+			}
+		}
+//line coroutine_durable.go:4809
+		_f0.IP = 18
+		fallthrough
+	case _f0.IP < 27:
+		switch {
+		case _f0.IP < 19:
+//line coroutine.go:714
+			_f0.X9 = 0
+//line coroutine_durable.go:4817
+			_f0.IP = 19
+			fallthrough
+		case _f0.IP < 27:
+		_l2:
+			for ; _f0.X9 < _f0.X0; _f0.X9, _f0.IP = _f0.X9+1, 19 {
+				switch {
+				case _f0.IP < 20:
+					_f0.X10 = new(int)
+					_f0.IP = 20
+					fallthrough
+				case _f0.IP < 21:
+//line coroutine.go:714
+					*_f0.X10 = _f0.X9
+//line coroutine_durable.go:4831
+					_f0.IP = 21
+					fallthrough
+				case _f0.IP < 22:
+					{
+						var _o2 *int = _f0.X10
+//line coroutine.go:715
+						_f0.X11 = func() { *_o2++ }
+					}
+//line coroutine_durable.go:4840
+					_f0.IP = 22
+					fallthrough
+				case _f0.IP < 23:
+//line coroutine.go:716
+					coroutine.Yield[int, any](*_f0.X10)
+//line coroutine_durable.go:4846
+					_f0.IP = 23
+					fallthrough
+				case _f0.IP < 26:
+//line coroutine.go:717
+					if *_f0.X10%
+						2 == 0 {
+//line coroutine_durable.go:4853
+						switch {
+						case _f0.IP < 24:
+//line coroutine.go:718
+							_f0.X11()
+//line coroutine_durable.go:4858
+							_f0.IP = 24
+							fallthrough
+						case _f0.IP < 26:
+//line coroutine.go:719
+							{
+//line coroutine_durable.go:4864
+								_f0.X9 = *_f0.X10
+//line coroutine.go:719
+								continue _l2
+							}
+						}
+					}
+//line coroutine_durable.go:4871
+					_f0.IP = 26
+					fallthrough
+				case _f0.IP < 27:
+					_f0.X9 = *_f0.X10
+				}
+			}
+		}
+		_f0.IP = 27
+		fallthrough
+	case _f0.IP < 32:
+		switch {
+		case _f0.IP < 28:
+//line coroutine.go:723
+			_f0.X12 = _f0.X1
+//line coroutine_durable.go:4886
+			_f0.IP = 28
+			fallthrough
+		case _f0.IP < 32:
+			switch {
+			case _f0.IP < 29:
+				_f0.X13 = 0
+				_f0.IP = 29
+				fallthrough
+			case _f0.IP < 32:
+				for ; _f0.X13 < len(_f0.X12); _f0.X13, _f0.IP = _f0.X13+1, 29 {
+					switch {
+					case _f0.IP < 30:
+//line coroutine.go:723
+						_f0.X14 = _f0.X12[_f0.X13]
+//line coroutine_durable.go:4901
+						_f0.IP = 30
+						fallthrough
+					case _f0.IP < 31:
+//line coroutine.go:724
+						_f0.X15 = _f0.X14()
+//line coroutine_durable.go:4907
+						_f0.IP = 31
+						fallthrough
+					case _f0.IP < 32:
+//line coroutine.go:724
+						coroutine.Yield[int, any](_f0.X15)
+					}
+				}
+			}
+		}
+//line coroutine_durable.go:4917
+		_f0.IP = 32
+		fallthrough
+	case _f0.IP < 36:
+		switch {
+		case _f0.IP < 33:
+//line coroutine.go:726
+			_f0.X16 = _f0.X4
+//line coroutine_durable.go:4925
+			_f0.IP = 33
+			fallthrough
+		case _f0.IP < 36:
+			switch {
+			case _f0.IP < 34:
+				_f0.X17 = 0
+				_f0.IP = 34
+				fallthrough
+			case _f0.IP < 36:
+				for ; _f0.X17 < len(_f0.X16); _f0.X17, _f0.IP = _f0.X17+1, 34 {
+					switch {
+					case _f0.IP < 35:
+//line coroutine.go:726
+						_f0.X18 = _f0.X16[_f0.X17]
+//line coroutine_durable.go:4940
+						_f0.IP = 35
+						fallthrough
+					case _f0.IP < 36:
+//line coroutine.go:727
+
+						coroutine.Yield[int, any](*_f0.X18)
+					}
+				}
+			}
+		}
+	}
+//line coroutine_durable.go:4952
+}
+
+//go:noinline
+func ReflectType(_fn0 ...reflect.Type) {
+	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:731
+	var _f0 *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ReflectType@18570e44eb10a7fb"`
+		X0 []reflect.Type
+		X1 []reflect.Type
+		X2 int
+		X3 reflect.Type
+		X4 reflect.Value
+		X5 reflect.Value
+		X6 bool
+		X7 bool
+		X8 uint64
+		X9 int
+	} = coroutine.Push[struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ReflectType@18570e44eb10a7fb"`
+		X0 []reflect.Type
+		X1 []reflect.Type
+		X2 int
+		X3 reflect.Type
+		X4 reflect.Value
+		X5 reflect.Value
+		X6 bool
+		X7 bool
+		X8 uint64
+		X9 int
+	}](&_c.Stack)
+//line coroutine_durable.go:4984
+	if _f0.IP == 0 {
+//line coroutine.go:731
+		*_f0 = struct {
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ReflectType@18570e44eb10a7fb"`
+			X0 []reflect.Type
+			X1 []reflect.Type
+			X2 int
+			X3 reflect.Type
+			X4 reflect.Value
+			X5 reflect.Value
+			X6 bool
+			X7 bool
+			X8 uint64
+			X9 int
+		}{X0: _fn0}
+	}
+//line coroutine_durable.go:5001
+	defer func() {
+		if !_c.Unwinding() {
+			coroutine.Pop(&_c.Stack)
+		}
+	}()
+	switch {
+	case _f0.IP < 2:
+//line coroutine.go:732
+		_f0.X1 = _f0.X0
+//line coroutine_durable.go:5011
+		_f0.IP = 2
+		fallthrough
+	case _f0.IP < 13:
+		switch {
+		case _f0.IP < 3:
+			_f0.X2 = 0
+			_f0.IP = 3
+			fallthrough
+		case _f0.IP < 13:
+			for ; _f0.X2 < len(_f0.X1); _f0.X2, _f0.IP = _f0.X2+1, 3 {
+				switch {
+				case _f0.IP < 4:
+//line coroutine.go:732
+					_f0.X3 = _f0.X1[_f0.X2]
+//line coroutine_durable.go:5026
+					_f0.IP = 4
+					fallthrough
+				case _f0.IP < 5:
+//line coroutine.go:733
+					_f0.X4 = reflect.New(_f0.X3)
+//line coroutine_durable.go:5032
+					_f0.IP = 5
+					fallthrough
+				case _f0.IP < 6:
+//line coroutine.go:733
+					_f0.X5 = _f0.X4.Elem()
+//line coroutine_durable.go:5038
+					_f0.IP = 6
+					fallthrough
+				case _f0.IP < 9:
+					switch {
+					case _f0.IP < 7:
+//line coroutine.go:734
+						_f0.X6 = _f0.X5.
+							CanUint()
+//line coroutine_durable.go:5047
+						_f0.IP = 7
+						fallthrough
+					case _f0.IP < 8:
+//line coroutine.go:734
+						_f0.X7 = !_f0.X6
+//line coroutine_durable.go:5053
+						_f0.IP = 8
+						fallthrough
+					case _f0.IP < 9:
+						if _f0.X7 {
+//line coroutine.go:735
+							panic("expected uint type")
+						}
 					}
+//line coroutine_durable.go:5062
 					_f0.IP = 9
 					fallthrough
 				case _f0.IP < 10:
+//line coroutine.go:737
 					_f0.X5.
 						SetUint(math.MaxUint64)
+//line coroutine_durable.go:5069
 					_f0.IP = 10
 					fallthrough
 				case _f0.IP < 11:
+//line coroutine.go:738
 					_f0.X8 = _f0.X5.
 						Uint()
+//line coroutine_durable.go:5076
 					_f0.IP = 11
 					fallthrough
 				case _f0.IP < 12:
+//line coroutine.go:738
 					_f0.X9 = int(_f0.X8)
+//line coroutine_durable.go:5082
 					_f0.IP = 12
 					fallthrough
 				case _f0.IP < 13:
+//line coroutine.go:738
 					coroutine.Yield[int, any](_f0.X9)
 				}
 			}
 		}
 	}
+//line coroutine_durable.go:5092
 }
 
 //go:noinline
 func MakeEllipsisClosure(_fn0 ...int) (_ func()) {
+//line coroutine.go:742
 	var _f0 *struct {
 		IP int
 		X0 []int
@@ -3931,16 +5101,18 @@ func MakeEllipsisClosure(_fn0 ...int) (_ func()) {
 		IP int
 		X0 []int
 	}{X0: _fn0}
+//line coroutine.go:743
 	return func() {
+//line coroutine_durable.go:5107
 		_c := coroutine.LoadContext[int, any]()
 		var _f1 *struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.MakeEllipsisClosure@a159dc53a4948501"`
 			X0 []int
 			X1 []int
 			X2 int
 			X3 int
 		} = coroutine.Push[struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.MakeEllipsisClosure@a159dc53a4948501"`
 			X0 []int
 			X1 []int
 			X2 int
@@ -3948,7 +5120,7 @@ func MakeEllipsisClosure(_fn0 ...int) (_ func()) {
 		}](&_c.Stack)
 		if _f1.IP == 0 {
 			*_f1 = struct {
-				IP int
+				IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.MakeEllipsisClosure@a159dc53a4948501"`
 				X0 []int
 				X1 []int
 				X2 int
@@ -3962,13 +5134,17 @@ func MakeEllipsisClosure(_fn0 ...int) (_ func()) {
 		}()
 		switch {
 		case _f1.IP < 2:
+//line coroutine.go:744
 			_f1.X0 = _f0.X0
+//line coroutine_durable.go:5140
 			_f1.IP = 2
 			fallthrough
 		case _f1.IP < 6:
 			switch {
 			case _f1.IP < 3:
+//line coroutine.go:745
 				_f1.X1 = _f1.X0
+//line coroutine_durable.go:5148
 				_f1.IP = 3
 				fallthrough
 			case _f1.IP < 6:
@@ -3981,10 +5157,13 @@ func MakeEllipsisClosure(_fn0 ...int) (_ func()) {
 					for ; _f1.X2 < len(_f1.X1); _f1.X2, _f1.IP = _f1.X2+1, 4 {
 						switch {
 						case _f1.IP < 5:
+//line coroutine.go:745
 							_f1.X3 = _f1.X1[_f1.X2]
+//line coroutine_durable.go:5163
 							_f1.IP = 5
 							fallthrough
 						case _f1.IP < 6:
+//line coroutine.go:746
 
 							coroutine.Yield[int, any](_f1.X3)
 						}
@@ -3993,30 +5172,35 @@ func MakeEllipsisClosure(_fn0 ...int) (_ func()) {
 			}
 		}
 	}
+//line coroutine_durable.go:5176
 }
 
 //go:noinline
 func EllipsisClosure(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:751
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.EllipsisClosure@8846f20c06b67cb0"`
 		X0 int
 		X1 []int
 		X2 func()
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.EllipsisClosure@8846f20c06b67cb0"`
 		X0 int
 		X1 []int
 		X2 func()
 	}](&_c.Stack)
+//line coroutine_durable.go:5194
 	if _f0.IP == 0 {
+//line coroutine.go:751
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.EllipsisClosure@8846f20c06b67cb0"`
 			X0 int
 			X1 []int
 			X2 func()
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:5204
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -4024,26 +5208,36 @@ func EllipsisClosure(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:752
 		_f0.X1 = make([]int, _f0.X0)
+//line coroutine_durable.go:5214
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:753
 		for i := range _f0.X1 {
 			_f0.X1[i] = i
 		}
+//line coroutine_durable.go:5222
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 4:
+//line coroutine.go:756
 		_f0.X2 = MakeEllipsisClosure(_f0.X1...)
+//line coroutine_durable.go:5228
 		_f0.IP = 4
 		fallthrough
 	case _f0.IP < 5:
+//line coroutine.go:757
 		coroutine.Yield[int, any](-1)
+//line coroutine_durable.go:5234
 		_f0.IP = 5
 		fallthrough
 	case _f0.IP < 6:
+//line coroutine.go:758
 		_f0.X2()
 	}
+//line coroutine_durable.go:5241
 }
 
 type innerInterface interface {
@@ -4062,7 +5256,7 @@ type outerInterface interface {
 func InterfaceEmbedded() {
 	_c := coroutine.LoadContext[int, any]()
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.InterfaceEmbedded@6a80e3fbb4860d00"`
 		X0 interface {
 			outerInterface
 		}
@@ -4070,7 +5264,7 @@ func InterfaceEmbedded() {
 		X2 int
 		X3 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.InterfaceEmbedded@6a80e3fbb4860d00"`
 		X0 interface {
 			outerInterface
 		}
@@ -4080,7 +5274,7 @@ func InterfaceEmbedded() {
 	}](&_c.Stack)
 	if _f0.IP == 0 {
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.InterfaceEmbedded@6a80e3fbb4860d00"`
 			X0 interface {
 				outerInterface
 			}
@@ -4096,62 +5290,80 @@ func InterfaceEmbedded() {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:774
 		_f0.X0 = innerInterfaceImpl(1)
+//line coroutine_durable.go:5296
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:775
 		_f0.X1 = _f0.X0.
 			Value()
+//line coroutine_durable.go:5303
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 4:
+//line coroutine.go:775
 		coroutine.Yield[int, any](_f0.X1)
+//line coroutine_durable.go:5309
 		_f0.IP = 4
 		fallthrough
 	case _f0.IP < 5:
+//line coroutine.go:776
 		_f0.X2 = _f0.X0.
 			Value()
+//line coroutine_durable.go:5316
 		_f0.IP = 5
 		fallthrough
 	case _f0.IP < 6:
+//line coroutine.go:776
 		coroutine.Yield[int, any](_f0.X2)
+//line coroutine_durable.go:5322
 		_f0.IP = 6
 		fallthrough
 	case _f0.IP < 7:
+//line coroutine.go:777
 		_f0.X3 = _f0.X0.
 			Value()
+//line coroutine_durable.go:5329
 		_f0.IP = 7
 		fallthrough
 	case _f0.IP < 8:
+//line coroutine.go:777
 		coroutine.Yield[int, any](_f0.X3)
 	}
+//line coroutine_durable.go:5336
 }
 
 //go:noinline
 func ClosureInSeparatePackage(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:780
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ClosureInSeparatePackage@daf531247543350e"`
 		X0 int
 		X1 func(int) int
 		X2 int
 		X3 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ClosureInSeparatePackage@daf531247543350e"`
 		X0 int
 		X1 func(int) int
 		X2 int
 		X3 int
 	}](&_c.Stack)
+//line coroutine_durable.go:5356
 	if _f0.IP == 0 {
+//line coroutine.go:780
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ClosureInSeparatePackage@daf531247543350e"`
 			X0 int
 			X1 func(int) int
 			X2 int
 			X3 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:5367
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -4159,58 +5371,68 @@ func ClosureInSeparatePackage(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:781
 		_f0.X1 = subpkg.Adder(_f0.X0)
+//line coroutine_durable.go:5377
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 5:
 		switch {
 		case _f0.IP < 3:
+//line coroutine.go:782
 			_f0.X2 = 0
+//line coroutine_durable.go:5385
 			_f0.IP = 3
 			fallthrough
 		case _f0.IP < 5:
 			for ; _f0.X2 < _f0.X0; _f0.X2, _f0.IP = _f0.X2+1, 3 {
 				switch {
 				case _f0.IP < 4:
+//line coroutine.go:783
 					_f0.X3 = _f0.X1(_f0.X2)
+//line coroutine_durable.go:5394
 					_f0.IP = 4
 					fallthrough
 				case _f0.IP < 5:
+//line coroutine.go:783
 					coroutine.Yield[int, any](_f0.X3)
 				}
 			}
 		}
 	}
+//line coroutine_durable.go:5404
 }
 
 //go:noinline
 func GenericStructClosure(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:787
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.GenericStructClosure@8a6b8ca06f5cae58"`
 		X0 int
-		X1 AdderImpl
-		X2 *GenericAdder[AdderImpl]
+		X1 *GenericAdder[AdderImpl]
+		X2 int
 		X3 int
-		X4 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.GenericStructClosure@8a6b8ca06f5cae58"`
 		X0 int
-		X1 AdderImpl
-		X2 *GenericAdder[AdderImpl]
+		X1 *GenericAdder[AdderImpl]
+		X2 int
 		X3 int
-		X4 int
 	}](&_c.Stack)
+//line coroutine_durable.go:5424
+	var _o0 AdderImpl
 	if _f0.IP == 0 {
+//line coroutine.go:787
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.GenericStructClosure@8a6b8ca06f5cae58"`
 			X0 int
-			X1 AdderImpl
-			X2 *GenericAdder[AdderImpl]
+			X1 *GenericAdder[AdderImpl]
+			X2 int
 			X3 int
-			X4 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:5436
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -4218,33 +5440,43 @@ func GenericStructClosure(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
-		_f0.X1 = AdderImpl{base: _f0.X0, mul: 2}
+//line coroutine.go:788
+		_o0 = AdderImpl{base: _f0.X0, mul: 2}
+//line coroutine_durable.go:5446
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
-		_f0.X2 = &GenericAdder[AdderImpl]{adder: _f0.X1}
+//line coroutine.go:790
+		_f0.X1 = &GenericAdder[AdderImpl]{adder: _o0}
+//line coroutine_durable.go:5452
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 6:
 		switch {
 		case _f0.IP < 4:
-			_f0.X3 = 0
+//line coroutine.go:791
+			_f0.X2 = 0
+//line coroutine_durable.go:5460
 			_f0.IP = 4
 			fallthrough
 		case _f0.IP < 6:
-			for ; _f0.X3 < _f0.X0; _f0.X3, _f0.IP = _f0.X3+1, 4 {
+			for ; _f0.X2 < _f0.X0; _f0.X2, _f0.IP = _f0.X2+1, 4 {
 				switch {
 				case _f0.IP < 5:
-					_f0.X4 = _f0.X2.
-						Add(_f0.X3)
+//line coroutine.go:792
+					_f0.X3 = _f0.X1.
+						Add(_f0.X2)
+//line coroutine_durable.go:5470
 					_f0.IP = 5
 					fallthrough
 				case _f0.IP < 6:
-					coroutine.Yield[int, any](_f0.X4)
+//line coroutine.go:792
+					coroutine.Yield[int, any](_f0.X3)
 				}
 			}
 		}
 	}
+//line coroutine_durable.go:5480
 }
 
 type adder interface {
@@ -4269,46 +5501,46 @@ func (b *GenericAdder[A]) Add(n int) int {
 //go:noinline
 func JSONRoundTrip(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:815
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.JSONRoundTrip@4fd72c93eb3a3003"`
 		X0 int
 		X1 []byte
-		X2 error
-		X3 string
-		X4 bool
-		X5 error
-		X6 struct {
+		X2 string
+		X3 bool
+		X4 error
+		X5 struct {
 			N int "json:\"n\""
 		}
-		X7 error
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.JSONRoundTrip@4fd72c93eb3a3003"`
 		X0 int
 		X1 []byte
-		X2 error
-		X3 string
-		X4 bool
-		X5 error
-		X6 struct {
+		X2 string
+		X3 bool
+		X4 error
+		X5 struct {
 			N int "json:\"n\""
 		}
-		X7 error
 	}](&_c.Stack)
+//line coroutine_durable.go:5527
+	var _o0 error
+	var _o1 error
 	if _f0.IP == 0 {
+//line coroutine.go:815
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.JSONRoundTrip@4fd72c93eb3a3003"`
 			X0 int
 			X1 []byte
-			X2 error
-			X3 string
-			X4 bool
-			X5 error
-			X6 struct {
+			X2 string
+			X3 bool
+			X4 error
+			X5 struct {
 				N int "json:\"n\""
 			}
-			X7 error
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:5544
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -4316,44 +5548,58 @@ func JSONRoundTrip(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
-		_f0.X1, _f0.X2 = json.Marshal(struct {
+//line coroutine.go:816
+		_f0.X1, _o0 = json.Marshal(struct {
 			N int `json:"n"`
 		}{_f0.X0})
+//line coroutine_durable.go:5556
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
-		if _f0.X2 != nil {
-			panic(_f0.X2)
+//line coroutine.go:819
+		if _o0 != nil {
+			panic(_o0)
 		}
+//line coroutine_durable.go:5564
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 7:
 		switch {
 		case _f0.IP < 4:
-			_f0.X3 = fmt.Sprintf(`{"n":%d}`, _f0.X0)
+//line coroutine.go:822
+			_f0.X2 = fmt.Sprintf(`{"n":%d}`, _f0.X0)
+//line coroutine_durable.go:5572
 			_f0.IP = 4
 			fallthrough
 		case _f0.IP < 5:
-			_f0.X4 = string(_f0.X1) != _f0.X3
+//line coroutine.go:822
+			_f0.X3 = string(_f0.X1) != _f0.X2
+//line coroutine_durable.go:5578
 			_f0.IP = 5
 			fallthrough
 		case _f0.IP < 7:
-			if _f0.X4 {
+			if _f0.X3 {
 				switch {
 				case _f0.IP < 6:
-					_f0.X5 = fmt.Errorf("unexpected JSON: %v", _f0.X1)
+//line coroutine.go:823
+					_f0.X4 = fmt.Errorf("unexpected JSON: %v", _f0.X1)
+//line coroutine_durable.go:5587
 					_f0.IP = 6
 					fallthrough
 				case _f0.IP < 7:
-					panic(_f0.X5)
+//line coroutine.go:823
+					panic(_f0.X4)
 				}
 			}
 		}
+//line coroutine_durable.go:5596
 		_f0.IP = 7
 		fallthrough
 	case _f0.IP < 8:
+//line coroutine.go:826
 
 		coroutine.Yield[int, any](_f0.X0)
+//line coroutine_durable.go:5603
 		_f0.IP = 8
 		fallthrough
 	case _f0.IP < 9:
@@ -4362,19 +5608,25 @@ func JSONRoundTrip(_fn0 int) {
 	case _f0.IP < 11:
 		switch {
 		case _f0.IP < 10:
-			_f0.X7 = json.Unmarshal(_f0.X1, &_f0.X6)
+//line coroutine.go:831
+			_o1 = json.Unmarshal(_f0.X1, &_f0.X5)
+//line coroutine_durable.go:5614
 			_f0.IP = 10
 			fallthrough
 		case _f0.IP < 11:
-			if _f0.X7 != nil {
-				panic(_f0.X7)
+//line coroutine.go:831
+			if _o1 != nil {
+				panic(_o1)
 			}
 		}
+//line coroutine_durable.go:5623
 		_f0.IP = 11
 		fallthrough
 	case _f0.IP < 12:
-		coroutine.Yield[int, any](_f0.X6.N)
+//line coroutine.go:834
+		coroutine.Yield[int, any](_f0.X5.N)
 	}
+//line coroutine_durable.go:5630
 }
 
 type Cloner[S ~[]E, E any] struct {
@@ -4390,8 +5642,9 @@ func (c *Cloner[S, E]) Clone() S {
 //go:noinline
 func GenericSlice(_fn0 int) {
 	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:847
 	var _f0 *struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.GenericSlice@c867b4f182443bcc"`
 		X0 int
 		X1 []int
 		X2 []int
@@ -4403,7 +5656,7 @@ func GenericSlice(_fn0 int) {
 		X8 int
 		X9 int
 	} = coroutine.Push[struct {
-		IP int
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.GenericSlice@c867b4f182443bcc"`
 		X0 int
 		X1 []int
 		X2 []int
@@ -4415,9 +5668,11 @@ func GenericSlice(_fn0 int) {
 		X8 int
 		X9 int
 	}](&_c.Stack)
+//line coroutine_durable.go:5672
 	if _f0.IP == 0 {
+//line coroutine.go:847
 		*_f0 = struct {
-			IP int
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.GenericSlice@c867b4f182443bcc"`
 			X0 int
 			X1 []int
 			X2 []int
@@ -4430,6 +5685,7 @@ func GenericSlice(_fn0 int) {
 			X9 int
 		}{X0: _fn0}
 	}
+//line coroutine_durable.go:5689
 	defer func() {
 		if !_c.Unwinding() {
 			coroutine.Pop(&_c.Stack)
@@ -4437,19 +5693,25 @@ func GenericSlice(_fn0 int) {
 	}()
 	switch {
 	case _f0.IP < 2:
+//line coroutine.go:848
 		_f0.X1 = make([]int, _f0.X0)
+//line coroutine_durable.go:5699
 		_f0.IP = 2
 		fallthrough
 	case _f0.IP < 3:
+//line coroutine.go:849
 		for i := range _f0.X1 {
 			_f0.X1[i] = i
 		}
+//line coroutine_durable.go:5707
 		_f0.IP = 3
 		fallthrough
 	case _f0.IP < 7:
 		switch {
 		case _f0.IP < 4:
+//line coroutine.go:852
 			_f0.X2 = _f0.X1
+//line coroutine_durable.go:5715
 			_f0.IP = 4
 			fallthrough
 		case _f0.IP < 7:
@@ -4462,35 +5724,47 @@ func GenericSlice(_fn0 int) {
 				for ; _f0.X3 < len(_f0.X2); _f0.X3, _f0.IP = _f0.X3+1, 5 {
 					switch {
 					case _f0.IP < 6:
+//line coroutine.go:852
 						_f0.X4 = _f0.X2[_f0.X3]
+//line coroutine_durable.go:5730
 						_f0.IP = 6
 						fallthrough
 					case _f0.IP < 7:
+//line coroutine.go:853
 
 						coroutine.Yield[int, any](_f0.X4)
 					}
 				}
 			}
 		}
+//line coroutine_durable.go:5741
 		_f0.IP = 7
 		fallthrough
 	case _f0.IP < 8:
+//line coroutine.go:856
 		_f0.X5 = &Cloner[[]int, int]{Slice: _f0.X1}
+//line coroutine_durable.go:5747
 		_f0.IP = 8
 		fallthrough
 	case _f0.IP < 9:
+//line coroutine.go:857
 		_f0.X6 = _f0.X5.Clone()
+//line coroutine_durable.go:5753
 		_f0.IP = 9
 		fallthrough
 	case _f0.IP < 10:
+//line coroutine.go:859
 
 		clear(_f0.X1)
+//line coroutine_durable.go:5760
 		_f0.IP = 10
 		fallthrough
 	case _f0.IP < 14:
 		switch {
 		case _f0.IP < 11:
+//line coroutine.go:861
 			_f0.X7 = _f0.X6
+//line coroutine_durable.go:5768
 			_f0.IP = 11
 			fallthrough
 		case _f0.IP < 14:
@@ -4503,10 +5777,13 @@ func GenericSlice(_fn0 int) {
 				for ; _f0.X8 < len(_f0.X7); _f0.X8, _f0.IP = _f0.X8+1, 12 {
 					switch {
 					case _f0.IP < 13:
+//line coroutine.go:861
 						_f0.X9 = _f0.X7[_f0.X8]
+//line coroutine_durable.go:5783
 						_f0.IP = 13
 						fallthrough
 					case _f0.IP < 14:
+//line coroutine.go:862
 
 						coroutine.Yield[int, any](_f0.X9)
 					}
@@ -4514,217 +5791,2380 @@ func GenericSlice(_fn0 int) {
 			}
 		}
 	}
+//line coroutine_durable.go:5795
 }
-func init() {
-	_types.RegisterFunc[func(_fn1 int) (_ func(int))]("github.com/dispatchrun/coroutine/compiler/testdata.(*Box).Closure")
-	_types.RegisterClosure[func(_fn0 int), struct {
-		F  uintptr
-		X0 *struct {
-			IP int
-			X0 *Box
-			X1 int
-		}
-	}]("github.com/dispatchrun/coroutine/compiler/testdata.(*Box).Closure.func1")
-	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.(*Box).YieldAndInc")
-	_types.RegisterFunc[func() (_ []int)]("github.com/dispatchrun/coroutine/compiler/testdata.(*Cloner[go.shape.[]int,go.shape.int]).Clone")
-	_types.RegisterFunc[func(n int) (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.(*GenericAdder[go.shape.struct { github.com/dispatchrun/coroutine/compiler/testdata.base int; github.com/dispatchrun/coroutine/compiler/testdata.mul int }]).Add")
-	_types.RegisterFunc[func(_fn1 int) (_ func(int))]("github.com/dispatchrun/coroutine/compiler/testdata.(*GenericBox[go.shape.int]).Closure")
-	_types.RegisterClosure[func(_fn0 int), struct {
-		F  uintptr
-		X0 *struct {
-			IP int
-			X0 *GenericBox[int]
-			X1 int
-		}
-		D uintptr
-	}]("github.com/dispatchrun/coroutine/compiler/testdata.(*GenericBox[go.shape.int]).Closure.func1")
-	_types.RegisterFunc[func(_fn1 int) (_ func(int))]("github.com/dispatchrun/coroutine/compiler/testdata.(*IdentityGenericStruct[go.shape.int]).Closure")
-	_types.RegisterClosure[func(_fn0 int), struct {
-		F  uintptr
-		X0 *struct {
-			IP int
-			X0 *IdentityGenericStruct[int]
-			X1 int
-		}
-		D uintptr
-	}]("github.com/dispatchrun/coroutine/compiler/testdata.(*IdentityGenericStruct[go.shape.int]).Closure.func1")
-	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.(*IdentityGenericStruct[go.shape.int]).Run")
-	_types.RegisterFunc[func(_fn1 int)]("github.com/dispatchrun/coroutine/compiler/testdata.(*MethodGeneratorState).MethodGenerator")
-	_types.RegisterFunc[func(n int) (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.AdderImpl.Add")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.ClosureInSeparatePackage")
-	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.Double")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.EllipsisClosure")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.EvenSquareGenerator")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.FizzBuzzIfGenerator")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.FizzBuzzSwitchGenerator")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.GenericSlice")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.GenericStructClosure")
-	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.Identity")
-	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericClosureInt")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericClosure[go.shape.int]")
-	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericInt")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericStructClosureInt")
-	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericStructInt")
-	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.IdentityGeneric[go.shape.int]")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.IndirectClosure")
-	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.InterfaceEmbedded")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.JSONRoundTrip")
-	_types.RegisterFunc[func(_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.LoopBreakAndContinue")
-	_types.RegisterFunc[func(_fn0 ...int) (_ func())]("github.com/dispatchrun/coroutine/compiler/testdata.MakeEllipsisClosure")
-	_types.RegisterClosure[func(), struct {
-		F  uintptr
-		X0 *struct {
-			IP int
-			X0 []int
-		}
-	}]("github.com/dispatchrun/coroutine/compiler/testdata.MakeEllipsisClosure.func1")
-	_types.RegisterFunc[func(_fn0 int) (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.NestedLoops")
-	_types.RegisterFunc[func(_fn0 int, _fn1 func(int))]("github.com/dispatchrun/coroutine/compiler/testdata.Range")
-	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers")
-	_types.RegisterClosure[func() (_ bool), struct {
-		F  uintptr
-		X0 *struct {
-			IP int
-			X0 int
-			X1 int
-			X2 *int
-			X3 *int
-			X4 func() bool
-			X5 bool
-			X6 bool
-		}
-	}]("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers.func2")
-	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues")
-	_types.RegisterClosure[func() (_ bool), struct {
-		F  uintptr
-		X0 *struct {
-			IP int
+
+type Notifier interface {
+	//coroutine:noyield
+	Notify(int)
+}
+
+type yieldingNotifier struct{}
+
+//go:noinline
+func (yieldingNotifier) Notify(n int) {
+//line coroutine.go:873
+	coroutine.Yield[int, any](n)
+//line coroutine_durable.go:5809
+}
+
+type countingNotifier struct{ count *int }
+
+func (c countingNotifier) Notify(n int) { *c.count += n }
+
+// notifyAll would be colored because of the yieldingNotifier implementation
+// of Notifier (and rejected because of the goto statement), but the noyield
+// directive on the method prunes the edge.
+func notifyAll(notifiers []Notifier, n int) {
+	i := 0
+loop:
+	if i < len(notifiers) {
+		notifiers[i].Notify(n)
+		i++
+		goto loop
+	}
+}
+
+//go:noinline
+func NoYieldDirective(_fn0 int) {
+	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:892
+	var _f0 *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.NoYieldDirective@543b83cfa14e5fdf"`
+		X0 int
+		X1 int
+		X2 []Notifier
+		X3 int
+	} = coroutine.Push[struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.NoYieldDirective@543b83cfa14e5fdf"`
+		X0 int
+		X1 int
+		X2 []Notifier
+		X3 int
+	}](&_c.Stack)
+//line coroutine_durable.go:5846
+	if _f0.IP == 0 {
+//line coroutine.go:892
+		*_f0 = struct {
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.NoYieldDirective@543b83cfa14e5fdf"`
 			X0 int
 			X1 int
-			X2 func() bool
-			X3 bool
-			X4 bool
+			X2 []Notifier
+			X3 int
+		}{X0: _fn0}
+	}
+//line coroutine_durable.go:5857
+	defer func() {
+		if !_c.Unwinding() {
+			coroutine.Pop(&_c.Stack)
 		}
-	}]("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues.func2")
-	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture")
-	_types.RegisterClosure[func() (_ int), struct {
-		F  uintptr
-		X0 *struct {
-			IP  int
-			X0  int8
-			X1  int16
-			X2  int32
-			X3  int64
-			X4  uint8
-			X5  uint16
-			X6  uint32
-			X7  uint64
-			X8  uintptr
-			X9  func() int
-			X10 int
-			X11 func() bool
-			X12 bool
-			X13 bool
+	}()
+	switch {
+	case _f0.IP < 2:
+//line coroutine.go:893
+		_f0.X1 = 0
+//line coroutine_durable.go:5867
+		_f0.IP = 2
+		fallthrough
+	case _f0.IP < 3:
+//line coroutine.go:894
+		_f0.X2 = []Notifier{countingNotifier{&_f0.X1}}
+//line coroutine_durable.go:5873
+		_f0.IP = 3
+		fallthrough
+	case _f0.IP < 4:
+//line coroutine.go:895
+		if _f0.X0 < 0 {
+			_f0.X2 = append(_f0.X2, yieldingNotifier{})
 		}
-	}]("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture.func2")
-	_types.RegisterClosure[func() (_ bool), struct {
-		F  uintptr
-		X0 *struct {
-			IP  int
-			X0  int8
-			X1  int16
-			X2  int32
-			X3  int64
-			X4  uint8
-			X5  uint16
-			X6  uint32
-			X7  uint64
-			X8  uintptr
-			X9  func() int
-			X10 int
-			X11 func() bool
-			X12 bool
-			X13 bool
+//line coroutine_durable.go:5881
+		_f0.IP = 4
+		fallthrough
+	case _f0.IP < 7:
+		switch {
+		case _f0.IP < 5:
+//line coroutine.go:898
+			_f0.X3 = 1
+//line coroutine_durable.go:5889
+			_f0.IP = 5
+			fallthrough
+		case _f0.IP < 7:
+			for ; _f0.X3 <= _f0.X0; _f0.X3, _f0.IP = _f0.X3+1, 5 {
+				switch {
+				case _f0.IP < 6:
+//line coroutine.go:899
+					notifyAll(_f0.X2, _f0.X3)
+//line coroutine_durable.go:5898
+					_f0.IP = 6
+					fallthrough
+				case _f0.IP < 7:
+//line coroutine.go:900
+					coroutine.Yield[int, any](_f0.X1)
+				}
+			}
 		}
-	}]("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture.func3")
-	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.Range10Heterogenous")
-	_types.RegisterFunc[func(_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeArrayIndexValueGenerator")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeOverInt")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeOverMaps")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeReverseClosureCaptureByValue")
-	_types.RegisterClosure[func(), struct {
-		F  uintptr
-		X0 *struct {
-			IP int
+	}
+//line coroutine_durable.go:5908
+}
+
+//go:noinline
+func forcedYield(_fn0 int) (_ int) {
+	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:908
+	var _f0 *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.forcedYield@fb0c79caa0f3c37a"`
+		X0 int
+	} = coroutine.Push[struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.forcedYield@fb0c79caa0f3c37a"`
+		X0 int
+	}](&_c.Stack)
+//line coroutine_durable.go:5922
+	if _f0.IP == 0 {
+//line coroutine.go:908
+		*_f0 = struct {
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.forcedYield@fb0c79caa0f3c37a"`
 			X0 int
-			X1 int
-			X2 func()
+		}{X0: _fn0}
+	}
+//line coroutine_durable.go:5930
+	defer func() {
+		if !_c.Unwinding() {
+			coroutine.Pop(&_c.Stack)
 		}
-	}]("github.com/dispatchrun/coroutine/compiler/testdata.RangeReverseClosureCaptureByValue.func2")
-	_types.RegisterFunc[func(_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeSliceIndexGenerator")
-	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeTriple")
-	_types.RegisterFunc[func(i int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeTriple.func1")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeTripleFuncValue")
-	_types.RegisterFunc[func(i int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeTripleFuncValue.func2")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeYieldAndDeferAssign")
-	_types.RegisterFunc[func(_fn0 ...reflect.Type)]("github.com/dispatchrun/coroutine/compiler/testdata.ReflectType")
-	_types.RegisterFunc[func() (_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.ReturnNamedValue")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.Select")
-	_types.RegisterFunc[func(_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.Shadowing")
-	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.SomeFunctionThatShouldExistInTheCompiledFile")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.SquareGenerator")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.SquareGeneratorTwice")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.SquareGeneratorTwiceLoop")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.StructClosure")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.StructGenericClosure")
-	_types.RegisterFunc[func(_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.TypeSwitchingGenerator")
-	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.VarArgs")
-	_types.RegisterFunc[func(_fn0 *int, _fn1, _fn2 int)]("github.com/dispatchrun/coroutine/compiler/testdata.YieldAndDeferAssign")
-	_types.RegisterClosure[func(), struct {
-		F  uintptr
-		X0 *struct {
-			IP int
-			X0 *int
+	}()
+//line coroutine.go:909
+	return double(_f0.X0)
+//line coroutine_durable.go:5938
+}
+
+func double(n int) int {
+	return n * 2
+}
+
+//go:noinline
+func YieldsDirective(_fn0 int) {
+	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:916
+	var _f0 *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldsDirective@b38d21ec721eac3b"`
+		X0 int
+		X1 int
+		X2 int
+	} = coroutine.Push[struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldsDirective@b38d21ec721eac3b"`
+		X0 int
+		X1 int
+		X2 int
+	}](&_c.Stack)
+//line coroutine_durable.go:5960
+	if _f0.IP == 0 {
+//line coroutine.go:916
+		*_f0 = struct {
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldsDirective@b38d21ec721eac3b"`
+			X0 int
 			X1 int
 			X2 int
-			X3 []func()
+		}{X0: _fn0}
+	}
+//line coroutine_durable.go:5970
+	defer func() {
+		if !_c.Unwinding() {
+			coroutine.Pop(&_c.Stack)
 		}
-	}]("github.com/dispatchrun/coroutine/compiler/testdata.YieldAndDeferAssign.func2")
-	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations")
-	_types.RegisterClosure[func(), struct {
-		F  uintptr
-		X0 *struct {
-			IP int
-			X0 *time.Duration
-			X1 time.Duration
-			X2 func()
-			X3 int
+	}()
+	switch {
+	case _f0.IP < 2:
+//line coroutine.go:917
+		_f0.X1 = 0
+//line coroutine_durable.go:5980
+		_f0.IP = 2
+		fallthrough
+	case _f0.IP < 4:
+		for ; _f0.X1 < _f0.X0; _f0.X1, _f0.IP = _f0.X1+1, 2 {
+			switch {
+			case _f0.IP < 3:
+//line coroutine.go:918
+				_f0.X2 = forcedYield(_f0.X1)
+//line coroutine_durable.go:5989
+				_f0.IP = 3
+				fallthrough
+			case _f0.IP < 4:
+//line coroutine.go:918
+				coroutine.Yield[int, any](_f0.X2)
+			}
 		}
-	}]("github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations.func2")
-	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.YieldingExpressionDesugaring")
-	_types.RegisterFunc[func(_fn0 int) (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.a")
-	_types.RegisterFunc[func(_fn0 int) (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.b")
-	_types.RegisterFunc[func(_fn0 int) (_ func())]("github.com/dispatchrun/coroutine/compiler/testdata.buildClosure[go.shape.int]")
-	_types.RegisterClosure[func(), struct {
+	}
+//line coroutine_durable.go:5998
+}
+
+//go:noinline
+func ChannelBuffer(_fn0 int) {
+	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:924
+	var _f0 *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ChannelBuffer@54a83952369eec41"`
+		X0 int
+		X1 chan int
+		X2 int
+		X3 int
+	} = coroutine.Push[struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ChannelBuffer@54a83952369eec41"`
+		X0 int
+		X1 chan int
+		X2 int
+		X3 int
+	}](&_c.Stack)
+//line coroutine_durable.go:6018
+	var _o0 bool
+	if _f0.IP == 0 {
+//line coroutine.go:924
+		*_f0 = struct {
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ChannelBuffer@54a83952369eec41"`
+			X0 int
+			X1 chan int
+			X2 int
+			X3 int
+		}{X0: _fn0}
+	}
+//line coroutine_durable.go:6030
+	defer func() {
+		if !_c.Unwinding() {
+			coroutine.Pop(&_c.Stack)
+		}
+	}()
+	switch {
+	case _f0.IP < 2:
+//line coroutine.go:925
+		_f0.X1 = make(chan int, _f0.X0)
+//line coroutine_durable.go:6040
+		_f0.IP = 2
+		fallthrough
+	case _f0.IP < 3:
+//line coroutine.go:926
+		for _f0.X2 = 1; _f0.X2 <= _f0.X0; _f0.X2++ {
+//line coroutine_durable.go:6046
+			_f0.X1 <- _f0.X2 * _f0.X2
+		}
+		_f0.IP = 3
+		fallthrough
+	case _f0.IP < 4:
+//line coroutine.go:929
+		close(_f0.X1)
+//line coroutine_durable.go:6054
+		_f0.IP = 4
+		fallthrough
+	case _f0.IP < 7:
+//line coroutine.go:930
+	_l0:
+		for ; ; _f0.IP = 4 {
+//line coroutine_durable.go:6061
+			switch {
+			case _f0.IP < 5:
+//line coroutine.go:931
+				_f0.X3, _o0 = <-_f0.X1
+//line coroutine_durable.go:6066
+				_f0.IP = 5
+				fallthrough
+			case _f0.IP < 6:
+//line coroutine.go:932
+				if !_o0 {
+					break _l0
+				}
+//line coroutine_durable.go:6074
+				_f0.IP = 6
+				fallthrough
+			case _f0.IP < 7:
+//line coroutine.go:935
+
+				coroutine.Yield[int, any](_f0.X3)
+			}
+		}
+	}
+//line coroutine_durable.go:6084
+}
+
+// transientCache memoizes squares; the cache is not part of the state of
+// coroutines and starts empty when they are resumed.
+type transientCache struct {
+	squares map[int]int `coroutine:"-"`
+}
+
+func (c *transientCache) square(i int) int {
+	if c.squares == nil {
+		c.squares = make(map[int]int)
+	}
+	v, ok := c.squares[i]
+	if !ok {
+		v = i * i
+		c.squares[i] = v
+	}
+	return v
+}
+
+//go:noinline
+func TransientField(_fn0 int) {
+	_c := coroutine.LoadContext[int, any]()
+//line coroutine.go:957
+	var _f0 *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.TransientField@d4ac97491bc97a2f"`
+		X0 int
+		X1 transientCache
+		X2 int
+		X3 int
+	} = coroutine.Push[struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.TransientField@d4ac97491bc97a2f"`
+		X0 int
+		X1 transientCache
+		X2 int
+		X3 int
+	}](&_c.Stack)
+//line coroutine_durable.go:6122
+	if _f0.IP == 0 {
+//line coroutine.go:957
+		*_f0 = struct {
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.TransientField@d4ac97491bc97a2f"`
+			X0 int
+			X1 transientCache
+			X2 int
+			X3 int
+		}{X0: _fn0}
+	}
+//line coroutine_durable.go:6133
+	defer func() {
+		if !_c.Unwinding() {
+			coroutine.Pop(&_c.Stack)
+		}
+	}()
+	switch {
+	case _f0.IP < 2:
+//line coroutine.go:958
+		_f0.X1 = transientCache{}
+//line coroutine_durable.go:6143
+		_f0.IP = 2
+		fallthrough
+	case _f0.IP < 5:
+		switch {
+		case _f0.IP < 3:
+//line coroutine.go:959
+			_f0.X2 = 1
+//line coroutine_durable.go:6151
+			_f0.IP = 3
+			fallthrough
+		case _f0.IP < 5:
+			for ; _f0.X2 <= _f0.X0; _f0.X2, _f0.IP = _f0.X2+1, 3 {
+				switch {
+				case _f0.IP < 4:
+//line coroutine.go:960
+					_f0.X3 = _f0.X1.
+						square(_f0.X2)
+//line coroutine_durable.go:6161
+					_f0.IP = 4
+					fallthrough
+				case _f0.IP < 5:
+//line coroutine.go:960
+					coroutine.Yield[int, any](_f0.X3)
+				}
+			}
+		}
+	}
+//line coroutine_durable.go:6171
+}
+func init() {
+	_types.RegisterFunc[func(_fn1 int) (_ func(int))]("github.com/dispatchrun/coroutine/compiler/testdata.(*Box).Closure")
+	_types.RegisterClosure[func(_fn0 int), struct {
 		F  uintptr
 		X0 *struct {
 			IP int
-			X0 int
+			X0 *Box
+			X1 int
+		}
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.(*Box).Closure.func1")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.(*Box).Closure.func1", "github.com/dispatchrun/coroutine/compiler/testdata.(*Box).Closure.func-25d626574e77fb91")
+	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.(*Box).YieldAndInc")
+	_types.RegisterFunc[func() (_ []int)]("github.com/dispatchrun/coroutine/compiler/testdata.(*Cloner[go.shape.[]int,go.shape.int]).Clone")
+	_types.RegisterFunc[func(n int) (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.(*GenericAdder[go.shape.struct { github.com/dispatchrun/coroutine/compiler/testdata.base int; github.com/dispatchrun/coroutine/compiler/testdata.mul int }]).Add")
+	_types.RegisterFunc[func(_fn1 int) (_ func(int))]("github.com/dispatchrun/coroutine/compiler/testdata.(*GenericBox[go.shape.int]).Closure")
+	_types.RegisterClosure[func(_fn0 int), struct {
+		F  uintptr
+		X0 *struct {
+			IP int
+			X0 *GenericBox[int]
+			X1 int
 		}
 		D uintptr
-	}]("github.com/dispatchrun/coroutine/compiler/testdata.buildClosure[go.shape.int].func1")
-	_types.RegisterFunc[func(_fn0 interface {
-		YieldAndInc()
-	}) (_ func())]("github.com/dispatchrun/coroutine/compiler/testdata.indirectClosure")
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.(*GenericBox[go.shape.int]).Closure.func1")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.(*GenericBox[go.shape.int]).Closure.func1", "github.com/dispatchrun/coroutine/compiler/testdata.(*GenericBox[go.shape.int]).Closure.func-3a2fd83eecd5fdd0")
+	_types.RegisterFunc[func(_fn1 int) (_ func(int))]("github.com/dispatchrun/coroutine/compiler/testdata.(*IdentityGenericStruct[go.shape.int]).Closure")
+	_types.RegisterClosure[func(_fn0 int), struct {
+		F  uintptr
+		X0 *struct {
+			IP int
+			X0 *IdentityGenericStruct[int]
+			X1 int
+		}
+		D uintptr
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.(*IdentityGenericStruct[go.shape.int]).Closure.func1")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.(*IdentityGenericStruct[go.shape.int]).Closure.func1", "github.com/dispatchrun/coroutine/compiler/testdata.(*IdentityGenericStruct[go.shape.int]).Closure.func-3c786a1f671607a2")
+	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.(*IdentityGenericStruct[go.shape.int]).Run")
+	_types.RegisterFunc[func(_fn1 int)]("github.com/dispatchrun/coroutine/compiler/testdata.(*MethodGeneratorState).MethodGenerator")
+	_types.RegisterFunc[func(i int) (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.(*transientCache).square")
+	_types.RegisterFunc[func(n int) (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.AdderImpl.Add")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.ChannelBuffer")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.ClosureInSeparatePackage")
+	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.Double")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.EllipsisClosure")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.EvenSquareGenerator")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.FizzBuzzIfGenerator")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.FizzBuzzSwitchGenerator")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.GenericSlice")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.GenericStructClosure")
+	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.Identity")
+	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericClosureInt")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericClosure[go.shape.int]")
+	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericInt")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericStructClosureInt")
+	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericStructInt")
+	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.IdentityGeneric[go.shape.int]")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.IndirectClosure")
+	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.InterfaceEmbedded")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.JSONRoundTrip")
+	_types.RegisterFunc[func(_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.LoopBreakAndContinue")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures")
+	_types.RegisterClosure[func() (_ int), struct {
+		F  uintptr
+		X0 *int
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures.func2")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures.func2", "github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures.func-cdd33a1b4e45f3dc")
+	_types.RegisterClosure[func() (_ int), struct {
+		F  uintptr
+		X0 *int
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures.func3")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures.func3", "github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures.func-cc5335cdc55b9f1c")
+	_types.RegisterClosure[func(), struct {
+		F  uintptr
+		X0 *int
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures.func4")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures.func4", "github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures.func-85953620322c7441")
+	_types.RegisterFunc[func(_fn0 ...int) (_ func())]("github.com/dispatchrun/coroutine/compiler/testdata.MakeEllipsisClosure")
 	_types.RegisterClosure[func(), struct {
 		F  uintptr
 		X0 *struct {
 			IP int
-			X0 interface {
-				YieldAndInc()
-			}
+			X0 []int
 		}
-	}]("github.com/dispatchrun/coroutine/compiler/testdata.indirectClosure.func2")
-	_types.RegisterFunc[func() (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.innerInterfaceImpl.Value")
-	_types.RegisterFunc[func(_fn0 ...int)]("github.com/dispatchrun/coroutine/compiler/testdata.varArgs")
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.MakeEllipsisClosure.func1")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.MakeEllipsisClosure.func1", "github.com/dispatchrun/coroutine/compiler/testdata.MakeEllipsisClosure.func-2103fd6482a0d1a9")
+	_types.RegisterFunc[func(_fn0 int) (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.NestedLoops")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.NoYieldDirective")
+	_types.RegisterFunc[func(_fn0 int, _fn1 func(int))]("github.com/dispatchrun/coroutine/compiler/testdata.Range")
+	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers")
+	_types.RegisterClosure[func() (_ bool), struct {
+		F  uintptr
+		X0 *struct {
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers@9aad2ee0908977dc"`
+			X0 *int
+			X1 *int
+			X2 func() bool
+			X3 bool
+			X4 bool
+		}
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers.func2")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers.func2", "github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers.func-3ffda48370be3eb1")
+	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues")
+	_types.RegisterClosure[func() (_ bool), struct {
+		F  uintptr
+		X0 *struct {
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues@75f89957a2e7d54a"`
+			X0 int
+			X1 int
+			X2 func() bool
+			X3 bool
+			X4 bool
+		}
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues.func2")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues.func2", "github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues.func-20ff7df9921c2f46")
+	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture")
+	_types.RegisterClosure[func() (_ int), struct {
+		F  uintptr
+		X0 *struct {
+			IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture@53399881f6748171"`
+			X0  int8
+			X1  int16
+			X2  int32
+			X3  int64
+			X4  uint8
+			X5  uint16
+			X6  uint32
+			X7  uint64
+			X8  uintptr
+			X9  func() int
+			X10 int
+			X11 func() bool
+			X12 bool
+			X13 bool
+		}
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture.func2")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture.func2", "github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture.func-edd6aee8f8d55a6f")
+	_types.RegisterClosure[func() (_ bool), struct {
+		F  uintptr
+		X0 *struct {
+			IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture@53399881f6748171"`
+			X0  int8
+			X1  int16
+			X2  int32
+			X3  int64
+			X4  uint8
+			X5  uint16
+			X6  uint32
+			X7  uint64
+			X8  uintptr
+			X9  func() int
+			X10 int
+			X11 func() bool
+			X12 bool
+			X13 bool
+		}
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture.func3")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture.func3", "github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture.func-dffafab4e0e19f83")
+	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.Range10Heterogenous")
+	_types.RegisterFunc[func(_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeArrayIndexValueGenerator")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeOverInt")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeOverMaps")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeReverseClosureCaptureByValue")
+	_types.RegisterClosure[func(), struct {
+		F  uintptr
+		X0 *struct {
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeReverseClosureCaptureByValue@f0643c5b617867a9"`
+			X0 int
+			X1 int
+			X2 func()
+		}
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.RangeReverseClosureCaptureByValue.func2")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.RangeReverseClosureCaptureByValue.func2", "github.com/dispatchrun/coroutine/compiler/testdata.RangeReverseClosureCaptureByValue.func-968d53e2ead9e63e")
+	_types.RegisterFunc[func(_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeSliceIndexGenerator")
+	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeTriple")
+	_types.RegisterFunc[func(i int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeTriple.func1")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.RangeTriple.func1", "github.com/dispatchrun/coroutine/compiler/testdata.RangeTriple.func-53815c292de16fb7")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeTripleFuncValue")
+	_types.RegisterFunc[func(i int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeTripleFuncValue.func2")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.RangeTripleFuncValue.func2", "github.com/dispatchrun/coroutine/compiler/testdata.RangeTripleFuncValue.func-53815c292de16fb7")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.RangeYieldAndDeferAssign")
+	_types.RegisterFunc[func(_fn0 ...reflect.Type)]("github.com/dispatchrun/coroutine/compiler/testdata.ReflectType")
+	_types.RegisterFunc[func() (_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.ReturnNamedValue")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.Select")
+	_types.RegisterFunc[func(_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.Shadowing")
+	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.SomeFunctionThatShouldExistInTheCompiledFile")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.SquareGenerator")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.SquareGeneratorTwice")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.SquareGeneratorTwiceLoop")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.StructClosure")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.StructGenericClosure")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.TransientField")
+	_types.RegisterFunc[func(_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.TypeSwitchingGenerator")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.VarArgs")
+	_types.RegisterFunc[func(_fn0 *int, _fn1, _fn2 int)]("github.com/dispatchrun/coroutine/compiler/testdata.YieldAndDeferAssign")
+	_types.RegisterClosure[func(), struct {
+		F  uintptr
+		X0 *struct {
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldAndDeferAssign@e17c0ca722b4aa32"`
+			X0 *int
+			X1 int
+			X2 int
+			X3 []func()
+		}
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.YieldAndDeferAssign.func2")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.YieldAndDeferAssign.func2", "github.com/dispatchrun/coroutine/compiler/testdata.YieldAndDeferAssign.func-5efbbd9356ac67ae")
+	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations")
+	_types.RegisterClosure[func(), struct {
+		F  uintptr
+		X0 *struct {
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations@3f9d8b15962ed143"`
+			X0 *time.Duration
+			X1 time.Duration
+			X2 func()
+			X3 int
+		}
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations.func2")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations.func2", "github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations.func-de62ebd8731d98f2")
+	_types.RegisterFunc[func()]("github.com/dispatchrun/coroutine/compiler/testdata.YieldingExpressionDesugaring")
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.YieldsDirective")
+	_types.RegisterFunc[func(_fn0 int) (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.a")
+	_types.RegisterFunc[func(_fn0 int) (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.b")
+	_types.RegisterFunc[func(_fn0 int) (_ func())]("github.com/dispatchrun/coroutine/compiler/testdata.buildClosure[go.shape.int]")
+	_types.RegisterClosure[func(), struct {
+		F  uintptr
+		X0 *struct {
+			IP int
+			X0 int
+		}
+		D uintptr
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.buildClosure[go.shape.int].func1")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.buildClosure[go.shape.int].func1", "github.com/dispatchrun/coroutine/compiler/testdata.buildClosure[go.shape.int].func-f60b92eb342a4fb8")
+	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.countingNotifier.Notify")
+	_types.RegisterFunc[func(n int) (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.double")
+	_types.RegisterFunc[func(_fn0 int) (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.forcedYield")
+	_types.RegisterFunc[func(_fn0 interface {
+		YieldAndInc()
+	}) (_ func())]("github.com/dispatchrun/coroutine/compiler/testdata.indirectClosure")
+	_types.RegisterClosure[func(), struct {
+		F  uintptr
+		X0 *struct {
+			IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.indirectClosure@2af7e513f568f296"`
+			X0 interface {
+				YieldAndInc()
+			}
+		}
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.indirectClosure.func2")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.indirectClosure.func2", "github.com/dispatchrun/coroutine/compiler/testdata.indirectClosure.func-f3525d85e8f27b78")
+	_types.RegisterFunc[func() (_ int)]("github.com/dispatchrun/coroutine/compiler/testdata.innerInterfaceImpl.Value")
+	_types.RegisterFunc[func(notifiers []Notifier, n int)]("github.com/dispatchrun/coroutine/compiler/testdata.notifyAll")
+	_types.RegisterFunc[func(_fn0 ...int)]("github.com/dispatchrun/coroutine/compiler/testdata.varArgs")
+	_types.RegisterFunc[func(n int)]("github.com/dispatchrun/coroutine/compiler/testdata.yieldingNotifier.Notify")
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.SquareGenerator@c655cb4854991333"`
+		X0 int
+		X1 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.SquareGenerator@c655cb4854991333"`
+		X0 int
+		X1 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.SquareGeneratorTwice@e842ebcb555b67e6"`
+		X0 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.SquareGeneratorTwice@e842ebcb555b67e6"`
+		X0 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.EvenSquareGenerator@b0f75a8c0071d093"`
+		X0 int
+		X1 int
+		X2 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.EvenSquareGenerator@b0f75a8c0071d093"`
+		X0 int
+		X1 int
+		X2 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.NestedLoops@f14fdf4dd3902d03"`
+		X0 int
+		X1 int
+		X2 int
+		X3 int
+		X4 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.NestedLoops@f14fdf4dd3902d03"`
+		X0 int
+		X1 int
+		X2 int
+		X3 int
+		X4 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.FizzBuzzSwitchGenerator@b600f193aa9c22ca"`
+		X0 int
+		X1 int
+		X2 bool
+		X3 bool
+		X4 bool
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.FizzBuzzSwitchGenerator@b600f193aa9c22ca"`
+		X0 int
+		X1 int
+		X2 bool
+		X3 bool
+		X4 bool
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Shadowing@5d6f70650829da51"`
+		X0  int
+		X1  int
+		X2  int
+		X3  int
+		X4  int
+		X5  bool
+		X6  int
+		X7  int
+		X8  int
+		X9  int
+		X10 int
+		X11 int
+		X12 int
+		X13 uintptr
+		X14 int
+		X15 uintptr
+		X16 int
+		X17 uintptr
+		X18 int
+		X19 uintptr
+		X20 int
+		X21 uintptr
+		X22 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+		_types.SerializeField(s, &x.X5)
+		_types.SerializeField(s, &x.X6)
+		_types.SerializeField(s, &x.X7)
+		_types.SerializeField(s, &x.X8)
+		_types.SerializeField(s, &x.X9)
+		_types.SerializeField(s, &x.X10)
+		_types.SerializeField(s, &x.X11)
+		_types.SerializeField(s, &x.X12)
+		_types.SerializeField(s, &x.X13)
+		_types.SerializeField(s, &x.X14)
+		_types.SerializeField(s, &x.X15)
+		_types.SerializeField(s, &x.X16)
+		_types.SerializeField(s, &x.X17)
+		_types.SerializeField(s, &x.X18)
+		_types.SerializeField(s, &x.X19)
+		_types.SerializeField(s, &x.X20)
+		_types.SerializeField(s, &x.X21)
+		_types.SerializeField(s, &x.X22)
+	}, func(d *_types.Deserializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Shadowing@5d6f70650829da51"`
+		X0  int
+		X1  int
+		X2  int
+		X3  int
+		X4  int
+		X5  bool
+		X6  int
+		X7  int
+		X8  int
+		X9  int
+		X10 int
+		X11 int
+		X12 int
+		X13 uintptr
+		X14 int
+		X15 uintptr
+		X16 int
+		X17 uintptr
+		X18 int
+		X19 uintptr
+		X20 int
+		X21 uintptr
+		X22 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+		_types.DeserializeField(d, &x.X5)
+		_types.DeserializeField(d, &x.X6)
+		_types.DeserializeField(d, &x.X7)
+		_types.DeserializeField(d, &x.X8)
+		_types.DeserializeField(d, &x.X9)
+		_types.DeserializeField(d, &x.X10)
+		_types.DeserializeField(d, &x.X11)
+		_types.DeserializeField(d, &x.X12)
+		_types.DeserializeField(d, &x.X13)
+		_types.DeserializeField(d, &x.X14)
+		_types.DeserializeField(d, &x.X15)
+		_types.DeserializeField(d, &x.X16)
+		_types.DeserializeField(d, &x.X17)
+		_types.DeserializeField(d, &x.X18)
+		_types.DeserializeField(d, &x.X19)
+		_types.DeserializeField(d, &x.X20)
+		_types.DeserializeField(d, &x.X21)
+		_types.DeserializeField(d, &x.X22)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeSliceIndexGenerator@d5e40a2939c39085"`
+		X0 []int
+		X1 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeSliceIndexGenerator@d5e40a2939c39085"`
+		X0 []int
+		X1 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeArrayIndexValueGenerator@8a5fd5286ef4fb01"`
+		X0 [3]int
+		X1 int
+		X2 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeArrayIndexValueGenerator@8a5fd5286ef4fb01"`
+		X0 [3]int
+		X1 int
+		X2 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.TypeSwitchingGenerator@6123141c1f6af071"`
+		X0 []any
+		X1 int
+		X2 any
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.TypeSwitchingGenerator@6123141c1f6af071"`
+		X0 []any
+		X1 int
+		X2 any
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopBreakAndContinue@4f6231e9ad0c4daa"`
+		X0 int
+		X1 int
+		X2 int
+		X3 int
+		X4 bool
+		X5 bool
+		X6 int
+		X7 bool
+		X8 bool
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+		_types.SerializeField(s, &x.X5)
+		_types.SerializeField(s, &x.X6)
+		_types.SerializeField(s, &x.X7)
+		_types.SerializeField(s, &x.X8)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopBreakAndContinue@4f6231e9ad0c4daa"`
+		X0 int
+		X1 int
+		X2 int
+		X3 int
+		X4 bool
+		X5 bool
+		X6 int
+		X7 bool
+		X8 bool
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+		_types.DeserializeField(d, &x.X5)
+		_types.DeserializeField(d, &x.X6)
+		_types.DeserializeField(d, &x.X7)
+		_types.DeserializeField(d, &x.X8)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeOverMaps@f783f24fa9201e8f"`
+		X0  int
+		X1  map[int]int
+		X2  map[int]int
+		X3  int
+		X4  map[int]int
+		X5  []int
+		X6  []int
+		X7  int
+		X8  int
+		X9  bool
+		X10 map[int]int
+		X11 []int
+		X12 []int
+		X13 int
+		X14 int
+		X15 int
+		X16 bool
+		X17 map[int]struct {
+		}
+		X18 int
+		X19 map[int]struct {
+		}
+		X20 []int
+		X21 []int
+		X22 int
+		X23 int
+		X24 bool
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+		_types.SerializeField(s, &x.X5)
+		_types.SerializeField(s, &x.X6)
+		_types.SerializeField(s, &x.X7)
+		_types.SerializeField(s, &x.X8)
+		_types.SerializeField(s, &x.X9)
+		_types.SerializeField(s, &x.X10)
+		_types.SerializeField(s, &x.X11)
+		_types.SerializeField(s, &x.X12)
+		_types.SerializeField(s, &x.X13)
+		_types.SerializeField(s, &x.X14)
+		_types.SerializeField(s, &x.X15)
+		_types.SerializeField(s, &x.X16)
+		_types.SerializeField(s, &x.X17)
+		_types.SerializeField(s, &x.X18)
+		_types.SerializeField(s, &x.X19)
+		_types.SerializeField(s, &x.X20)
+		_types.SerializeField(s, &x.X21)
+		_types.SerializeField(s, &x.X22)
+		_types.SerializeField(s, &x.X23)
+		_types.SerializeField(s, &x.X24)
+	}, func(d *_types.Deserializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeOverMaps@f783f24fa9201e8f"`
+		X0  int
+		X1  map[int]int
+		X2  map[int]int
+		X3  int
+		X4  map[int]int
+		X5  []int
+		X6  []int
+		X7  int
+		X8  int
+		X9  bool
+		X10 map[int]int
+		X11 []int
+		X12 []int
+		X13 int
+		X14 int
+		X15 int
+		X16 bool
+		X17 map[int]struct {
+		}
+		X18 int
+		X19 map[int]struct {
+		}
+		X20 []int
+		X21 []int
+		X22 int
+		X23 int
+		X24 bool
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+		_types.DeserializeField(d, &x.X5)
+		_types.DeserializeField(d, &x.X6)
+		_types.DeserializeField(d, &x.X7)
+		_types.DeserializeField(d, &x.X8)
+		_types.DeserializeField(d, &x.X9)
+		_types.DeserializeField(d, &x.X10)
+		_types.DeserializeField(d, &x.X11)
+		_types.DeserializeField(d, &x.X12)
+		_types.DeserializeField(d, &x.X13)
+		_types.DeserializeField(d, &x.X14)
+		_types.DeserializeField(d, &x.X15)
+		_types.DeserializeField(d, &x.X16)
+		_types.DeserializeField(d, &x.X17)
+		_types.DeserializeField(d, &x.X18)
+		_types.DeserializeField(d, &x.X19)
+		_types.DeserializeField(d, &x.X20)
+		_types.DeserializeField(d, &x.X21)
+		_types.DeserializeField(d, &x.X22)
+		_types.DeserializeField(d, &x.X23)
+		_types.DeserializeField(d, &x.X24)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range@40c5ac9c364c8d40"`
+		X0 int
+		X1 func(int)
+		X2 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range@40c5ac9c364c8d40"`
+		X0 int
+		X1 func(int)
+		X2 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeTripleFuncValue@30a2b825a9a2e158"`
+		X0 int
+		X1 func(int)
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeTripleFuncValue@30a2b825a9a2e158"`
+		X0 int
+		X1 func(int)
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeReverseClosureCaptureByValue@f0643c5b617867a9"`
+		X0 int
+		X1 int
+		X2 func()
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeReverseClosureCaptureByValue@f0643c5b617867a9"`
+		X0 int
+		X1 int
+		X2 func()
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues@11f7b3a23c59b515"`
+	}) {
+		_types.SerializeField(s, &x.IP)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues@11f7b3a23c59b515"`
+	}) {
+		_types.DeserializeField(d, &x.IP)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues@75f89957a2e7d54a"`
+		X0 int
+		X1 int
+		X2 func() bool
+		X3 bool
+		X4 bool
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues@75f89957a2e7d54a"`
+		X0 int
+		X1 int
+		X2 func() bool
+		X3 bool
+		X4 bool
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers@9aad2ee0908977dc"`
+		X0 *int
+		X1 *int
+		X2 func() bool
+		X3 bool
+		X4 bool
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers@9aad2ee0908977dc"`
+		X0 *int
+		X1 *int
+		X2 func() bool
+		X3 bool
+		X4 bool
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture@3426ebc46755e597"`
+		X0  int
+		X1  int
+		X2  bool
+		X3  bool
+		X4  bool
+		X5  bool
+		X6  bool
+		X7  bool
+		X8  bool
+		X9  bool
+		X10 bool
+		X11 bool
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+		_types.SerializeField(s, &x.X5)
+		_types.SerializeField(s, &x.X6)
+		_types.SerializeField(s, &x.X7)
+		_types.SerializeField(s, &x.X8)
+		_types.SerializeField(s, &x.X9)
+		_types.SerializeField(s, &x.X10)
+		_types.SerializeField(s, &x.X11)
+	}, func(d *_types.Deserializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture@3426ebc46755e597"`
+		X0  int
+		X1  int
+		X2  bool
+		X3  bool
+		X4  bool
+		X5  bool
+		X6  bool
+		X7  bool
+		X8  bool
+		X9  bool
+		X10 bool
+		X11 bool
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+		_types.DeserializeField(d, &x.X5)
+		_types.DeserializeField(d, &x.X6)
+		_types.DeserializeField(d, &x.X7)
+		_types.DeserializeField(d, &x.X8)
+		_types.DeserializeField(d, &x.X9)
+		_types.DeserializeField(d, &x.X10)
+		_types.DeserializeField(d, &x.X11)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture@53399881f6748171"`
+		X0  int8
+		X1  int16
+		X2  int32
+		X3  int64
+		X4  uint8
+		X5  uint16
+		X6  uint32
+		X7  uint64
+		X8  uintptr
+		X9  func() int
+		X10 int
+		X11 func() bool
+		X12 bool
+		X13 bool
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+		_types.SerializeField(s, &x.X5)
+		_types.SerializeField(s, &x.X6)
+		_types.SerializeField(s, &x.X7)
+		_types.SerializeField(s, &x.X8)
+		_types.SerializeField(s, &x.X9)
+		_types.SerializeField(s, &x.X10)
+		_types.SerializeField(s, &x.X11)
+		_types.SerializeField(s, &x.X12)
+		_types.SerializeField(s, &x.X13)
+	}, func(d *_types.Deserializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture@53399881f6748171"`
+		X0  int8
+		X1  int16
+		X2  int32
+		X3  int64
+		X4  uint8
+		X5  uint16
+		X6  uint32
+		X7  uint64
+		X8  uintptr
+		X9  func() int
+		X10 int
+		X11 func() bool
+		X12 bool
+		X13 bool
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+		_types.DeserializeField(d, &x.X5)
+		_types.DeserializeField(d, &x.X6)
+		_types.DeserializeField(d, &x.X7)
+		_types.DeserializeField(d, &x.X8)
+		_types.DeserializeField(d, &x.X9)
+		_types.DeserializeField(d, &x.X10)
+		_types.DeserializeField(d, &x.X11)
+		_types.DeserializeField(d, &x.X12)
+		_types.DeserializeField(d, &x.X13)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10Heterogenous@70be744490c3d6da"`
+		X0  int8
+		X1  int16
+		X2  int32
+		X3  int64
+		X4  uint8
+		X5  uint16
+		X6  uint32
+		X7  uint64
+		X8  uintptr
+		X9  int
+		X10 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+		_types.SerializeField(s, &x.X5)
+		_types.SerializeField(s, &x.X6)
+		_types.SerializeField(s, &x.X7)
+		_types.SerializeField(s, &x.X8)
+		_types.SerializeField(s, &x.X9)
+		_types.SerializeField(s, &x.X10)
+	}, func(d *_types.Deserializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Range10Heterogenous@70be744490c3d6da"`
+		X0  int8
+		X1  int16
+		X2  int32
+		X3  int64
+		X4  uint8
+		X5  uint16
+		X6  uint32
+		X7  uint64
+		X8  uintptr
+		X9  int
+		X10 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+		_types.DeserializeField(d, &x.X5)
+		_types.DeserializeField(d, &x.X6)
+		_types.DeserializeField(d, &x.X7)
+		_types.DeserializeField(d, &x.X8)
+		_types.DeserializeField(d, &x.X9)
+		_types.DeserializeField(d, &x.X10)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Select@179080c426ded742"`
+		X0  int
+		X1  int
+		X2  int
+		X3  bool
+		X4  int
+		X5  int
+		X6  <-chan time.Time
+		X7  <-chan time.Time
+		X8  int
+		X9  bool
+		X10 bool
+		X11 int
+		X12 <-chan time.Time
+		X13 int
+		X14 bool
+		X15 int
+		X16 <-chan time.Time
+		X17 int
+		X18 bool
+		X19 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+		_types.SerializeField(s, &x.X5)
+		_types.SerializeField(s, &x.X6)
+		_types.SerializeField(s, &x.X7)
+		_types.SerializeField(s, &x.X8)
+		_types.SerializeField(s, &x.X9)
+		_types.SerializeField(s, &x.X10)
+		_types.SerializeField(s, &x.X11)
+		_types.SerializeField(s, &x.X12)
+		_types.SerializeField(s, &x.X13)
+		_types.SerializeField(s, &x.X14)
+		_types.SerializeField(s, &x.X15)
+		_types.SerializeField(s, &x.X16)
+		_types.SerializeField(s, &x.X17)
+		_types.SerializeField(s, &x.X18)
+		_types.SerializeField(s, &x.X19)
+	}, func(d *_types.Deserializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.Select@179080c426ded742"`
+		X0  int
+		X1  int
+		X2  int
+		X3  bool
+		X4  int
+		X5  int
+		X6  <-chan time.Time
+		X7  <-chan time.Time
+		X8  int
+		X9  bool
+		X10 bool
+		X11 int
+		X12 <-chan time.Time
+		X13 int
+		X14 bool
+		X15 int
+		X16 <-chan time.Time
+		X17 int
+		X18 bool
+		X19 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+		_types.DeserializeField(d, &x.X5)
+		_types.DeserializeField(d, &x.X6)
+		_types.DeserializeField(d, &x.X7)
+		_types.DeserializeField(d, &x.X8)
+		_types.DeserializeField(d, &x.X9)
+		_types.DeserializeField(d, &x.X10)
+		_types.DeserializeField(d, &x.X11)
+		_types.DeserializeField(d, &x.X12)
+		_types.DeserializeField(d, &x.X13)
+		_types.DeserializeField(d, &x.X14)
+		_types.DeserializeField(d, &x.X15)
+		_types.DeserializeField(d, &x.X16)
+		_types.DeserializeField(d, &x.X17)
+		_types.DeserializeField(d, &x.X18)
+		_types.DeserializeField(d, &x.X19)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingExpressionDesugaring@886ef314e15a6e12"`
+		X0  int
+		X1  int
+		X2  int
+		X3  int
+		X4  bool
+		X5  int
+		X6  int
+		X7  int
+		X8  int
+		X9  int
+		X10 bool
+		X11 int
+		X12 int
+		X13 int
+		X14 int
+		X15 int
+		X16 bool
+		X17 int
+		X18 int
+		X19 int
+		X20 int
+		X21 bool
+		X22 bool
+		X23 int
+		X24 int
+		X25 int
+		X26 int
+		X27 bool
+		X28 int
+		X29 int
+		X30 bool
+		X31 int
+		X32 int
+		X33 int
+		X34 bool
+		X35 int
+		X36 int
+		X37 int
+		X38 bool
+		X39 int
+		X40 int
+		X41 any
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+		_types.SerializeField(s, &x.X5)
+		_types.SerializeField(s, &x.X6)
+		_types.SerializeField(s, &x.X7)
+		_types.SerializeField(s, &x.X8)
+		_types.SerializeField(s, &x.X9)
+		_types.SerializeField(s, &x.X10)
+		_types.SerializeField(s, &x.X11)
+		_types.SerializeField(s, &x.X12)
+		_types.SerializeField(s, &x.X13)
+		_types.SerializeField(s, &x.X14)
+		_types.SerializeField(s, &x.X15)
+		_types.SerializeField(s, &x.X16)
+		_types.SerializeField(s, &x.X17)
+		_types.SerializeField(s, &x.X18)
+		_types.SerializeField(s, &x.X19)
+		_types.SerializeField(s, &x.X20)
+		_types.SerializeField(s, &x.X21)
+		_types.SerializeField(s, &x.X22)
+		_types.SerializeField(s, &x.X23)
+		_types.SerializeField(s, &x.X24)
+		_types.SerializeField(s, &x.X25)
+		_types.SerializeField(s, &x.X26)
+		_types.SerializeField(s, &x.X27)
+		_types.SerializeField(s, &x.X28)
+		_types.SerializeField(s, &x.X29)
+		_types.SerializeField(s, &x.X30)
+		_types.SerializeField(s, &x.X31)
+		_types.SerializeField(s, &x.X32)
+		_types.SerializeField(s, &x.X33)
+		_types.SerializeField(s, &x.X34)
+		_types.SerializeField(s, &x.X35)
+		_types.SerializeField(s, &x.X36)
+		_types.SerializeField(s, &x.X37)
+		_types.SerializeField(s, &x.X38)
+		_types.SerializeField(s, &x.X39)
+		_types.SerializeField(s, &x.X40)
+		_types.SerializeField(s, &x.X41)
+	}, func(d *_types.Deserializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingExpressionDesugaring@886ef314e15a6e12"`
+		X0  int
+		X1  int
+		X2  int
+		X3  int
+		X4  bool
+		X5  int
+		X6  int
+		X7  int
+		X8  int
+		X9  int
+		X10 bool
+		X11 int
+		X12 int
+		X13 int
+		X14 int
+		X15 int
+		X16 bool
+		X17 int
+		X18 int
+		X19 int
+		X20 int
+		X21 bool
+		X22 bool
+		X23 int
+		X24 int
+		X25 int
+		X26 int
+		X27 bool
+		X28 int
+		X29 int
+		X30 bool
+		X31 int
+		X32 int
+		X33 int
+		X34 bool
+		X35 int
+		X36 int
+		X37 int
+		X38 bool
+		X39 int
+		X40 int
+		X41 any
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+		_types.DeserializeField(d, &x.X5)
+		_types.DeserializeField(d, &x.X6)
+		_types.DeserializeField(d, &x.X7)
+		_types.DeserializeField(d, &x.X8)
+		_types.DeserializeField(d, &x.X9)
+		_types.DeserializeField(d, &x.X10)
+		_types.DeserializeField(d, &x.X11)
+		_types.DeserializeField(d, &x.X12)
+		_types.DeserializeField(d, &x.X13)
+		_types.DeserializeField(d, &x.X14)
+		_types.DeserializeField(d, &x.X15)
+		_types.DeserializeField(d, &x.X16)
+		_types.DeserializeField(d, &x.X17)
+		_types.DeserializeField(d, &x.X18)
+		_types.DeserializeField(d, &x.X19)
+		_types.DeserializeField(d, &x.X20)
+		_types.DeserializeField(d, &x.X21)
+		_types.DeserializeField(d, &x.X22)
+		_types.DeserializeField(d, &x.X23)
+		_types.DeserializeField(d, &x.X24)
+		_types.DeserializeField(d, &x.X25)
+		_types.DeserializeField(d, &x.X26)
+		_types.DeserializeField(d, &x.X27)
+		_types.DeserializeField(d, &x.X28)
+		_types.DeserializeField(d, &x.X29)
+		_types.DeserializeField(d, &x.X30)
+		_types.DeserializeField(d, &x.X31)
+		_types.DeserializeField(d, &x.X32)
+		_types.DeserializeField(d, &x.X33)
+		_types.DeserializeField(d, &x.X34)
+		_types.DeserializeField(d, &x.X35)
+		_types.DeserializeField(d, &x.X36)
+		_types.DeserializeField(d, &x.X37)
+		_types.DeserializeField(d, &x.X38)
+		_types.DeserializeField(d, &x.X39)
+		_types.DeserializeField(d, &x.X40)
+		_types.DeserializeField(d, &x.X41)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations@3ae61304e1366d0f"`
+		X0 int64
+		X1 int
+		X2 time.Duration
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations@3ae61304e1366d0f"`
+		X0 int64
+		X1 int
+		X2 time.Duration
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations@3f9d8b15962ed143"`
+		X0 *time.Duration
+		X1 time.Duration
+		X2 func()
+		X3 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations@3f9d8b15962ed143"`
+		X0 *time.Duration
+		X1 time.Duration
+		X2 func()
+		X3 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldAndDeferAssign@e17c0ca722b4aa32"`
+		X0 *int
+		X1 int
+		X2 int
+		X3 []func()
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.YieldAndDeferAssign@e17c0ca722b4aa32"`
+		X0 *int
+		X1 int
+		X2 int
+		X3 []func()
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeYieldAndDeferAssign@13e68f62d35f31e9"`
+		X0 int
+		X1 int
+		X2 *int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.RangeYieldAndDeferAssign@13e68f62d35f31e9"`
+		X0 int
+		X1 int
+		X2 *int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.MethodGeneratorState).MethodGenerator@2be97cd35ecee81b"`
+		X0 *MethodGeneratorState
+		X1 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.MethodGeneratorState).MethodGenerator@2be97cd35ecee81b"`
+		X0 *MethodGeneratorState
+		X1 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.VarArgs@9525cd9dd0377507"`
+		X0 int
+		X1 []int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.VarArgs@9525cd9dd0377507"`
+		X0 int
+		X1 []int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.varArgs@5d0430e3ba77537f"`
+		X0 []int
+		X1 []int
+		X2 int
+		X3 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.varArgs@5d0430e3ba77537f"`
+		X0 []int
+		X1 []int
+		X2 int
+		X3 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.Box).YieldAndInc@bcf6e5dfbabedd75"`
+		X0 *Box
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"(*github.com/dispatchrun/coroutine/compiler/testdata.Box).YieldAndInc@bcf6e5dfbabedd75"`
+		X0 *Box
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int
+		X0 *Box
+		X1 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int
+		X0 *Box
+		X1 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.StructClosure@74f284527bcdfdd9"`
+		X0 int
+		X1 Box
+		X2 func(int)
+		X3 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.StructClosure@74f284527bcdfdd9"`
+		X0 int
+		X1 Box
+		X2 func(int)
+		X3 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.StructGenericClosure@c222701158173da6"`
+		X0 int
+		X1 GenericBox[int]
+		X2 func(int)
+		X3 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.StructGenericClosure@c222701158173da6"`
+		X0 int
+		X1 GenericBox[int]
+		X2 func(int)
+		X3 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.IndirectClosure@5aaff2d6b996df9e"`
+		X0 int
+		X1 *Box
+		X2 func()
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.IndirectClosure@5aaff2d6b996df9e"`
+		X0 int
+		X1 *Box
+		X2 func()
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.indirectClosure@2af7e513f568f296"`
+		X0 interface{ YieldAndInc() }
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.indirectClosure@2af7e513f568f296"`
+		X0 interface{ YieldAndInc() }
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures@106e0b93f4be2405"`
+		X0  int
+		X1  []func() int
+		X2  int
+		X3  *int
+		X4  []*int
+		X5  int
+		X6  int
+		X7  *int
+		X8  *int
+		X9  int
+		X10 *int
+		X11 func()
+		X12 []func() int
+		X13 int
+		X14 func() int
+		X15 int
+		X16 []*int
+		X17 int
+		X18 *int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+		_types.SerializeField(s, &x.X5)
+		_types.SerializeField(s, &x.X6)
+		_types.SerializeField(s, &x.X7)
+		_types.SerializeField(s, &x.X8)
+		_types.SerializeField(s, &x.X9)
+		_types.SerializeField(s, &x.X10)
+		_types.SerializeField(s, &x.X11)
+		_types.SerializeField(s, &x.X12)
+		_types.SerializeField(s, &x.X13)
+		_types.SerializeField(s, &x.X14)
+		_types.SerializeField(s, &x.X15)
+		_types.SerializeField(s, &x.X16)
+		_types.SerializeField(s, &x.X17)
+		_types.SerializeField(s, &x.X18)
+	}, func(d *_types.Deserializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures@106e0b93f4be2405"`
+		X0  int
+		X1  []func() int
+		X2  int
+		X3  *int
+		X4  []*int
+		X5  int
+		X6  int
+		X7  *int
+		X8  *int
+		X9  int
+		X10 *int
+		X11 func()
+		X12 []func() int
+		X13 int
+		X14 func() int
+		X15 int
+		X16 []*int
+		X17 int
+		X18 *int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+		_types.DeserializeField(d, &x.X5)
+		_types.DeserializeField(d, &x.X6)
+		_types.DeserializeField(d, &x.X7)
+		_types.DeserializeField(d, &x.X8)
+		_types.DeserializeField(d, &x.X9)
+		_types.DeserializeField(d, &x.X10)
+		_types.DeserializeField(d, &x.X11)
+		_types.DeserializeField(d, &x.X12)
+		_types.DeserializeField(d, &x.X13)
+		_types.DeserializeField(d, &x.X14)
+		_types.DeserializeField(d, &x.X15)
+		_types.DeserializeField(d, &x.X16)
+		_types.DeserializeField(d, &x.X17)
+		_types.DeserializeField(d, &x.X18)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ReflectType@18570e44eb10a7fb"`
+		X0 []reflect.Type
+		X1 []reflect.Type
+		X2 int
+		X3 reflect.Type
+		X4 reflect.Value
+		X5 reflect.Value
+		X6 bool
+		X7 bool
+		X8 uint64
+		X9 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+		_types.SerializeField(s, &x.X5)
+		_types.SerializeField(s, &x.X6)
+		_types.SerializeField(s, &x.X7)
+		_types.SerializeField(s, &x.X8)
+		_types.SerializeField(s, &x.X9)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ReflectType@18570e44eb10a7fb"`
+		X0 []reflect.Type
+		X1 []reflect.Type
+		X2 int
+		X3 reflect.Type
+		X4 reflect.Value
+		X5 reflect.Value
+		X6 bool
+		X7 bool
+		X8 uint64
+		X9 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+		_types.DeserializeField(d, &x.X5)
+		_types.DeserializeField(d, &x.X6)
+		_types.DeserializeField(d, &x.X7)
+		_types.DeserializeField(d, &x.X8)
+		_types.DeserializeField(d, &x.X9)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int
+		X0 []int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int
+		X0 []int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.EllipsisClosure@8846f20c06b67cb0"`
+		X0 int
+		X1 []int
+		X2 func()
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.EllipsisClosure@8846f20c06b67cb0"`
+		X0 int
+		X1 []int
+		X2 func()
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.InterfaceEmbedded@6a80e3fbb4860d00"`
+		X0 interface {
+			outerInterface
+		}
+		X1 int
+		X2 int
+		X3 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.InterfaceEmbedded@6a80e3fbb4860d00"`
+		X0 interface {
+			outerInterface
+		}
+		X1 int
+		X2 int
+		X3 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ClosureInSeparatePackage@daf531247543350e"`
+		X0 int
+		X1 func(int) int
+		X2 int
+		X3 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ClosureInSeparatePackage@daf531247543350e"`
+		X0 int
+		X1 func(int) int
+		X2 int
+		X3 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.GenericStructClosure@8a6b8ca06f5cae58"`
+		X0 int
+		X1 *GenericAdder[AdderImpl]
+		X2 int
+		X3 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.GenericStructClosure@8a6b8ca06f5cae58"`
+		X0 int
+		X1 *GenericAdder[AdderImpl]
+		X2 int
+		X3 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.JSONRoundTrip@4fd72c93eb3a3003"`
+		X0 int
+		X1 []byte
+		X2 string
+		X3 bool
+		X4 error
+		X5 struct {
+			N int "json:\"n\""
+		}
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+		_types.SerializeField(s, &x.X5)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.JSONRoundTrip@4fd72c93eb3a3003"`
+		X0 int
+		X1 []byte
+		X2 string
+		X3 bool
+		X4 error
+		X5 struct {
+			N int "json:\"n\""
+		}
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+		_types.DeserializeField(d, &x.X5)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.GenericSlice@c867b4f182443bcc"`
+		X0 int
+		X1 []int
+		X2 []int
+		X3 int
+		X4 int
+		X5 *Cloner[[]int, int]
+		X6 []int
+		X7 []int
+		X8 int
+		X9 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+		_types.SerializeField(s, &x.X5)
+		_types.SerializeField(s, &x.X6)
+		_types.SerializeField(s, &x.X7)
+		_types.SerializeField(s, &x.X8)
+		_types.SerializeField(s, &x.X9)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.GenericSlice@c867b4f182443bcc"`
+		X0 int
+		X1 []int
+		X2 []int
+		X3 int
+		X4 int
+		X5 *Cloner[[]int, int]
+		X6 []int
+		X7 []int
+		X8 int
+		X9 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+		_types.DeserializeField(d, &x.X5)
+		_types.DeserializeField(d, &x.X6)
+		_types.DeserializeField(d, &x.X7)
+		_types.DeserializeField(d, &x.X8)
+		_types.DeserializeField(d, &x.X9)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.NoYieldDirective@543b83cfa14e5fdf"`
+		X0 int
+		X1 int
+		X2 []Notifier
+		X3 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.NoYieldDirective@543b83cfa14e5fdf"`
+		X0 int
+		X1 int
+		X2 []Notifier
+		X3 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ChannelBuffer@54a83952369eec41"`
+		X0 int
+		X1 chan int
+		X2 int
+		X3 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.ChannelBuffer@54a83952369eec41"`
+		X0 int
+		X1 chan int
+		X2 int
+		X3 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.TransientField@d4ac97491bc97a2f"`
+		X0 int
+		X1 transientCache
+		X2 int
+		X3 int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+	}, func(d *_types.Deserializer, x *struct {
+		IP int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.TransientField@d4ac97491bc97a2f"`
+		X0 int
+		X1 transientCache
+		X2 int
+		X3 int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *AdderImpl) {
+		_types.SerializeField(s, &x.base)
+		_types.SerializeField(s, &x.mul)
+	}, func(d *_types.Deserializer, x *AdderImpl) {
+		_types.DeserializeField(d, &x.base)
+		_types.DeserializeField(d, &x.mul)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *Box) {
+		_types.SerializeField(s, &x.x)
+	}, func(d *_types.Deserializer, x *Box) {
+		_types.DeserializeField(d, &x.x)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *MethodGeneratorState) {
+		_types.SerializeField(s, &x.i)
+	}, func(d *_types.Deserializer, x *MethodGeneratorState) {
+		_types.DeserializeField(d, &x.i)
+	})
+	_types.RegisterCodec(func(s *_types.Serializer, x *transientCache) {
+	}, func(d *_types.Deserializer, x *transientCache) {
+	})
+	coroutine.RegisterVersion("(*github.com/dispatchrun/coroutine/compiler/testdata.Box).Closure", "167793d5b527ddc4")
+	coroutine.RegisterVersion("(*github.com/dispatchrun/coroutine/compiler/testdata.Box).YieldAndInc", "bcf6e5dfbabedd75")
+	coroutine.RegisterVersion("(*github.com/dispatchrun/coroutine/compiler/testdata.GenericBox[T]).Closure", "ee338ddca2e7b38c")
+	coroutine.RegisterVersion("(*github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericStruct[T]).Closure", "9abb1b77509c5fd6")
+	coroutine.RegisterVersion("(*github.com/dispatchrun/coroutine/compiler/testdata.MethodGeneratorState).MethodGenerator", "2be97cd35ecee81b")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.ChannelBuffer", "54a83952369eec41")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.ClosureInSeparatePackage", "daf531247543350e")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.EllipsisClosure", "8846f20c06b67cb0")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.EvenSquareGenerator", "b0f75a8c0071d093")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.FizzBuzzIfGenerator", "f72a8e002a46241c")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.FizzBuzzSwitchGenerator", "b600f193aa9c22ca")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.GenericSlice", "c867b4f182443bcc")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.GenericStructClosure", "8a6b8ca06f5cae58")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericClosure", "732a63fa0e983c46")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.IdentityGenericStructClosureInt", "b36c92788dd15dea")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.IndirectClosure", "5aaff2d6b996df9e")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.InterfaceEmbedded", "6a80e3fbb4860d00")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.JSONRoundTrip", "4fd72c93eb3a3003")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.LoopBreakAndContinue", "4f6231e9ad0c4daa")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.LoopVarClosures", "106e0b93f4be2405")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.MakeEllipsisClosure", "a159dc53a4948501")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.NestedLoops", "f14fdf4dd3902d03")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.NoYieldDirective", "543b83cfa14e5fdf")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.Range", "40c5ac9c364c8d40")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers", "313a80ad44866651")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingPointers", "9aad2ee0908977dc")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues", "11f7b3a23c59b515")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureCapturingValues", "75f89957a2e7d54a")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture", "3426ebc46755e597")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.Range10ClosureHeterogenousCapture", "53399881f6748171")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.Range10Heterogenous", "70be744490c3d6da")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.RangeArrayIndexValueGenerator", "8a5fd5286ef4fb01")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.RangeOverInt", "adf4d48d38f24b72")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.RangeOverMaps", "f783f24fa9201e8f")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.RangeReverseClosureCaptureByValue", "f0643c5b617867a9")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.RangeSliceIndexGenerator", "d5e40a2939c39085")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.RangeTripleFuncValue", "30a2b825a9a2e158")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.RangeYieldAndDeferAssign", "13e68f62d35f31e9")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.ReflectType", "18570e44eb10a7fb")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.ReturnNamedValue", "01f171c2439ae3fc")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.Select", "179080c426ded742")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.Shadowing", "5d6f70650829da51")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.SquareGenerator", "c655cb4854991333")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.SquareGeneratorTwice", "e842ebcb555b67e6")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.SquareGeneratorTwiceLoop", "03d45dc05ad3ccd2")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.StructClosure", "74f284527bcdfdd9")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.StructGenericClosure", "c222701158173da6")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.TransientField", "d4ac97491bc97a2f")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.TypeSwitchingGenerator", "6123141c1f6af071")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.VarArgs", "9525cd9dd0377507")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.YieldAndDeferAssign", "e17c0ca722b4aa32")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations", "3ae61304e1366d0f")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.YieldingDurations", "3f9d8b15962ed143")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.YieldingExpressionDesugaring", "886ef314e15a6e12")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.YieldsDirective", "b38d21ec721eac3b")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.a", "4952375a366b8d89")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.b", "d8fc9b93c2a1a2a9")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.forcedYield", "fb0c79caa0f3c37a")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.indirectClosure", "2af7e513f568f296")
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.varArgs", "5d0430e3ba77537f")
 }
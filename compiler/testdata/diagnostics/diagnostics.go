@@ -0,0 +1,18 @@
+package diagnostics
+
+import (
+	"github.com/dispatchrun/coroutine"
+	"github.com/dispatchrun/coroutine/compiler/testdata/diagnostics/sub"
+)
+
+func Goto() {
+	coroutine.Yield[int, any](0)
+	goto end
+end:
+}
+
+func Go() {
+	go func() {}()
+	coroutine.Yield[int, any](sub.Fallthrough(1))
+	go func() {}()
+}
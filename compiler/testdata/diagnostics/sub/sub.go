@@ -0,0 +1,13 @@
+package sub
+
+import "github.com/dispatchrun/coroutine"
+
+func Fallthrough(n int) int {
+	switch n {
+	case 0:
+		coroutine.Yield[int, any](n)
+		fallthrough
+	default:
+		return n
+	}
+}
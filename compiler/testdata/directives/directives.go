@@ -0,0 +1,16 @@
+package directives
+
+import "github.com/dispatchrun/coroutine"
+
+func Yield() {
+	coroutine.Yield[int, any](0)
+}
+
+//coroutine:yields
+func First() {}
+
+//coroutine:yields
+func Second() {}
+
+//coroutine:yields
+func Third() {}
@@ -0,0 +1,25 @@
+package explain
+
+import "github.com/dispatchrun/coroutine"
+
+type Yielder interface {
+	Yield(int)
+}
+
+type yielder struct{}
+
+func (yielder) Yield(v int) {
+	coroutine.Yield[int, any](v)
+}
+
+func Run(y Yielder) {
+	y.Yield(1)
+}
+
+func Main() {
+	Run(yielder{})
+}
+
+func NoYield() int {
+	return 1
+}
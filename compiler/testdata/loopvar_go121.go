@@ -0,0 +1,32 @@
+//go:build go1.21 && !durable
+
+package testdata
+
+import "github.com/dispatchrun/coroutine"
+
+// Before Go 1.22, the variables declared by for clauses and range clauses
+// are shared by all the iterations of the loop. The build constraint sets
+// the Go version of the file.
+
+func LoopVarSharedClosures(n int) {
+	var fs []func() int
+	for i := 0; i < n; i++ {
+		coroutine.Yield[int, any](i)
+		fs = append(fs, func() int { return i })
+	}
+
+	var ps []*int
+	for j, v := range make([]int, n) {
+		x := j * 10
+		ps = append(ps, &x, &v)
+		fs = append(fs, func() int { return j })
+		coroutine.Yield[int, any](x)
+	}
+
+	for _, f := range fs {
+		coroutine.Yield[int, any](f())
+	}
+	for _, p := range ps {
+		coroutine.Yield[int, any](*p)
+	}
+}
@@ -0,0 +1,359 @@
+//go:build go1.21 && durable
+
+package testdata
+
+import coroutine "github.com/dispatchrun/coroutine"
+import _types "github.com/dispatchrun/coroutine/types"
+
+//go:noinline
+func LoopVarSharedClosures(_fn0 int) {
+	_c := coroutine.LoadContext[int, any]()
+//line loopvar_go121.go:11
+	var _f0 *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures@ae9863b2dd7a1495"`
+		X0  int
+		X1  []func() int
+		X2  int
+		X3  []*int
+		X4  []int
+		X5  int
+		X6  int
+		X7  *int
+		X8  []func() int
+		X9  int
+		X10 func() int
+		X11 int
+		X12 []*int
+		X13 int
+		X14 *int
+	} = coroutine.Push[struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures@ae9863b2dd7a1495"`
+		X0  int
+		X1  []func() int
+		X2  int
+		X3  []*int
+		X4  []int
+		X5  int
+		X6  int
+		X7  *int
+		X8  []func() int
+		X9  int
+		X10 func() int
+		X11 int
+		X12 []*int
+		X13 int
+		X14 *int
+	}](&_c.Stack)
+//line loopvar_go121_durable.go:48
+	var _o0 int
+	if _f0.IP == 0 {
+//line loopvar_go121.go:11
+		*_f0 = struct {
+			IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures@ae9863b2dd7a1495"`
+			X0  int
+			X1  []func() int
+			X2  int
+			X3  []*int
+			X4  []int
+			X5  int
+			X6  int
+			X7  *int
+			X8  []func() int
+			X9  int
+			X10 func() int
+			X11 int
+			X12 []*int
+			X13 int
+			X14 *int
+		}{X0: _fn0}
+	}
+//line loopvar_go121_durable.go:71
+	defer func() {
+		if !_c.Unwinding() {
+			coroutine.Pop(&_c.Stack)
+		}
+	}()
+	switch {
+	case _f0.IP < 2:
+		_f0.IP = 2
+		fallthrough
+	case _f0.IP < 5:
+		switch {
+		case _f0.IP < 3:
+//line loopvar_go121.go:13
+			_f0.X2 = 0
+//line loopvar_go121_durable.go:86
+			_f0.IP = 3
+			fallthrough
+		case _f0.IP < 5:
+			for ; _f0.X2 < _f0.X0; _f0.X2, _f0.IP = _f0.X2+1, 3 {
+				switch {
+				case _f0.IP < 4:
+//line loopvar_go121.go:14
+					coroutine.Yield[int, any](_f0.X2)
+//line loopvar_go121_durable.go:95
+					_f0.IP = 4
+					fallthrough
+				case _f0.IP < 5:
+//line loopvar_go121.go:15
+					_f0.X1 = append(_f0.X1, func() int { return _f0.X2 })
+				}
+			}
+		}
+//line loopvar_go121_durable.go:104
+		_f0.IP = 5
+		fallthrough
+	case _f0.IP < 6:
+		_f0.IP = 6
+		fallthrough
+	case _f0.IP < 15:
+		switch {
+		case _f0.IP < 7:
+//line loopvar_go121.go:19
+			_f0.X4 = make([]int, _f0.X0)
+//line loopvar_go121_durable.go:115
+			_f0.IP = 7
+			fallthrough
+		case _f0.IP < 15:
+			switch {
+			case _f0.IP < 8:
+				_f0.X5 = 0
+				_f0.IP = 8
+				fallthrough
+			case _f0.IP < 15:
+				for ; _f0.X5 < len(_f0.X4); _f0.X5, _f0.IP = _f0.X5+1, 8 {
+					switch {
+					case _f0.IP < 9:
+//line loopvar_go121.go:19
+						_o0 = _f0.X4[_f0.X5]
+//line loopvar_go121_durable.go:130
+						_f0.IP = 9
+						fallthrough
+					case _f0.IP < 10:
+//line loopvar_go121.go:19
+						_f0.X6 = _f0.X5
+//line loopvar_go121_durable.go:136
+						_f0.IP = 10
+						fallthrough
+					case _f0.IP < 11:
+						_f0.X7 = new(int)
+						_f0.IP = 11
+						fallthrough
+					case _f0.IP < 12:
+//line loopvar_go121.go:20
+						*_f0.X7 = _f0.X6 * 10
+//line loopvar_go121_durable.go:146
+						_f0.IP = 12
+						fallthrough
+					case _f0.IP < 13:
+//line loopvar_go121.go:21
+						_f0.X3 = append(_f0.X3, &*_f0.X7, &_o0)
+//line loopvar_go121_durable.go:152
+						_f0.IP = 13
+						fallthrough
+					case _f0.IP < 14:
+//line loopvar_go121.go:22
+						_f0.X1 = append(_f0.X1, func() int { return _f0.X6 })
+//line loopvar_go121_durable.go:158
+						_f0.IP = 14
+						fallthrough
+					case _f0.IP < 15:
+//line loopvar_go121.go:23
+						coroutine.Yield[int, any](*_f0.X7)
+					}
+				}
+			}
+		}
+//line loopvar_go121_durable.go:168
+		_f0.IP = 15
+		fallthrough
+	case _f0.IP < 20:
+		switch {
+		case _f0.IP < 16:
+//line loopvar_go121.go:26
+			_f0.X8 = _f0.X1
+//line loopvar_go121_durable.go:176
+			_f0.IP = 16
+			fallthrough
+		case _f0.IP < 20:
+			switch {
+			case _f0.IP < 17:
+				_f0.X9 = 0
+				_f0.IP = 17
+				fallthrough
+			case _f0.IP < 20:
+				for ; _f0.X9 < len(_f0.X8); _f0.X9, _f0.IP = _f0.X9+1, 17 {
+					switch {
+					case _f0.IP < 18:
+//line loopvar_go121.go:26
+						_f0.X10 = _f0.X8[_f0.X9]
+//line loopvar_go121_durable.go:191
+						_f0.IP = 18
+						fallthrough
+					case _f0.IP < 19:
+//line loopvar_go121.go:27
+						_f0.X11 = _f0.X10()
+//line loopvar_go121_durable.go:197
+						_f0.IP = 19
+						fallthrough
+					case _f0.IP < 20:
+//line loopvar_go121.go:27
+						coroutine.Yield[int, any](_f0.X11)
+					}
+				}
+			}
+		}
+//line loopvar_go121_durable.go:207
+		_f0.IP = 20
+		fallthrough
+	case _f0.IP < 24:
+		switch {
+		case _f0.IP < 21:
+//line loopvar_go121.go:29
+			_f0.X12 = _f0.X3
+//line loopvar_go121_durable.go:215
+			_f0.IP = 21
+			fallthrough
+		case _f0.IP < 24:
+			switch {
+			case _f0.IP < 22:
+				_f0.X13 = 0
+				_f0.IP = 22
+				fallthrough
+			case _f0.IP < 24:
+				for ; _f0.X13 < len(_f0.X12); _f0.X13, _f0.IP = _f0.X13+1, 22 {
+					switch {
+					case _f0.IP < 23:
+//line loopvar_go121.go:29
+						_f0.X14 = _f0.X12[_f0.X13]
+//line loopvar_go121_durable.go:230
+						_f0.IP = 23
+						fallthrough
+					case _f0.IP < 24:
+//line loopvar_go121.go:30
+
+						coroutine.Yield[int, any](*_f0.X14)
+					}
+				}
+			}
+		}
+	}
+//line loopvar_go121_durable.go:242
+}
+func init() {
+	_types.RegisterFunc[func(_fn0 int)]("github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures")
+	_types.RegisterClosure[func() (_ int), struct {
+		F  uintptr
+		X0 *struct {
+			IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures@ae9863b2dd7a1495"`
+			X0  int
+			X1  []func() int
+			X2  int
+			X3  []*int
+			X4  []int
+			X5  int
+			X6  int
+			X7  *int
+			X8  []func() int
+			X9  int
+			X10 func() int
+			X11 int
+			X12 []*int
+			X13 int
+			X14 *int
+		}
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures.func2")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures.func2", "github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures.func-cdd33a1b4e45f3dc")
+	_types.RegisterClosure[func() (_ int), struct {
+		F  uintptr
+		X0 *struct {
+			IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures@ae9863b2dd7a1495"`
+			X0  int
+			X1  []func() int
+			X2  int
+			X3  []*int
+			X4  []int
+			X5  int
+			X6  int
+			X7  *int
+			X8  []func() int
+			X9  int
+			X10 func() int
+			X11 int
+			X12 []*int
+			X13 int
+			X14 *int
+		}
+	}]("github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures.func3")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures.func3", "github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures.func-cc5335cdc55b9f1c")
+	_types.RegisterCodec(func(s *_types.Serializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures@ae9863b2dd7a1495"`
+		X0  int
+		X1  []func() int
+		X2  int
+		X3  []*int
+		X4  []int
+		X5  int
+		X6  int
+		X7  *int
+		X8  []func() int
+		X9  int
+		X10 func() int
+		X11 int
+		X12 []*int
+		X13 int
+		X14 *int
+	}) {
+		_types.SerializeField(s, &x.IP)
+		_types.SerializeField(s, &x.X0)
+		_types.SerializeField(s, &x.X1)
+		_types.SerializeField(s, &x.X2)
+		_types.SerializeField(s, &x.X3)
+		_types.SerializeField(s, &x.X4)
+		_types.SerializeField(s, &x.X5)
+		_types.SerializeField(s, &x.X6)
+		_types.SerializeField(s, &x.X7)
+		_types.SerializeField(s, &x.X8)
+		_types.SerializeField(s, &x.X9)
+		_types.SerializeField(s, &x.X10)
+		_types.SerializeField(s, &x.X11)
+		_types.SerializeField(s, &x.X12)
+		_types.SerializeField(s, &x.X13)
+		_types.SerializeField(s, &x.X14)
+	}, func(d *_types.Deserializer, x *struct {
+		IP  int `frame:"github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures@ae9863b2dd7a1495"`
+		X0  int
+		X1  []func() int
+		X2  int
+		X3  []*int
+		X4  []int
+		X5  int
+		X6  int
+		X7  *int
+		X8  []func() int
+		X9  int
+		X10 func() int
+		X11 int
+		X12 []*int
+		X13 int
+		X14 *int
+	}) {
+		_types.DeserializeField(d, &x.IP)
+		_types.DeserializeField(d, &x.X0)
+		_types.DeserializeField(d, &x.X1)
+		_types.DeserializeField(d, &x.X2)
+		_types.DeserializeField(d, &x.X3)
+		_types.DeserializeField(d, &x.X4)
+		_types.DeserializeField(d, &x.X5)
+		_types.DeserializeField(d, &x.X6)
+		_types.DeserializeField(d, &x.X7)
+		_types.DeserializeField(d, &x.X8)
+		_types.DeserializeField(d, &x.X9)
+		_types.DeserializeField(d, &x.X10)
+		_types.DeserializeField(d, &x.X11)
+		_types.DeserializeField(d, &x.X12)
+		_types.DeserializeField(d, &x.X13)
+		_types.DeserializeField(d, &x.X14)
+	})
+	coroutine.RegisterVersion("github.com/dispatchrun/coroutine/compiler/testdata.LoopVarSharedClosures", "ae9863b2dd7a1495")
+}
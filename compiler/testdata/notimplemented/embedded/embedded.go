@@ -0,0 +1,13 @@
+package embedded
+
+import "github.com/dispatchrun/coroutine"
+
+type Inner struct{ X int }
+
+func Generic[T any](v T) {
+	coroutine.Yield[int, any](0)
+}
+
+func Embedded() {
+	Generic(struct{ Inner }{})
+}
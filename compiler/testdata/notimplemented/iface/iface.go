@@ -0,0 +1,15 @@
+package iface
+
+import (
+	"fmt"
+
+	"github.com/dispatchrun/coroutine"
+)
+
+func Generic[T any](v T) {
+	coroutine.Yield[int, any](0)
+}
+
+func Stringer() {
+	Generic[fmt.Stringer](nil)
+}
@@ -0,0 +1,11 @@
+package maptype
+
+import "github.com/dispatchrun/coroutine"
+
+func Generic[T any](v T) {
+	coroutine.Yield[int, any](0)
+}
+
+func Map() {
+	Generic(map[string]int{})
+}
@@ -0,0 +1,7 @@
+package platform
+
+import "github.com/dispatchrun/coroutine"
+
+func Name() {
+	coroutine.Yield[string, any](name())
+}
@@ -15,4 +15,5 @@ func init() {
 		F  uintptr
 		X0 int
 	}]("github.com/dispatchrun/coroutine/compiler/testdata/subpkg.Adder.func1")
+	_types.RegisterFuncID("github.com/dispatchrun/coroutine/compiler/testdata/subpkg.Adder.func1", "github.com/dispatchrun/coroutine/compiler/testdata/subpkg.Adder.func-9c826e38641366a6")
 }
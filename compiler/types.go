@@ -1,7 +1,6 @@
 package compiler
 
 import (
-	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
@@ -126,7 +125,8 @@ func typeExpr(p *packages.Package, typ types.Type, typeArg func(*types.TypeParam
 		p.TypesInfo.Defs[ident] = obj
 		return ident
 	}
-	panic(fmt.Sprintf("not implemented: %T", typ))
+	notImplemented(token.NoPos, "type %s (%T)", typ, typ)
+	return nil
 }
 
 func newFuncType(p *packages.Package, signature *types.Signature, typeArg func(*types.TypeParam) types.Type) *ast.FuncType {
@@ -223,7 +223,8 @@ func substituteTypeArgs(p *packages.Package, expr ast.Expr, typeArg func(*types.
 	case *ast.BasicLit:
 		return e
 	default:
-		panic(fmt.Sprintf("not implemented: %T", e))
+		notImplemented(e.Pos(), "type expression %T", e)
+		return nil
 	}
 }
 
@@ -289,7 +290,7 @@ func containsTypeParam(typ types.Type) bool {
 	case *types.Interface:
 	case *types.Struct:
 	default:
-		panic(fmt.Sprintf("not implemented: %T", typ))
+		notImplemented(token.NoPos, "type %s (%T)", typ, typ)
 	}
 	return false
 }
@@ -5,11 +5,22 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
-	"log"
+
+	"golang.org/x/tools/go/packages"
 )
 
-// unsupported checks a function for unsupported language features.
-func (c *compiler) unsupported(decl ast.Node, info *types.Info, colorsByFunc map[ast.Node]*types.Signature) (err error) {
+// unsupported checks a function for unsupported language features, returning
+// a diagnostic for each of them.
+func (c *compiler) unsupported(p *packages.Package, decl *ast.FuncDecl, colorsByFunc map[ast.Node]*types.Signature) (diags Diagnostics) {
+	info := p.TypesInfo
+	errorf := func(node ast.Node, format string, args ...any) {
+		diags = append(diags, &Diagnostic{
+			Pos:      c.fset.Position(node.Pos()),
+			Function: funcDeclName(p, decl),
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
 	ast.Inspect(decl, func(node ast.Node) bool {
 		switch nn := node.(type) {
 		case ast.Stmt:
@@ -17,7 +28,7 @@ func (c *compiler) unsupported(decl ast.Node, info *types.Info, colorsByFunc map
 			// Not yet supported:
 			case *ast.GoStmt:
 				if _, inColoredFunc := colorsByFunc[decl]; inColoredFunc {
-					err = fmt.Errorf("not implemented: go")
+					errorf(n, "not implemented: go")
 				} else {
 					// Allow go statement in certain limited circumstances.
 					_, goColoredFunc := colorsByFunc[n.Call.Fun]
@@ -28,10 +39,10 @@ func (c *compiler) unsupported(decl ast.Node, info *types.Info, colorsByFunc map
 						}
 					}
 					if goColoredFunc {
-						err = fmt.Errorf("not implemented: go")
+						errorf(n, "not implemented: go")
 					} else {
 						pos := c.fset.Position(n.Pos())
-						log.Printf("warning: goroutine mutations at %s may not be durable", pos)
+						c.logger.Printf("warning: goroutine mutations at %s may not be durable", pos)
 					}
 				}
 
@@ -39,9 +50,9 @@ func (c *compiler) unsupported(decl ast.Node, info *types.Info, colorsByFunc map
 			case *ast.BranchStmt:
 				// continue/break are supported, goto/fallthrough are not.
 				if n.Tok == token.GOTO {
-					err = fmt.Errorf("not implemented: goto")
+					errorf(n, "not implemented: goto")
 				} else if n.Tok == token.FALLTHROUGH {
-					err = fmt.Errorf("not implemented: fallthrough")
+					errorf(n, "not implemented: fallthrough")
 				}
 			case *ast.LabeledStmt:
 				// Labeled for/switch/select statements are supported,
@@ -49,7 +60,7 @@ func (c *compiler) unsupported(decl ast.Node, info *types.Info, colorsByFunc map
 				switch n.Stmt.(type) {
 				case *ast.ForStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
 				default:
-					err = fmt.Errorf("not implemented: labels not attached to for/switch/select")
+					errorf(n, "not implemented: labels not attached to for/switch/select")
 				}
 			case *ast.ForStmt:
 				// Only simple post iteration statements are supported.
@@ -60,16 +71,17 @@ func (c *compiler) unsupported(decl ast.Node, info *types.Info, colorsByFunc map
 					exprs = append(exprs, p.X)
 				case *ast.AssignStmt:
 					if len(p.Lhs) != len(p.Rhs) {
-						err = fmt.Errorf("not implemented: for loop post iteration assignment with unbalanced sides")
+						errorf(p, "not implemented: for loop post iteration assignment with unbalanced sides")
 					}
 					exprs = append(exprs, p.Lhs...)
 					exprs = append(exprs, p.Rhs...)
 				default:
-					err = fmt.Errorf("not implemented: for loop post iteration statement %T", p)
+					errorf(p, "not implemented: for loop post iteration statement %T", p)
 				}
 				for _, e := range exprs {
 					if countFunctionCalls(e, info) > 0 {
-						err = fmt.Errorf("not implemented: for loop post iteration statement with function call")
+						errorf(n.Post, "not implemented: for loop post iteration statement with function call")
+						break
 					}
 				}
 
@@ -93,10 +105,10 @@ func (c *compiler) unsupported(decl ast.Node, info *types.Info, colorsByFunc map
 
 			// Catch all in case new statements are added:
 			default:
-				err = fmt.Errorf("not implmemented: ast.Stmt(%T)", n)
+				errorf(n, "not implmemented: ast.Stmt(%T)", n)
 			}
 		}
-		return err == nil
+		return true
 	})
 	return
 }
@@ -15,8 +15,9 @@ import (
 
 const copyConcurrency = 16
 
-// vendorGOROOT copies GOROOT packages into a new directory.
-func vendorGOROOT(newRoot string, pkgs []*packages.Package) error {
+// rewriteGOROOT changes the paths of the files of GOROOT packages to point
+// to the directory where the packages will be vendored by vendorGOROOT.
+func rewriteGOROOT(newRoot string, pkgs []*packages.Package) error {
 	goroot := runtime.GOROOT()
 
 	var scanErr error
@@ -37,11 +38,6 @@ func vendorGOROOT(newRoot string, pkgs []*packages.Package) error {
 		return scanErr
 	}
 
-	// Copy the entire GOROOT/src directory.
-	if err := copyDir(filepath.Join(newRoot, "src"), filepath.Join(goroot, "src")); err != nil {
-		return err
-	}
-
 	// Rewrite GoFiles paths.
 	packages.Visit(pkgs, func(p *packages.Package) bool {
 		for i, path := range p.GoFiles {
@@ -50,6 +46,17 @@ func vendorGOROOT(newRoot string, pkgs []*packages.Package) error {
 		}
 		return true
 	}, nil)
+	return nil
+}
+
+// vendorGOROOT copies GOROOT packages into a new directory.
+func vendorGOROOT(newRoot string) error {
+	goroot := runtime.GOROOT()
+
+	// Copy the entire GOROOT/src directory.
+	if err := copyDir(filepath.Join(newRoot, "src"), filepath.Join(goroot, "src")); err != nil {
+		return err
+	}
 
 	// Symlink $GOROOT/pkg, which contains directories required
 	// at compile time.
@@ -60,6 +67,18 @@ func vendorGOROOT(newRoot string, pkgs []*packages.Package) error {
 	return err
 }
 
+// isVendoredGOROOT reports whether dir has the layout of the directories
+// created by vendorGOROOT, with a symbolic link to the pkg directory of
+// GOROOT, so that other directories aren't removed by Clean.
+func isVendoredGOROOT(dir string) bool {
+	pkg, err := os.Lstat(filepath.Join(dir, "pkg"))
+	if err != nil || pkg.Mode()&os.ModeSymlink == 0 {
+		return false
+	}
+	src, err := os.Stat(filepath.Join(dir, "src"))
+	return err == nil && src.IsDir()
+}
+
 func packageDir(p *packages.Package) string {
 	var f string
 	switch {
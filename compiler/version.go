@@ -0,0 +1,128 @@
+package compiler
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"hash"
+	"io"
+	"slices"
+	"strconv"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// The frames of coroutines carry the name and version of the function they
+// belong to in the tag of their IP field, which programs use to find the
+// migrations of frames created by other versions of the function (see
+// coroutine.RegisterMigration).
+//
+// The version of a function is derived from its syntax and from the types of
+// the fields of its frame. Any change to the function, except for changes to
+// comments and formatting, changes the layout of the frame or the numbering
+// of instruction pointers, so the version changes too.
+//
+// The current versions of the functions are registered with
+// coroutine.RegisterVersion, so that programs can tell the frames of other
+// versions apart when resuming coroutines serialized by other builds.
+
+// functionDigest starts the computation of the version of a function from its
+// syntax, which must be called before the function is compiled.
+func functionDigest(typ *ast.FuncType, body *ast.BlockStmt) hash.Hash {
+	h := sha256.New()
+	for _, node := range []ast.Node{typ, body} {
+		ast.Inspect(node, func(n ast.Node) bool {
+			if n == nil {
+				io.WriteString(h, ")")
+				return false
+			}
+			fmt.Fprintf(h, "(%T", n)
+			switch n := n.(type) {
+			case *ast.Ident:
+				writeToken(h, n.Name)
+			case *ast.BasicLit:
+				writeToken(h, n.Value)
+			case *ast.BinaryExpr:
+				writeToken(h, n.Op.String())
+			case *ast.UnaryExpr:
+				writeToken(h, n.Op.String())
+			case *ast.AssignStmt:
+				writeToken(h, n.Tok.String())
+			case *ast.IncDecStmt:
+				writeToken(h, n.Tok.String())
+			case *ast.BranchStmt:
+				writeToken(h, n.Tok.String())
+			case *ast.RangeStmt:
+				writeToken(h, n.Tok.String())
+			case *ast.GenDecl:
+				writeToken(h, n.Tok.String())
+			case *ast.ChanType:
+				writeToken(h, strconv.Itoa(int(n.Dir)))
+			case *ast.Ellipsis:
+				writeToken(h, token.ELLIPSIS.String())
+			}
+			return true
+		})
+	}
+	return h
+}
+
+func writeToken(h hash.Hash, s string) {
+	io.WriteString(h, " ")
+	io.WriteString(h, strconv.Quote(s))
+}
+
+// functionVersion completes the computation of the version of a function with
+// the types of the fields of its frame.
+func functionVersion(h hash.Hash, frame []FrameField) string {
+	for _, f := range frame {
+		writeToken(h, f.Name)
+		writeToken(h, f.Type)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// tagFrame records the name and version of a function in the tag of the IP
+// field of its frame.
+func tagFrame(frameType *ast.StructType, name, version string) {
+	tag := fmt.Sprintf("frame:%q", name+"@"+version)
+	frameType.Fields.List[0].Tag = &ast.BasicLit{Kind: token.STRING, Value: "`" + tag + "`"}
+}
+
+// generateVersions registers the versions of the coroutines compiled in a file
+// with coroutine.RegisterVersion.
+func (c *compiler) generateVersions(p *packages.Package, f *ast.File, reports []FunctionReport) {
+	type version struct{ name, version string }
+	versions := make([]version, 0, len(reports))
+	for _, report := range reports {
+		versions = append(versions, version{report.Name, report.Version})
+	}
+	slices.SortFunc(versions, func(a, b version) int {
+		return cmp.Or(cmp.Compare(a.name, b.name), cmp.Compare(a.version, b.version))
+	})
+	versions = slices.Compact(versions)
+	if len(versions) == 0 {
+		return
+	}
+
+	coroutineIdent := ast.NewIdent("coroutine")
+	p.TypesInfo.Uses[coroutineIdent] = types.NewPkgName(token.NoPos, p.Types, "coroutine", c.coroutinePkg.Types)
+
+	stmts := make([]ast.Stmt, 0, len(versions))
+	for _, v := range versions {
+		stmts = append(stmts, &ast.ExprStmt{
+			X: &ast.CallExpr{
+				Fun: &ast.SelectorExpr{X: coroutineIdent, Sel: ast.NewIdent("RegisterVersion")},
+				Args: []ast.Expr{
+					&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(v.name)},
+					&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(v.version)},
+				},
+			},
+		})
+	}
+	appendInit(f, stmts)
+}
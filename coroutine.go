@@ -124,6 +124,28 @@ var (
 	ErrNotDurable = errors.New("only durable coroutines can be serialized")
 
 	// ErrInvalidState is an error that occurs when attempting to
-	// deserialize a coroutine that was serialized in another build.
+	// deserialize a coroutine that was serialized in another build, or
+	// which is incompatible with the build when unmarshaled with the
+	// CrossBuild option, or whose state is corrupted.
 	ErrInvalidState = errors.New("durable coroutine was serialized in another build")
 )
+
+// UnmarshalOption is an option for (*Context).Unmarshal.
+type UnmarshalOption func(*unmarshalOptions)
+
+type unmarshalOptions struct {
+	crossBuild bool
+}
+
+// CrossBuild returns an option which allows resuming coroutines serialized by
+// other builds of the program, for example after a redeploy.
+//
+// Functions and types are resolved by name in the program, and unmarshaling
+// fails with ErrInvalidState if they do not exist anymore or if their layout
+// changed (see types.CrossBuild). It also fails if the coroutines on the
+// stack were modified, since their frames are restored as they were
+// serialized, unless migrations of their frames were registered (see
+// RegisterMigration).
+func CrossBuild() UnmarshalOption {
+	return func(o *unmarshalOptions) { o.crossBuild = true }
+}
@@ -1,17 +1,23 @@
-//go:build durable
+//go:build durable || coroutine.durable
 
 package coroutine
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
 	"runtime"
+	"slices"
+	"sync"
 	"unsafe"
 
 	"github.com/dispatchrun/coroutine/types"
 )
 
 // Durable is a constant which takes the values true or false depending on
-// whether the program is built with the "durable" tag.
+// whether the program is built with the "durable" tag, or with the
+// "coroutine.durable" tag which selects the durable implementation of the
+// package for programs generated by coroc with a different build tag.
 const Durable = true
 
 // New creates a new coroutine which executes f as entry point.
@@ -66,11 +72,19 @@ type Stack struct {
 // stack's underlying frame backing array might change. Callers
 // intending to serialize the stack should call Store(fp, frame) for each
 // frame during stack unwinding.
+//
+// Frames created by other versions of the function are migrated when they
+// are pushed again, see RegisterMigration.
 func Push[Frame any](s *Stack) *Frame {
 	if s.isTop() {
 		s.Frames = append(s.Frames, new(Frame))
 	}
 	s.FP++
+	if m, ok := s.Frames[s.FP].(*pendingMigration); ok {
+		frame := new(Frame)
+		m.apply(frame)
+		s.Frames[s.FP] = frame
+	}
 	return s.Frames[s.FP].(*Frame)
 }
 
@@ -89,6 +103,123 @@ func (s *Stack) isTop() bool {
 	return s.FP == len(s.Frames)-1
 }
 
+// pendingMigration is a frame of an old version of a function, which is
+// migrated when the function resumes and pushes its frame.
+type pendingMigration struct {
+	frame     any
+	old       *OldFrame
+	migration Migration
+}
+
+func (m *pendingMigration) apply(frame any) {
+	ip, err := m.migration(m.old, frame)
+	if err != nil {
+		panic(fmt.Errorf("coroutine: cannot migrate frame of %s version %s: %w", m.old.Function, m.old.Version, err))
+	}
+	reflect.ValueOf(frame).Elem().Field(0).SetInt(int64(ip))
+}
+
+// migrateFrames replaces the frames of the stack which were created by other
+// versions of the functions with pending migrations. An error is returned if
+// no migration was registered for one of those frames.
+func migrateFrames(b []byte, s *Stack) error {
+	var regions []*types.Region
+	for i, frame := range s.Frames {
+		function, version, ok := frameVersion(frame)
+		if !ok || isCurrentVersion(function, version) {
+			continue
+		}
+		migration := lookupMigration(function, version)
+		if migration == nil {
+			return fmt.Errorf("no migration registered for the frame of %s version %s", function, version)
+		}
+		if regions == nil {
+			var err error
+			if regions, err = frameRegions(b, len(s.Frames)); err != nil {
+				return err
+			}
+		}
+		s.Frames[i] = &pendingMigration{
+			frame: frame,
+			old: &OldFrame{
+				Function: function,
+				Version:  version,
+				IP:       int(reflect.ValueOf(frame).Elem().Field(0).Int()),
+				Region:   regions[i],
+			},
+			migration: migration,
+		}
+	}
+	return nil
+}
+
+// frameRegions returns the memory regions holding the n stack frames of a
+// serialized coroutine.
+func frameRegions(b []byte, n int) ([]*types.Region, error) {
+	state, err := types.Inspect(b)
+	if err != nil {
+		return nil, err
+	}
+
+	coroutine, err := structRegion(state.Root())
+	if err != nil {
+		return nil, err
+	}
+
+	var frames *types.Region
+	fields := coroutine.Scan()
+	for frames == nil && fields.Next() {
+		if f := fields.Field(); f != nil && f.Name() == "Frames" && fields.Kind() == reflect.Slice {
+			frames, _ = fields.Region()
+		}
+	}
+	if err := fields.Close(); err != nil {
+		return nil, err
+	}
+	if frames == nil {
+		return nil, fmt.Errorf("stack frames not found in state")
+	}
+
+	regions := make([]*types.Region, 0, n)
+	elems := frames.Scan()
+	for len(regions) < n && elems.Next() {
+		if elems.Kind() == reflect.Interface {
+			region, _ := elems.Region()
+			if region != nil {
+				if region, err = structRegion(region); err != nil {
+					return nil, err
+				}
+			}
+			regions = append(regions, region)
+		}
+	}
+	if err := elems.Close(); err != nil {
+		return nil, err
+	}
+	if len(regions) < n {
+		return nil, fmt.Errorf("stack frames not found in state")
+	}
+	return regions, nil
+}
+
+// structRegion follows the pointers stored in a region to the region of the
+// struct they point to.
+func structRegion(r *types.Region) (*types.Region, error) {
+	for r.Type().Kind() != reflect.Struct {
+		s := r.Scan()
+		if !s.Next() {
+			if err := s.Close(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("struct not found in state")
+		}
+		if r, _ = s.Region(); r == nil {
+			return nil, fmt.Errorf("struct not found in state")
+		}
+	}
+	return r, nil
+}
+
 type serializedCoroutine[R any] struct {
 	entry  func()
 	entryR func() R
@@ -96,12 +227,37 @@ type serializedCoroutine[R any] struct {
 	resume bool
 }
 
+// serializedCoroutineTypes holds a *sync.Once for each instantiation of
+// serializedCoroutine, since generic types cannot have package variables of
+// their own.
+var serializedCoroutineTypes sync.Map // reflect.Type => *sync.Once
+
+// registerSerializedCoroutine registers the type of serialized coroutines
+// returning R for cross-build deserialization the first time it is called.
+func registerSerializedCoroutine[R any]() {
+	t := reflect.TypeFor[serializedCoroutine[R]]()
+	once, _ := serializedCoroutineTypes.LoadOrStore(t, new(sync.Once))
+	once.(*sync.Once).Do(types.RegisterType[serializedCoroutine[R]])
+}
+
 // Marshal returns a serialized Context.
 func (c *Context[R, S]) Marshal() ([]byte, error) {
+	stack := c.Stack
+	cloned := false
+	for i, frame := range c.Stack.Frames {
+		if m, ok := frame.(*pendingMigration); ok {
+			// The coroutine did not resume since it was unmarshaled,
+			// serialize the frame as it was.
+			if !cloned {
+				stack.Frames, cloned = slices.Clone(stack.Frames), true
+			}
+			stack.Frames[i] = m.frame
+		}
+	}
 	return types.Serialize(&serializedCoroutine[R]{
 		entry:  c.entry,
 		entryR: c.entryR,
-		stack:  c.Stack,
+		stack:  stack,
 		resume: c.resume,
 	})
 }
@@ -109,11 +265,25 @@ func (c *Context[R, S]) Marshal() ([]byte, error) {
 // Unmarshal deserializes a Context from the provided buffer, returning
 // the number of bytes that were read in order to reconstruct the
 // context.
-func (c *Context[R, S]) Unmarshal(b []byte) error {
-	v, err := types.Deserialize(b)
+func (c *Context[R, S]) Unmarshal(b []byte, options ...UnmarshalOption) error {
+	var opts unmarshalOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	var deserializeOptions []types.DeserializeOption
+	if opts.crossBuild {
+		registerSerializedCoroutine[R]()
+		deserializeOptions = append(deserializeOptions, types.CrossBuild())
+	}
+
+	v, err := types.Deserialize(b, deserializeOptions...)
 	if err != nil {
-		if errors.Is(err, types.ErrBuildIDMismatch) {
+		switch {
+		case errors.Is(err, types.ErrBuildIDMismatch):
 			err = ErrInvalidState
+		case errors.Is(err, types.ErrIncompatibleState), errors.Is(err, types.ErrInvalidState):
+			err = fmt.Errorf("%w: %w", ErrInvalidState, err)
 		}
 		return err
 	}
@@ -122,6 +292,11 @@ func (c *Context[R, S]) Unmarshal(b []byte) error {
 	c.entryR = s.entryR
 	c.Stack = s.stack
 	c.resume = s.resume
+	if opts.crossBuild {
+		if err := migrateFrames(b, &c.Stack); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidState, err)
+		}
+	}
 	return nil
 }
 
@@ -1,4 +1,4 @@
-//go:build !durable
+//go:build !durable && !coroutine.durable
 
 package coroutine
 
@@ -9,7 +9,9 @@ import (
 )
 
 // Durable is a constant which takes the values true or false depending on
-// whether the program is built with the "durable" tag.
+// whether the program is built with the "durable" tag, or with the
+// "coroutine.durable" tag which selects the durable implementation of the
+// package for programs generated by coroc with a different build tag.
 const Durable = false
 
 // New creates a new coroutine which executes f as entry point.
@@ -82,7 +84,7 @@ func (c *Context[R, S]) Marshal() ([]byte, error) {
 	return nil, ErrNotDurable
 }
 
-func (c *Context[R, S]) Unmarshal(b []byte) error {
+func (c *Context[R, S]) Unmarshal(b []byte, options ...UnmarshalOption) error {
 	return ErrNotDurable
 }
 
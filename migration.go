@@ -0,0 +1,118 @@
+package coroutine
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/dispatchrun/coroutine/types"
+)
+
+// OldFrame is the stack frame of a durable coroutine created by an old version
+// of a function, which a Migration converts to a frame of the current version
+// of the function.
+type OldFrame struct {
+	// Function is the name of the function, as found in the compilation
+	// report of coroc. Function literals are named after the function
+	// declaration they appear in.
+	Function string
+
+	// Version is the version of the function that created the frame.
+	Version string
+
+	// IP is the instruction pointer at which the old version of the
+	// function would have resumed.
+	IP int
+
+	// Region is the memory region holding the frame in the coroutine state.
+	// Its type describes the fields of the old frame, and their values can
+	// be read by scanning the region (see types.Inspect).
+	Region *types.Region
+}
+
+// Migration is a function which converts a frame created by an old version of
+// a function to a frame of the current version of the function.
+//
+// The migration sets the fields of the frame passed as argument, which is a
+// pointer to the zero value of the frame struct of the function, and returns
+// the instruction pointer at which the function resumes.
+type Migration func(old *OldFrame, frame any) (ip int, err error)
+
+type migrationKey struct {
+	function, version string
+}
+
+var migrations struct {
+	sync.Mutex
+	m map[migrationKey]Migration
+}
+
+// RegisterMigration registers a migration of the frames created by a version
+// of a function when unmarshaling durable coroutines with CrossBuild.
+//
+// The versions of functions are listed in the compilation report of coroc,
+// and change whenever functions are modified in a way that changes the layout
+// of their frame or the numbering of their instruction pointers. Unmarshaling
+// coroutines holding frames created by another version of a function fails
+// with ErrInvalidState unless a migration was registered for that version.
+//
+// Frames which are referenced by closures cannot be migrated, since closures
+// would keep referencing the old frame.
+func RegisterMigration(function, version string, migration Migration) {
+	migrations.Lock()
+	defer migrations.Unlock()
+
+	if migrations.m == nil {
+		migrations.m = make(map[migrationKey]Migration)
+	}
+	migrations.m[migrationKey{function, version}] = migration
+}
+
+var versions struct {
+	sync.Mutex
+	m map[migrationKey]struct{}
+}
+
+// RegisterVersion registers the current version of a function. The code
+// generated by coroc registers the versions of all the coroutines, so that
+// unmarshaling coroutines with CrossBuild fails if they hold frames created
+// by other versions of the functions which cannot be migrated.
+func RegisterVersion(function, version string) {
+	versions.Lock()
+	defer versions.Unlock()
+
+	if versions.m == nil {
+		versions.m = make(map[migrationKey]struct{})
+	}
+	versions.m[migrationKey{function, version}] = struct{}{}
+}
+
+func isCurrentVersion(function, version string) bool {
+	versions.Lock()
+	defer versions.Unlock()
+
+	_, ok := versions.m[migrationKey{function, version}]
+	return ok
+}
+
+func lookupMigration(function, version string) Migration {
+	migrations.Lock()
+	defer migrations.Unlock()
+
+	return migrations.m[migrationKey{function, version}]
+}
+
+// frameVersion returns the name and version of the function that a frame
+// belongs to, which the compiler records in the tag of the IP field of the
+// frame struct.
+func frameVersion(frame any) (function, version string, ok bool) {
+	t := reflect.TypeOf(frame)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct || t.Elem().NumField() == 0 {
+		return "", "", false
+	}
+	tag, ok := t.Elem().Field(0).Tag.Lookup("frame")
+	if !ok {
+		return "", "", false
+	}
+	return strings.Cut(tag, "@")
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"unsafe"
+)
+
+// The buffered elements of channels are read from the circular queue of the
+// runtime representation of channels (see hchan in runtime/chan.go), which
+// lets the serializer capture the contents of channels without receiving
+// from them. The fields up to closed have the same layout in all versions of
+// Go, but the receive index moved when timer channels were added in Go 1.23
+// (see chan_go122.go and chan_go123.go).
+
+// len is the number of elements buffered in the channel.
+func (c *hchan) len() int {
+	return int(c.qcount)
+}
+
+// isClosed is true if the channel was closed.
+func (c *hchan) isClosed() bool {
+	return c.closed != 0
+}
+
+// elem returns the address of the i-th element buffered in the channel, in
+// the order they would be received.
+func (c *hchan) elem(i int, size uintptr) unsafe.Pointer {
+	j := (c.recvx + uint(i)) % c.dataqsiz
+	return unsafe.Add(c.buf, uintptr(j)*size)
+}
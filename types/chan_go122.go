@@ -0,0 +1,16 @@
+//go:build !go1.23
+
+package types
+
+import "unsafe"
+
+type hchan struct {
+	qcount   uint
+	dataqsiz uint
+	buf      unsafe.Pointer
+	elemsize uint16
+	closed   uint32
+	elemtype unsafe.Pointer
+	sendx    uint
+	recvx    uint
+}
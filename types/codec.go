@@ -1,12 +1,26 @@
 package types
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unsafe"
 )
 
 func init() {
 	Register[time.Time](serializeTime, deserializeTime)
+	Register[sync.Mutex](serializeMutex, deserializeMutex)
+	Register[sync.RWMutex](serializeRWMutex, deserializeRWMutex)
+	Register[sync.Once](serializeOnce, deserializeOnce)
+	Register[sync.WaitGroup](serializeWaitGroup, deserializeWaitGroup)
+	Register[sync.Map](serializeSyncMap, deserializeSyncMap)
+
+	// Marshalers are attached after the serializers above so the IDs of the
+	// serializers do not change.
+	registerMarshalers(serdes)
 }
 
 func serializeTime(s *Serializer, x *time.Time) error {
@@ -24,3 +38,149 @@ func deserializeTime(d *Deserializer, x *time.Time) error {
 	DeserializeTo(d, &b)
 	return x.UnmarshalBinary(b)
 }
+
+// The goroutines holding locks or waiting on sync values do not exist after
+// the state is deserialized, so mutexes and wait groups can only be serialized
+// when they are not in use. Their internal fields (semaphores, wait counts) are
+// not serialized, and they are deserialized as zero values.
+//
+// The state of sync values is read from their unexported fields without
+// acquiring them, so serializing a mutex does not change the state of the
+// program. A mutex locked by another goroutine while the state is serialized
+// may still be serialized as unlocked; values shared with other goroutines
+// must not be in use during serialization. The layout of the types of the
+// sync package may change in future versions of Go, in which case the values
+// cannot be serialized.
+
+var (
+	errLocked    = errors.New("cannot serialize a locked mutex")
+	errWaitGroup = errors.New("cannot serialize a wait group with a non-zero counter")
+)
+
+// syncField returns the address of the field of x at one of the paths, which
+// must be of the given kind.
+func syncField(x any, kind reflect.Kind, paths ...[]string) (unsafe.Pointer, error) {
+	v := reflect.ValueOf(x).Elem()
+	for _, path := range paths {
+		f := v
+		for _, name := range path {
+			if f.Kind() != reflect.Struct {
+				f = reflect.Value{}
+				break
+			}
+			if f = f.FieldByName(name); !f.IsValid() {
+				break
+			}
+		}
+		if f.IsValid() && f.Kind() == kind {
+			return unsafe.Pointer(f.UnsafeAddr()), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot serialize %s: unsupported layout of the type", v.Type())
+}
+
+// mutexLocked returns true if the mutex is locked.
+func mutexLocked(x *sync.Mutex) (bool, error) {
+	// The state is an int32 of which the lowest bit is set when the mutex
+	// is locked. Newer versions of Go wrap the mutex of internal/sync.
+	state, err := syncField(x, reflect.Int32, []string{"state"}, []string{"mu", "state"})
+	if err != nil {
+		return false, err
+	}
+	return atomic.LoadInt32((*int32)(state))&1 != 0, nil
+}
+
+func serializeMutex(s *Serializer, x *sync.Mutex) error {
+	locked, err := mutexLocked(x)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return errLocked
+	}
+	return nil
+}
+
+func deserializeMutex(d *Deserializer, x *sync.Mutex) error {
+	return nil
+}
+
+func serializeRWMutex(s *Serializer, x *sync.RWMutex) error {
+	// The mutex is held by readers if the reader count is not zero, and by
+	// a writer if the w mutex is locked.
+	readers, err := syncField(x, reflect.Int32, []string{"readerCount", "v"})
+	if err != nil {
+		return err
+	}
+	w, err := syncField(x, reflect.Struct, []string{"w"})
+	if err != nil {
+		return err
+	}
+	locked, err := mutexLocked((*sync.Mutex)(w))
+	if err != nil {
+		return err
+	}
+	if locked || atomic.LoadInt32((*int32)(readers)) != 0 {
+		return errLocked
+	}
+	return nil
+}
+
+func deserializeRWMutex(d *Deserializer, x *sync.RWMutex) error {
+	return nil
+}
+
+func serializeOnce(s *Serializer, x *sync.Once) error {
+	// The done field is an atomic.Uint32 or atomic.Bool depending on the
+	// version of Go, both of which store their value in a uint32 field.
+	done, err := syncField(x, reflect.Uint32, []string{"done", "v"})
+	if err != nil {
+		return err
+	}
+	SerializeT(s, atomic.LoadUint32((*uint32)(done)) != 0)
+	return nil
+}
+
+func deserializeOnce(d *Deserializer, x *sync.Once) error {
+	var done bool
+	DeserializeTo(d, &done)
+	if done {
+		x.Do(func() {})
+	}
+	return nil
+}
+
+func serializeWaitGroup(s *Serializer, x *sync.WaitGroup) error {
+	// The counter is in the high 32 bits of the state.
+	state, err := syncField(x, reflect.Uint64, []string{"state", "v"})
+	if err != nil {
+		return err
+	}
+	if atomic.LoadUint64((*uint64)(state))>>32 != 0 {
+		return errWaitGroup
+	}
+	return nil
+}
+
+func deserializeWaitGroup(d *Deserializer, x *sync.WaitGroup) error {
+	return nil
+}
+
+func serializeSyncMap(s *Serializer, x *sync.Map) error {
+	m := make(map[any]any)
+	x.Range(func(k, v any) bool {
+		m[k] = v
+		return true
+	})
+	SerializeT(s, m)
+	return nil
+}
+
+func deserializeSyncMap(d *Deserializer, x *sync.Map) error {
+	var m map[any]any
+	DeserializeTo(d, &m)
+	for k, v := range m {
+		x.Store(k, v)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package types
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// codegen.go contains the support for serialization procedures generated by
+// coroc. The compiler generates a codec for the frames of coroutines and the
+// struct types reachable from them, which serializes the struct fields without
+// having to reflect on the struct type. The generated codecs produce the same
+// output as the reflection based procedures, which remain the fallback for the
+// types that don't have a codec.
+
+// Global codec register.
+var codecs = map[reflect.Type]codec{}
+
+type codec struct {
+	ser func(*Serializer, unsafe.Pointer)
+	des func(*Deserializer, unsafe.Pointer)
+	// Transient fields are skipped by the generated functions, and are
+	// re-initialized after deserializing the struct.
+	transient []reflect.StructField
+}
+
+// RegisterCodec attaches generated serialization and deserialization functions
+// to type T. It is intended to be called by code generated by coroc, which is
+// responsible for producing functions that encode values the same way as
+// the reflection based procedures do.
+//
+// Custom serializers registered with [Register] take precedence over codecs.
+func RegisterCodec[T any](ser func(*Serializer, *T), des func(*Deserializer, *T)) {
+	t := reflect.TypeFor[T]()
+	c := codec{
+		ser: func(s *Serializer, p unsafe.Pointer) { ser(s, (*T)(p)) },
+		des: func(d *Deserializer, p unsafe.Pointer) { des(d, (*T)(p)) },
+	}
+	if t.Kind() == reflect.Struct {
+		c.transient = transientFields(t)
+	}
+	codecs[t] = c
+	registry.add(t)
+}
+
+// SerializeField serializes a struct field in a codec generated by coroc.
+// Unlike [SerializeT], no type information is written.
+func SerializeField[T any](s *Serializer, x *T) {
+	t := reflect.TypeFor[T]()
+	if _, ok := s.serdes.serdeByType(t); ok || !serializeBasic(s, x) {
+		serializeAny(s, t, unsafe.Pointer(x))
+	}
+}
+
+// DeserializeField deserializes a struct field in a codec generated by coroc.
+func DeserializeField[T any](d *Deserializer, x *T) {
+	t := reflect.TypeFor[T]()
+	if _, ok := d.serdes.serdeByType(t); ok || !deserializeBasic(d, x) {
+		deserializeAny(d, t, unsafe.Pointer(x))
+	}
+}
+
+// serializeBasic serializes values of predeclared types without going through
+// reflection, returning false if x is not a pointer to such a value.
+func serializeBasic(s *Serializer, x any) bool {
+	switch v := x.(type) {
+	case *bool:
+		serializeBool(s, *v)
+	case *int:
+		serializeInt(s, *v)
+	case *int64:
+		serializeInt64(s, *v)
+	case *int32:
+		serializeInt32(s, *v)
+	case *int16:
+		serializeInt16(s, *v)
+	case *int8:
+		serializeInt8(s, *v)
+	case *uint:
+		serializeUint(s, *v)
+	case *uint64:
+		serializeUint64(s, *v)
+	case *uint32:
+		serializeUint32(s, *v)
+	case *uint16:
+		serializeUint16(s, *v)
+	case *uint8:
+		serializeUint8(s, *v)
+	case *uintptr:
+		serializeUintptr(s, *v)
+	case *float64:
+		serializeFloat64(s, *v)
+	case *float32:
+		serializeFloat32(s, *v)
+	case *complex64:
+		serializeComplex64(s, *v)
+	case *complex128:
+		serializeComplex128(s, *v)
+	case *string:
+		serializeString(s, v)
+	default:
+		return false
+	}
+	return true
+}
+
+func deserializeBasic(d *Deserializer, x any) bool {
+	switch v := x.(type) {
+	case *bool:
+		deserializeBool(d, v)
+	case *int:
+		deserializeInt(d, v)
+	case *int64:
+		deserializeInt64(d, v)
+	case *int32:
+		deserializeInt32(d, v)
+	case *int16:
+		deserializeInt16(d, v)
+	case *int8:
+		deserializeInt8(d, v)
+	case *uint:
+		deserializeUint(d, v)
+	case *uint64:
+		deserializeUint64(d, v)
+	case *uint32:
+		deserializeUint32(d, v)
+	case *uint16:
+		deserializeUint16(d, v)
+	case *uint8:
+		deserializeUint8(d, v)
+	case *uintptr:
+		deserializeUintptr(d, v)
+	case *float64:
+		deserializeFloat64(d, v)
+	case *float32:
+		deserializeFloat32(d, v)
+	case *complex64:
+		deserializeComplex64(d, v)
+	case *complex128:
+		deserializeComplex128(d, v)
+	case *string:
+		deserializeString(d, v)
+	default:
+		return false
+	}
+	return true
+}
@@ -3,6 +3,7 @@ package types
 import (
 	"fmt"
 	"reflect"
+	"sync"
 	"unsafe"
 )
 
@@ -25,9 +26,15 @@ type DeserializerFunc[T any] func(*Deserializer, *T) error
 // Coroutine state is serialized and deserialized when calling [Context.Marshal]
 // and [Context.Unmarshal] respectively.
 //
-// Go basic types, structs, interfaces, slices, arrays, or any combination of
-// them have built-in serialization and deserialization mechanisms. Channels and
-// sync values do not.
+// Go basic types, structs, interfaces, slices, arrays, maps, channels, or any
+// combination of them have built-in serialization and deserialization
+// mechanisms. So do time.Time and the sync.Mutex, sync.RWMutex, sync.Once,
+// sync.WaitGroup and sync.Map types: mutexes and wait groups can only be
+// serialized when they are not in use and are deserialized as zero values,
+// while sync.Once retains whether it was done and sync.Map its entries. Types
+// implementing [DurableMarshaler], encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler, or gob.GobEncoder and gob.GobDecoder, are
+// serialized using those methods.
 //
 // Custom serializer and deserializer functions can be attached to types using
 // [Register] to control how they are serialized, and possibly perform
@@ -40,6 +47,9 @@ type DeserializerFunc[T any] func(*Deserializer, *T) error
 // result, slices sharing the same backing array are deserialized into one array
 // with two shared slices, just like the original state was. Elements between
 // length and capacity are also preserved.
+//
+// Functions registered with Register take precedence over the marshaling
+// methods of the types, regardless of the order in which they were registered.
 func Register[T any](
 	serializer SerializerFunc[T],
 	deserializer DeserializerFunc[T]) {
@@ -82,6 +92,7 @@ func registerSerde[T any](serdes *serdemap,
 	}
 
 	serdes.attach(t, s, d)
+	registry.add(t)
 }
 
 type serializerFunc func(*Serializer, reflect.Type, unsafe.Pointer)
@@ -100,6 +111,17 @@ type serdemap struct {
 	serdes     []serde
 	serdesByT  map[reflect.Type]serde
 	interfaces []serde
+	marshalers []serde
+
+	// Results of serdeByType, including types without serde, since looking
+	// up the interfaces implemented by a type is expensive and done for each
+	// value serialized. The cache is reset when serdes are attached.
+	lookups sync.Map // reflect.Type => serdeLookup
+}
+
+type serdeLookup struct {
+	serde serde
+	ok    bool
 }
 
 func newSerdeMap() *serdemap {
@@ -124,9 +146,43 @@ func (m *serdemap) attach(t reflect.Type, ser serializerFunc, des deserializerFu
 	if t.Kind() == reflect.Interface {
 		m.interfaces = append(m.interfaces, s)
 	}
+	m.resetLookups()
+}
+
+// attachMarshaler attaches serialization functions to the types implementing
+// interface t with their pointer receiver, which take precedence over the
+// reflection based procedures but not over the functions attached to types
+// or interfaces with attach.
+func (m *serdemap) attachMarshaler(t reflect.Type, ser serializerFunc, des deserializerFunc) {
+	s := serde{
+		id:  serdeid(len(m.serdes)) + 1,
+		typ: t,
+		ser: ser,
+		des: des,
+	}
+	m.serdes = append(m.serdes, s)
+	m.marshalers = append(m.marshalers, s)
+	m.resetLookups()
+}
+
+func (m *serdemap) resetLookups() {
+	m.lookups.Range(func(t, _ any) bool {
+		m.lookups.Delete(t)
+		return true
+	})
 }
 
 func (m *serdemap) serdeByType(x reflect.Type) (serde, bool) {
+	if v, ok := m.lookups.Load(x); ok {
+		l := v.(serdeLookup)
+		return l.serde, l.ok
+	}
+	s, ok := m.lookupSerde(x)
+	m.lookups.Store(x, serdeLookup{serde: s, ok: ok})
+	return s, ok
+}
+
+func (m *serdemap) lookupSerde(x reflect.Type) (serde, bool) {
 	s, ok := m.serdesByT[x]
 	if ok {
 		return s, true
@@ -137,6 +193,15 @@ func (m *serdemap) serdeByType(x reflect.Type) (serde, bool) {
 			return s, true
 		}
 	}
+	if x.Kind() != reflect.Interface && len(m.marshalers) > 0 {
+		px := reflect.PointerTo(x)
+		for i := range m.marshalers {
+			s := m.marshalers[i]
+			if px.Implements(s.typ) {
+				return s, true
+			}
+		}
+	}
 	return serde{}, false
 }
 
@@ -0,0 +1,384 @@
+package types
+
+// decode.go contains the checks that the deserializer performs on the states
+// it decodes. States are loaded from storage that the program does not
+// control, so they may be truncated or corrupted: lengths, offsets and
+// references found in the state are validated before memory is allocated or
+// written, and the errors report the position of the invalid data.
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+
+	coroutinev1 "github.com/dispatchrun/coroutine/gen/proto/go/coroutine/v1"
+)
+
+// ErrInvalidState is an error that occurs when a state is corrupted, or was
+// not produced by [Serialize].
+var ErrInvalidState = errors.New("invalid state")
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidState, fmt.Sprintf(format, args...))
+}
+
+// DecodeError is the error returned by [Deserialize] and reported by
+// [Scanner] when the data of a region of the state is invalid.
+//
+// DecodeError wraps [ErrInvalidState].
+type DecodeError struct {
+	// Region is the index of the region holding the invalid data, as
+	// returned by [Region.Index]; it is -1 for the root region.
+	Region int
+	// Offset is the position of the invalid data, in bytes from the start
+	// of the region.
+	Offset int
+	// Err describes why the data is invalid.
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	region := "root region"
+	if e.Region >= 0 {
+		region = fmt.Sprintf("region %d", e.Region)
+	}
+	return fmt.Sprintf("%s: %s at offset %d: %v", ErrInvalidState, region, e.Offset, e.Err)
+}
+
+func (e *DecodeError) Unwrap() []error {
+	return []error{ErrInvalidState, e.Err}
+}
+
+// defaultMaxAllocation is the default limit of the memory allocated to
+// deserialize a state, see MaxAllocation.
+const defaultMaxAllocation = 1 << 30
+
+// MaxAllocation returns an option which limits the memory that [Deserialize]
+// allocates for the values of the state to size bytes, 1 GiB by default.
+//
+// Values of zero-sized types count as one byte, so that the time spent
+// decoding arrays of such values is also bounded.
+func MaxAllocation(size int) DeserializeOption {
+	return func(o *deserializeOptions) { o.maxAllocation = size }
+}
+
+// errorf returns a *DecodeError reporting the current position of the
+// deserializer.
+func (d *Deserializer) errorf(format string, args ...any) error {
+	return &DecodeError{
+		Region: d.region,
+		Offset: len(d.data) - len(d.b),
+		Err:    fmt.Errorf(format, args...),
+	}
+}
+
+// catch converts the panics raised while decoding the data of the
+// deserializer into errors reporting the position of the data. Errors which
+// are not caused by invalid data, such as errors of custom deserializers or
+// incompatible types, are left untouched. It must be deferred.
+func (d *Deserializer) catch() {
+	switch e := recover().(type) {
+	case nil:
+	case *DecodeError:
+		panic(e)
+	case runtime.Error:
+		panic(&DecodeError{Region: d.region, Offset: len(d.data) - len(d.b), Err: e})
+	case error:
+		panic(e)
+	default:
+		panic(d.errorf("%v", e))
+	}
+}
+
+// read consumes the next n bytes of the input.
+func (d *Deserializer) read(n int) []byte {
+	if len(d.b) < n {
+		panic(d.errorf("unexpected end of data"))
+	}
+	b := d.b[:n:n]
+	d.b = d.b[n:]
+	return b
+}
+
+// allocate accounts for the memory allocated to deserialize n values of the
+// given size, failing if the total exceeds the limit set by MaxAllocation.
+func (d *Deserializer) allocate(size uintptr, n int) {
+	if n < 0 {
+		panic(d.errorf("invalid length %d", n))
+	}
+	size = max(size, 1)
+	if uintptr(n) > uintptr(d.maxAllocation-d.allocated)/size {
+		panic(d.errorf("allocating %d values of %d bytes exceeds the limit of %d bytes", n, size, d.maxAllocation))
+	}
+	d.allocated += int(size) * n
+}
+
+// typeOf returns the type recorded with id in the state.
+func (d *Deserializer) typeOf(id int) reflect.Type {
+	if d.types.lookup(typeid(id)) == nil || id != int(typeid(id)) {
+		panic(d.errorf("type %d not found", id))
+	}
+	return d.types.ToReflect(typeid(id))
+}
+
+// regionOf returns the region referenced with id, its type and its length,
+// which is negative if the region is not an array.
+func (d *Deserializer) regionOf(id int) (*coroutinev1.Region, reflect.Type, int) {
+	if id < 1 || id > len(d.regions) {
+		panic(d.errorf("region %d not found", id))
+	}
+	region := d.regions[id-1]
+	t := d.typeOf(int(region.Type >> 1))
+	if region.Type&1 == 1 {
+		return region, t, int(region.ArrayLength)
+	}
+	return region, t, -1
+}
+
+// referenceRegion returns the region holding the map or channel of type t
+// referenced with id.
+func (d *Deserializer) referenceRegion(id int, t reflect.Type) *coroutinev1.Region {
+	region, rt, length := d.regionOf(id)
+	ok := length < 0 && rt.Kind() == t.Kind() && rt.Elem() == t.Elem()
+	if ok && t.Kind() == reflect.Map {
+		ok = rt.Key() == t.Key()
+	}
+	if !ok {
+		panic(d.errorf("region %d of type %s cannot hold a %s", id, rt, t))
+	}
+	return region
+}
+
+// validPointer is true if n values of type t at offset off of a region of
+// length values of type rt (a single value if length is negative) are within
+// the bounds of the region, and can be accessed as values of type t. A nil
+// type t is used for unsafe pointers, which may point anywhere in the region.
+func validPointer(rt reflect.Type, length int, off int, t reflect.Type, n int) bool {
+	if length < 0 {
+		length = 1
+	}
+	if n < 0 {
+		n = 1
+	}
+	size := rt.Size() * uintptr(length)
+	if off < 0 || uintptr(off) > size {
+		return false
+	}
+	if t == nil {
+		return true
+	}
+	if es := t.Size(); es == 0 || n == 0 {
+		return true
+	} else if uintptr(n) > (size-uintptr(off))/es {
+		return false
+	}
+	return typeAt(rt, length, uintptr(off), t, n)
+}
+
+// typeAt is true if the memory of n values of type t at offset off of length
+// values of type rt holds values of type t, or if it holds no pointers and
+// values of type t do not either: reinterpreting such memory is safe.
+func typeAt(rt reflect.Type, length int, off uintptr, t reflect.Type, n int) bool {
+	size := t.Size() * uintptr(n)
+	for {
+		es := rt.Size()
+		if rt == t && off%es == 0 && off/es+uintptr(n) <= uintptr(length) {
+			return true
+		}
+		if !hasPointers(rt) && !hasPointers(t) {
+			return true
+		}
+		if es == 0 {
+			return false
+		}
+		off %= es
+		if off+size > es {
+			return false // spans values of another type
+		}
+		switch rt.Kind() {
+		case reflect.Array:
+			rt, length = rt.Elem(), rt.Len()
+		case reflect.Struct:
+			i := 0
+			for ; i < rt.NumField(); i++ {
+				f := rt.Field(i)
+				if off >= f.Offset && off+size <= f.Offset+f.Type.Size() && f.Type.Size() > 0 {
+					break
+				}
+			}
+			if i == rt.NumField() {
+				return false
+			}
+			f := rt.Field(i)
+			rt, length, off = f.Type, 1, off-f.Offset
+		default:
+			return false
+		}
+	}
+}
+
+// hasPointers is true if values of type t hold pointers.
+func hasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Array:
+		return t.Len() > 0 && hasPointers(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	case reflect.String, reflect.Pointer, reflect.UnsafePointer, reflect.Slice,
+		reflect.Map, reflect.Chan, reflect.Func, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkState returns an error if the types, functions or regions of a state
+// reference types or strings which do not exist, or if the types contain
+// cycles that Go types cannot have. The data of the regions is checked as it
+// is decoded.
+func checkState(state *coroutinev1.State) error {
+	if state.Build == nil || state.Root == nil {
+		return invalid("missing build or root region")
+	}
+	types, strings := state.Types, uint32(len(state.Strings))
+	typeExists := func(id uint32) bool { return id >= 1 && id <= uint32(len(types)) }
+
+	for i, t := range types {
+		if t == nil {
+			return invalid("type %d is missing", i+1)
+		}
+		if _, ok := coroutinev1.Kind_name[int32(t.Kind)]; !ok || t.Kind == coroutinev1.Kind_KIND_UNSPECIFIED {
+			return invalid("type %d has invalid kind %d", i+1, t.Kind)
+		}
+		if t.Name > strings || t.Package > strings {
+			return invalid("type %d has invalid name", i+1)
+		}
+		switch {
+		case t.Kind == coroutinev1.Kind_KIND_ARRAY && t.Length < 0:
+			return invalid("type %d has invalid length %d", i+1, t.Length)
+		case t.Kind == coroutinev1.Kind_KIND_CHAN && t.CustomSerializer == 0:
+			if _, ok := coroutinev1.ChanDir_name[int32(t.ChanDir)]; !ok || t.ChanDir == coroutinev1.ChanDir_CHAN_DIR_UNSPECIFIED {
+				return invalid("type %d has invalid channel direction %d", i+1, t.ChanDir)
+			}
+		}
+		for _, f := range typeFields(t) {
+			if f == nil || f.Name > strings || f.Package > strings {
+				return invalid("type %d has invalid field", i+1)
+			}
+		}
+		for _, id := range typeRefs(t) {
+			if !typeExists(id) {
+				return invalid("type %d references type %d which does not exist", i+1, id)
+			}
+		}
+	}
+
+	// Values cannot contain themselves, and only named types can refer to
+	// themselves. Types are reconstructed and printed recursively, which
+	// would not terminate if the state contained such cycles.
+	if typeCycle(types, typeValueRefs) {
+		return invalid("types contain themselves")
+	}
+	if typeCycle(types, func(t *coroutinev1.Type) []uint32 {
+		if t.Name != 0 && (t.MemoryOffset != 0 || t.CustomSerializer > 0) {
+			return nil
+		}
+		return typeRefs(t)
+	}) {
+		return invalid("unnamed types refer to themselves")
+	}
+
+	for i, f := range state.Functions {
+		if f == nil || f.Name > strings || (f.Type != 0 && !typeExists(f.Type)) || (f.Closure != 0 && !typeExists(f.Closure)) {
+			return invalid("function %d is invalid", i+1)
+		}
+	}
+	for i, r := range append([]*coroutinev1.Region{state.Root}, state.Regions...) {
+		if r == nil || !typeExists(r.Type>>1) {
+			return invalid("region %d has invalid type", i)
+		}
+	}
+	return nil
+}
+
+// typeFields returns the fields of struct types, or the methods of interface
+// types.
+func typeFields(t *coroutinev1.Type) []*coroutinev1.Field {
+	switch t.Kind {
+	case coroutinev1.Kind_KIND_STRUCT, coroutinev1.Kind_KIND_INTERFACE:
+		return t.Fields
+	}
+	return nil
+}
+
+// typeRefs returns the IDs of the types that a type refers to. Types with
+// custom serializers are opaque.
+func typeRefs(t *coroutinev1.Type) (refs []uint32) {
+	if t.CustomSerializer > 0 {
+		return nil
+	}
+	switch t.Kind {
+	case coroutinev1.Kind_KIND_ARRAY, coroutinev1.Kind_KIND_CHAN, coroutinev1.Kind_KIND_POINTER, coroutinev1.Kind_KIND_SLICE:
+		return []uint32{t.Elem}
+	case coroutinev1.Kind_KIND_MAP:
+		return []uint32{t.Key, t.Elem}
+	case coroutinev1.Kind_KIND_FUNC:
+		refs = append(refs, t.Params...)
+		return append(refs, t.Results...)
+	}
+	for _, f := range typeFields(t) {
+		refs = append(refs, f.Type)
+	}
+	return refs
+}
+
+// typeValueRefs returns the IDs of the types that values of a type contain.
+func typeValueRefs(t *coroutinev1.Type) []uint32 {
+	switch t.Kind {
+	case coroutinev1.Kind_KIND_ARRAY, coroutinev1.Kind_KIND_STRUCT:
+		return typeRefs(t)
+	}
+	return nil
+}
+
+// typeCycle is true if the types contain a cycle through the references
+// returned by refs.
+func typeCycle(types []*coroutinev1.Type, refs func(*coroutinev1.Type) []uint32) bool {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make([]byte, len(types))
+
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		switch states[i] {
+		case visiting:
+			return true
+		case visited:
+			return false
+		}
+		states[i] = visiting
+		for _, id := range refs(types[i]) {
+			if visit(int(id) - 1) {
+				return true
+			}
+		}
+		states[i] = visited
+		return false
+	}
+
+	for i := range types {
+		if visit(i) {
+			return true
+		}
+	}
+	return false
+}
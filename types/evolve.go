@@ -0,0 +1,218 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	coroutinev1 "github.com/dispatchrun/coroutine/gen/proto/go/coroutine/v1"
+)
+
+// Struct types of the program may have evolved since the state was created
+// by another build. Fields of named struct types are matched by name rather
+// than position or offset: fields added to the type are left to their zero
+// value, and the values of fields removed from the type are decoded and
+// discarded. Pointers into values of evolved types are translated to the new
+// layout of the values.
+
+// structPlan describes how to decode the values of a struct type recorded in
+// the state as values of a struct type of the program.
+type structPlan struct {
+	fields    []planField // in the order of the recorded fields
+	transient []reflect.StructField
+}
+
+type planField struct {
+	typ    reflect.Type
+	offset uintptr
+	// The field was removed from the type, its values are skipped.
+	removed bool
+}
+
+func (plan *structPlan) decode(d *Deserializer, p unsafe.Pointer) {
+	for _, f := range plan.fields {
+		if f.removed {
+			d.allocate(f.typ.Size(), 1)
+			deserializeAny(d, f.typ, reflect.New(f.typ).UnsafePointer())
+		} else {
+			deserializeAny(d, f.typ, unsafe.Add(p, f.offset))
+		}
+	}
+	for _, f := range plan.transient {
+		reinitTransient(f, p)
+	}
+}
+
+// fieldError is the error returned when the type of a struct field changed.
+// Errors of nested fields are wrapped to form the path to the field.
+type fieldError struct {
+	name string
+	err  error
+}
+
+func (e *fieldError) Error() string {
+	path, err := e.name, e.err
+	for {
+		f, ok := err.(*fieldError)
+		if !ok {
+			break
+		}
+		path, err = path+"."+f.name, f.err
+	}
+	return fmt.Sprintf("field %s: %v", path, err)
+}
+
+func (e *fieldError) Unwrap() error { return e.err }
+
+// matchStruct returns an error if values of a struct type recorded in the
+// state cannot be decoded as values of struct type x. Fields of unnamed
+// struct types must not change, while fields of named struct types can be
+// added, removed, or reordered. Fields may also become transient, or stop
+// being transient, in which case they are treated as removed or added.
+func (m *typemap) matchStruct(t *coroutinev1.Type, x reflect.Type) error {
+	named := t.Name != 0
+	if !named && len(t.Fields) != x.NumField() {
+		return fmt.Errorf("struct has %d fields, now %d", len(t.Fields), x.NumField())
+	}
+
+	plan := &structPlan{transient: transientFields(x)}
+	evolved := false
+	var order []int
+
+	for i, f := range t.Fields {
+		name := m.strings.Lookup(f.Name)
+		pkg := m.strings.Lookup(f.Package)
+
+		j := -1
+		if named {
+			j = fieldByName(x, name, pkg)
+		} else if xf := x.Field(i); xf.Name == name && xf.PkgPath == pkg && xf.Anonymous == f.Anonymous {
+			j = i
+		}
+		if j < 0 && !named {
+			return fmt.Errorf("field %s is now %s", name, x.Field(i).Name)
+		}
+		if transient(reflect.StructTag(f.Tag)) {
+			// The values of the field are not in the state.
+			continue
+		}
+		if j < 0 || transient(x.Field(j).Tag) {
+			// The values of the field are still in the state and have to
+			// be decoded to be skipped.
+			plan.fields = append(plan.fields, planField{typ: m.ToReflect(typeid(f.Type)), removed: true})
+			evolved = true
+			continue
+		}
+
+		xf := x.Field(j)
+		if err := m.match(typeid(f.Type), xf.Type); err != nil {
+			return &fieldError{name, err}
+		}
+		plan.fields = append(plan.fields, planField{typ: xf.Type, offset: xf.Offset})
+		order = append(order, j)
+	}
+
+	// The recorded fields are decoded in the order of the fields of x if
+	// none were added, removed, or reordered.
+	n := 0
+	for i := 0; i < x.NumField(); i++ {
+		if transient(x.Field(i).Tag) {
+			continue
+		}
+		if n >= len(order) || order[n] != i {
+			evolved = true
+			break
+		}
+		n++
+	}
+	evolved = evolved || n != len(order)
+
+	if evolved {
+		if m.plans == nil {
+			m.plans = make(map[reflect.Type]*structPlan)
+		}
+		m.plans[x] = plan
+	}
+	return nil
+}
+
+func fieldByName(x reflect.Type, name, pkg string) int {
+	for i := 0; i < x.NumField(); i++ {
+		if f := x.Field(i); f.Name == name && f.PkgPath == pkg {
+			return i
+		}
+	}
+	return -1
+}
+
+// translate returns the offset in values of type x of the byte found at
+// offset off in the values of the type recorded with id. Offset zero always
+// refers to the value itself.
+func (m *typemap) translate(id typeid, x reflect.Type, off int) int {
+	if off == 0 {
+		return off
+	}
+	t := m.lookup(id)
+	switch {
+	case t.Kind == coroutinev1.Kind_KIND_ARRAY:
+		return m.translateArray(typeid(t.Elem), x.Elem(), off)
+
+	case t.Kind == coroutinev1.Kind_KIND_STRUCT && t.CustomSerializer == 0:
+		for i := len(t.Fields) - 1; i >= 0; i-- {
+			f := t.Fields[i]
+			if int(f.Offset) > off {
+				continue
+			}
+			name := m.strings.Lookup(f.Name)
+			j := fieldByName(x, name, m.strings.Lookup(f.Package))
+			if j < 0 {
+				panic(incompatible("pointer to field %s removed from %s", name, x))
+			}
+			xf := x.Field(j)
+			return int(xf.Offset) + m.translate(typeid(f.Type), xf.Type, off-int(f.Offset))
+		}
+	}
+	return off
+}
+
+// translateArray is like translate for arrays of elements of the type
+// recorded with id.
+func (m *typemap) translateArray(id typeid, x reflect.Type, off int) int {
+	size, _ := m.recordedSize(id)
+	if size == 0 {
+		return off
+	}
+	i, off := off/int(size), off%int(size)
+	return i*int(x.Size()) + m.translate(id, x, off)
+}
+
+// recordedSize returns the size and alignment that values of the type recorded
+// with id had in the build which created the state.
+func (m *typemap) recordedSize(id typeid) (size, align uintptr) {
+	t := m.lookup(id)
+	switch {
+	case t.Kind == coroutinev1.Kind_KIND_ARRAY:
+		size, align = m.recordedSize(typeid(t.Elem))
+		return size * uintptr(t.Length), align
+
+	case t.Kind == coroutinev1.Kind_KIND_STRUCT && t.CustomSerializer == 0:
+		align = 1
+		var last uintptr
+		for _, f := range t.Fields {
+			s, a := m.recordedSize(typeid(f.Type))
+			align = max(align, a)
+			size = uintptr(f.Offset) + s
+			last = s
+		}
+		if len(t.Fields) > 0 && last == 0 && size > 0 {
+			// Go pads structs ending with a zero-sized field so that
+			// pointers to the field don't point past the struct.
+			size++
+		}
+		return (size + align - 1) &^ (align - 1), align
+
+	default:
+		x := m.ToReflect(id)
+		return x.Size(), uintptr(x.Align())
+	}
+}
@@ -24,6 +24,10 @@ type Func struct {
 	// N starts at 1 and increments for each closure defined in the function.
 	Name string
 
+	// A stable identifier of the function, which is recorded in serialized
+	// states instead of the name if it is not empty (see RegisterFuncID).
+	ID string
+
 	// A type representing the signature of the function value.
 	//
 	// This field is nil if the type is unknown; by default the field is nil and
@@ -57,6 +61,7 @@ func RegisterFunc[Type any](name string) {
 	if f := FuncByName(name); f != nil {
 		var signature Type
 		f.Type = reflect.TypeOf(signature)
+		registry.add(f.Type)
 	}
 }
 
@@ -67,6 +72,26 @@ func RegisterClosure[Type, Closure any](name string) {
 		var signature Type
 		var closure Closure
 		f.Type, f.Closure = reflect.TypeOf(signature), reflect.TypeOf(closure)
+		registry.add(f.Type)
+		registry.add(f.Closure)
+	}
+}
+
+// RegisterFuncID assigns a stable identifier to the function with the given
+// name. Serialized states refer to the function by its identifier, which is
+// resolved when deserializing them even if the name of the function changed.
+//
+// The compiler assigns identifiers derived from the syntax of closures, since
+// the names given to closures by the linker depend on their position in the
+// function that declares them (see Func). Functions are still resolved by name
+// for states which do not refer to them by identifier.
+func RegisterFuncID(name, id string) {
+	if f := FuncByName(name); f != nil {
+		f.ID = id
+		if functionsByID == nil {
+			functionsByID = make(map[string]*Func)
+		}
+		functionsByID[id] = f
 	}
 }
 
@@ -108,6 +133,23 @@ func FuncAddr(fn any) uintptr {
 // returns nil.
 func FuncByName(name string) *Func { return functionsByName[name] }
 
+// funcByID returns the function with the given identifier, falling back to
+// the function with the given name (see RegisterFuncID).
+func funcByID(id string) *Func {
+	if f, ok := functionsByID[id]; ok {
+		return f
+	}
+	return FuncByName(id)
+}
+
+// id returns the identifier recorded in serialized states for the function.
+func (f *Func) id() string {
+	if f.ID != "" {
+		return f.ID
+	}
+	return f.Name
+}
+
 // FuncByAddr returns the function associated with the given address.
 //
 // Addresses in the returned Func value hold the value of the symbol location in
@@ -120,6 +162,7 @@ func FuncByAddr(addr uintptr) *Func { return functionsByAddr[addr] }
 var (
 	functionsByName map[string]*Func
 	functionsByAddr map[uintptr]*Func
+	functionsByID   map[string]*Func
 )
 
 func initFunctionTables(pclntab, symtab []byte) {
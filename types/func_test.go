@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 	"unsafe"
+
+	coroutinev1 "github.com/dispatchrun/coroutine/gen/proto/go/coroutine/v1"
 )
 
 func TestAddressOfNonFunctionValue(t *testing.T) {
@@ -132,3 +134,56 @@ func init() {
 	fn.Type = reflect.TypeOf(func() (_ int) { return })
 	fn.Closure = reflect.TypeOf(opFunc1Closure{})
 }
+
+//go:noinline
+func idClosure(n int) func() int {
+	return func() int { return n }
+}
+
+func TestFuncID(t *testing.T) {
+	const name = "github.com/dispatchrun/coroutine/types.idClosure.func1"
+	const id = "github.com/dispatchrun/coroutine/types.idClosure.func-0123456789abcdef"
+
+	RegisterClosure[func() int, struct {
+		F  uintptr
+		X0 int
+	}](name)
+	RegisterFuncID(name, id)
+
+	b, err := Serialize(idClosure(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := Inspect(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.Function(0).Name(); got != id {
+		t.Errorf("function recorded as %s, want %s", got, id)
+	}
+	v, err := Deserialize(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v.(func() int)(); got != 42 {
+		t.Errorf("unexpected closure result: %d", got)
+	}
+
+	// States which refer to the closure by name are still resolved.
+	var s coroutinev1.State
+	if err := s.UnmarshalVT(b); err != nil {
+		t.Fatal(err)
+	}
+	for i := range s.Strings {
+		if s.Strings[i] == id {
+			s.Strings[i] = name
+		}
+	}
+	v, err = Deserialize(mustSerialize(&s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v.(func() int)(); got != 42 {
+		t.Errorf("unexpected closure result: %d", got)
+	}
+}
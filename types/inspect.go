@@ -13,10 +13,15 @@ import (
 // Inspect inspects serialized durable coroutine state.
 //
 // The input should be a buffer produced by (*coroutine.Context).Marshal
-// or by types.Serialize.
+// or by types.Serialize. Inspect returns an error wrapping [ErrInvalidState]
+// if the types, functions or regions of the state are inconsistent; the data
+// of regions is checked as it is scanned.
 func Inspect(b []byte) (*State, error) {
 	var state coroutinev1.State
 	if err := state.UnmarshalVT(b); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidState, err)
+	}
+	if err := checkState(&state); err != nil {
 		return nil, err
 	}
 	return &State{state: &state}, nil
@@ -724,6 +729,9 @@ func (s *Scanner) Next() bool {
 
 		case scanstruct:
 			last.idx++
+			for last.idx < last.len && transient(last.typ.Field(last.idx).Tag()) {
+				last.idx++ // not serialized
+			}
 			if last.idx < last.len {
 				s.field = last.typ.Field(last.idx)
 				return s.readAny(s.field.Type(), len(s.stack))
@@ -758,8 +766,7 @@ func (s *Scanner) Next() bool {
 				return s.readAny(s.typ, len(s.stack))
 			}
 			if uint64(s.pos) > last.customtil {
-				s.err = fmt.Errorf("invalid custom object size")
-				return false
+				return s.fail(fmt.Errorf("invalid custom object size"))
 			}
 			s.custom = false
 		}
@@ -767,7 +774,7 @@ func (s *Scanner) Next() bool {
 	}
 
 	if s.pos != len(s.data) {
-		s.err = fmt.Errorf("trailing bytes")
+		s.fail(fmt.Errorf("trailing bytes"))
 	} else {
 		s.done = true // prevent re-init
 	}
@@ -821,18 +828,19 @@ func (s *Scanner) Nil() bool {
 	return s.nil
 }
 
-// Len is the length of the string, slice, array or map
-// the scanner is pointing to.
+// Len is the length of the string, slice, array, map or
+// channel the scanner is pointing to.
 func (s *Scanner) Len() int {
 	return s.len
 }
 
-// Cap is the capacity of the slice the scanner is pointing to.
+// Cap is the capacity of the slice or channel the scanner is pointing to.
 func (s *Scanner) Cap() int {
 	return s.cap
 }
 
-// Bool returns the bool the scanner points to.
+// Bool returns the bool the scanner points to, or whether the channel the
+// scanner points to is closed.
 func (s *Scanner) Bool() bool {
 	return s.data1 == 1
 }
@@ -917,10 +925,18 @@ func (s *Scanner) Complex128() complex128 {
 }
 
 // Close closes the scanner and returns any errors that occurred during scanning.
+//
+// Errors caused by invalid data are of type [*DecodeError].
 func (s *Scanner) Close() error {
 	return s.err
 }
 
+// fail records an error at the current position of the scanner.
+func (s *Scanner) fail(err error) bool {
+	s.err = &DecodeError{Region: s.src.index, Offset: s.pos, Err: err}
+	return false
+}
+
 func (s *Scanner) readAny(t *Type, depth int) (ok bool) {
 	s.typ = t
 	s.kind = t.Kind()
@@ -932,6 +948,10 @@ func (s *Scanner) readAny(t *Type, depth int) (ok bool) {
 		// Handle the first case here, and the reference case below.
 		return s.readMap()
 	}
+	if depth == 0 && t.Kind() == reflect.Chan {
+		// Likewise, channel regions encode the contents of a channel.
+		return s.readChan()
+	}
 
 	if t.Opaque() {
 		return s.readCustom()
@@ -942,13 +962,11 @@ func (s *Scanner) readAny(t *Type, depth int) (ok bool) {
 		return s.readArray(t)
 	case reflect.Struct:
 		if t.Package() == "reflect" {
-			panic("not implemented: reflection")
+			return s.fail(fmt.Errorf("cannot scan %s values", t))
 		}
 		return s.readStruct(t, 0)
 	case reflect.Func:
 		return s.readFunc(t)
-	case reflect.Chan:
-		panic("not implemented: channels")
 	}
 
 	s.stack = append(s.stack, scanstep{st: scanprimitive})
@@ -970,7 +988,7 @@ func (s *Scanner) readAny(t *Type, depth int) (ok bool) {
 		return s.readString()
 	case reflect.Slice:
 		return s.readSlice()
-	case reflect.Pointer, reflect.UnsafePointer, reflect.Map: // references
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Map, reflect.Chan: // references
 		return s.readRegionPointer()
 	case reflect.Interface:
 		return s.readInterface()
@@ -984,12 +1002,18 @@ func (s *Scanner) readType() (ok bool) {
 	if !ok {
 		return false
 	}
+	if id < 1 || id > int64(s.state.NumType()) {
+		return s.fail(fmt.Errorf("type %d not found", id))
+	}
 	t := s.state.Type(int(id - 1))
 
 	len, ok := s.getVarint()
 	if !ok {
 		return false
 	}
+	if len < -1 {
+		return s.fail(fmt.Errorf("invalid array length %d", len))
+	}
 	if len >= 0 {
 		t = newArrayType(s.state, len, t)
 	}
@@ -998,6 +1022,9 @@ func (s *Scanner) readType() (ok bool) {
 }
 
 func (s *Scanner) readUint8() (ok bool) {
+	if len(s.data)-s.pos < 1 {
+		return s.fail(io.ErrShortBuffer)
+	}
 	s.data1 = uint64(s.data[s.pos])
 	s.pos++
 	return true
@@ -1005,7 +1032,7 @@ func (s *Scanner) readUint8() (ok bool) {
 
 func (s *Scanner) readUint16() (ok bool) {
 	if len(s.data)-s.pos < 2 {
-		return false
+		return s.fail(io.ErrShortBuffer)
 	}
 	s.data1 = uint64(binary.LittleEndian.Uint16(s.data[s.pos:]))
 	s.pos += 2
@@ -1014,7 +1041,7 @@ func (s *Scanner) readUint16() (ok bool) {
 
 func (s *Scanner) readUint32() (ok bool) {
 	if len(s.data)-s.pos < 4 {
-		return false
+		return s.fail(io.ErrShortBuffer)
 	}
 	s.data1 = uint64(binary.LittleEndian.Uint32(s.data[s.pos:]))
 	s.pos += 4
@@ -1023,7 +1050,7 @@ func (s *Scanner) readUint32() (ok bool) {
 
 func (s *Scanner) readUint64() (ok bool) {
 	if len(s.data)-s.pos < 8 {
-		return false
+		return s.fail(io.ErrShortBuffer)
 	}
 	s.data1 = uint64(binary.LittleEndian.Uint64(s.data[s.pos:]))
 	s.pos += 8
@@ -1032,7 +1059,7 @@ func (s *Scanner) readUint64() (ok bool) {
 
 func (s *Scanner) readComplex64() (ok bool) {
 	if len(s.data)-s.pos < 8 {
-		return false
+		return s.fail(io.ErrShortBuffer)
 	}
 	s.data1 = uint64(binary.LittleEndian.Uint32(s.data[s.pos:]))
 	s.data2 = uint64(binary.LittleEndian.Uint32(s.data[s.pos+4:]))
@@ -1042,7 +1069,7 @@ func (s *Scanner) readComplex64() (ok bool) {
 
 func (s *Scanner) readComplex128() (ok bool) {
 	if len(s.data)-s.pos < 16 {
-		return false
+		return s.fail(io.ErrShortBuffer)
 	}
 	s.data1 = binary.LittleEndian.Uint64(s.data[s.pos:])
 	s.data2 = binary.LittleEndian.Uint64(s.data[s.pos+8:])
@@ -1055,6 +1082,9 @@ func (s *Scanner) readString() (ok bool) {
 	if !ok {
 		return ok
 	}
+	if n < 0 {
+		return s.fail(fmt.Errorf("invalid string length %d", n))
+	}
 	s.len = int(n)
 	if s.len == 0 {
 		return true
@@ -1074,6 +1104,9 @@ func (s *Scanner) readSlice() (ok bool) {
 		return ok
 	}
 	s.cap = int(n)
+	if s.len < 0 || s.len > s.cap {
+		return s.fail(fmt.Errorf("invalid slice length %d and capacity %d", s.len, s.cap))
+	}
 
 	return s.readRegionPointer()
 }
@@ -1108,6 +1141,9 @@ func (s *Scanner) readFunc(t *Type) (ok bool) {
 		s.nil = true
 		return true
 	}
+	if id < 0 || id > int64(s.state.NumFunction()) {
+		return s.fail(fmt.Errorf("function %d not found", id))
+	}
 	s.function = s.state.Function(int(id - 1))
 
 	ct := s.function.ClosureType()
@@ -1125,6 +1161,9 @@ func (s *Scanner) readMap() (ok bool) {
 	if !ok {
 		return false
 	}
+	if n < 0 || n > math.MaxInt32 {
+		return s.fail(fmt.Errorf("invalid map size %d", n))
+	}
 	s.len = int(n)
 
 	t := s.src.Type()
@@ -1141,7 +1180,42 @@ func (s *Scanner) readMap() (ok bool) {
 	return true
 }
 
+func (s *Scanner) readChan() (ok bool) {
+	c, ok := s.getVarint()
+	if !ok {
+		return false
+	}
+	if !s.readUint8() {
+		return false
+	}
+	n, ok := s.getVarint()
+	if !ok {
+		return false
+	}
+	if c < 0 || n < 0 || n > c {
+		return s.fail(fmt.Errorf("invalid channel size %d/%d", n, c))
+	}
+	s.cap = int(c)
+	s.len = int(n)
+
+	t := s.src.Type()
+	if len(s.stack) > 0 || t.Kind() != reflect.Chan {
+		panic("unexpected inline channel")
+	}
+
+	s.stack = append(s.stack, scanstep{
+		st:  scanarray,
+		idx: -1,
+		len: int(n),
+		typ: t,
+	})
+	return true
+}
+
 func (s *Scanner) readInterface() (ok bool) {
+	if len(s.data)-s.pos < 1 {
+		return s.fail(io.ErrShortBuffer)
+	}
 	nonNil := s.getBool()
 	if !nonNil {
 		s.nil = true
@@ -1170,6 +1244,9 @@ func (s *Scanner) readRegionPointer() (ok bool) {
 		s.data1 = uint64(offset)
 		return true
 	}
+	if tag < 0 || tag > int64(s.state.NumRegion()) {
+		return s.fail(fmt.Errorf("region %d not found", tag))
+	}
 	s.region = s.state.Region(int(tag - 1))
 
 	offset, ok := s.getVarint()
@@ -1183,13 +1260,11 @@ func (s *Scanner) readRegionPointer() (ok bool) {
 func (s *Scanner) readCustom() (ok bool) {
 	s.custom = true
 	if len(s.data)-s.pos < 8 {
-		s.err = io.ErrShortBuffer
-		return false
+		return s.fail(io.ErrShortBuffer)
 	}
 	size := binary.LittleEndian.Uint64(s.data[s.pos:])
-	if uint64(s.pos)+size > uint64(len(s.data)) {
-		s.err = fmt.Errorf("invalid custom object size")
-		return false
+	if size < 8 || size > uint64(len(s.data)-s.pos) {
+		return s.fail(fmt.Errorf("invalid custom object size"))
 	}
 	s.stack = append(s.stack, scanstep{
 		st:        scancustom,
@@ -1211,7 +1286,7 @@ func (s *Scanner) getVarint() (value int64, ok bool) {
 	var n int
 	value, n = binary.Varint(s.data[s.pos:])
 	if n <= 0 {
-		s.err = io.ErrShortBuffer
+		s.fail(io.ErrShortBuffer)
 		return
 	}
 	s.pos += n
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"encoding"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// DurableMarshaler is implemented by types which control how their values are
+// serialized in the state of durable coroutines.
+//
+// MarshalDurable and UnmarshalDurable are drivers for the [Serializer] and
+// [Deserializer], like the functions registered with [Register]: they invoke
+// [SerializeT] and [DeserializeTo] to serialize and deserialize the values
+// that make up the state of the type, and pointers to the same address are
+// reconstructed as pointing to the same value.
+//
+// UnmarshalDurable must be implemented with a pointer receiver.
+type DurableMarshaler interface {
+	MarshalDurable(s *Serializer) error
+	UnmarshalDurable(d *Deserializer) error
+}
+
+// Types which do not implement DurableMarshaler but implement the
+// encoding.BinaryMarshaler and encoding.BinaryUnmarshaler interfaces, or the
+// gob.GobEncoder and gob.GobDecoder interfaces, are serialized as the bytes
+// produced by their methods. Functions registered with Register for the types,
+// or for interfaces that they implement, take precedence over those methods.
+
+type binaryMarshaler interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+type gobMarshaler interface {
+	gob.GobEncoder
+	gob.GobDecoder
+}
+
+func registerMarshalers(serdes *serdemap) {
+	serdes.attachMarshaler(reflect.TypeFor[DurableMarshaler](), serializeDurableMarshaler, deserializeDurableMarshaler)
+	serdes.attachMarshaler(reflect.TypeFor[binaryMarshaler](), serializeBinaryMarshaler, deserializeBinaryMarshaler)
+	serdes.attachMarshaler(reflect.TypeFor[gobMarshaler](), serializeGobMarshaler, deserializeGobMarshaler)
+}
+
+func serializeDurableMarshaler(s *Serializer, t reflect.Type, p unsafe.Pointer) {
+	m := reflect.NewAt(t, p).Interface().(DurableMarshaler)
+	if err := m.MarshalDurable(s); err != nil {
+		panic(fmt.Errorf("serializing %s: %w", t, err))
+	}
+}
+
+func deserializeDurableMarshaler(d *Deserializer, t reflect.Type, p unsafe.Pointer) {
+	m := reflect.NewAt(t, p).Interface().(DurableMarshaler)
+	if err := m.UnmarshalDurable(d); err != nil {
+		panic(fmt.Errorf("deserializing %s: %w", t, err))
+	}
+}
+
+func serializeBinaryMarshaler(s *Serializer, t reflect.Type, p unsafe.Pointer) {
+	b, err := reflect.NewAt(t, p).Interface().(binaryMarshaler).MarshalBinary()
+	if err != nil {
+		panic(fmt.Errorf("serializing %s: %w", t, err))
+	}
+	SerializeT(s, b)
+}
+
+func deserializeBinaryMarshaler(d *Deserializer, t reflect.Type, p unsafe.Pointer) {
+	var b []byte
+	DeserializeTo(d, &b)
+	if err := reflect.NewAt(t, p).Interface().(binaryMarshaler).UnmarshalBinary(b); err != nil {
+		panic(fmt.Errorf("deserializing %s: %w", t, err))
+	}
+}
+
+func serializeGobMarshaler(s *Serializer, t reflect.Type, p unsafe.Pointer) {
+	b, err := reflect.NewAt(t, p).Interface().(gobMarshaler).GobEncode()
+	if err != nil {
+		panic(fmt.Errorf("serializing %s: %w", t, err))
+	}
+	SerializeT(s, b)
+}
+
+func deserializeGobMarshaler(d *Deserializer, t reflect.Type, p unsafe.Pointer) {
+	var b []byte
+	DeserializeTo(d, &b)
+	if err := reflect.NewAt(t, p).Interface().(gobMarshaler).GobDecode(b); err != nil {
+		panic(fmt.Errorf("deserializing %s: %w", t, err))
+	}
+}
@@ -26,7 +26,10 @@ func serializeType(s *Serializer, t reflect.Type) {
 func deserializeType(d *Deserializer) (reflect.Type, int) {
 	id := deserializeVarint(d)
 	length := deserializeVarint(d)
-	t := d.types.ToReflect(typeid(id))
+	if length < -1 {
+		panic(d.errorf("invalid array length %d", length))
+	}
+	t := d.typeOf(id)
 	return t, length
 }
 
@@ -39,6 +42,11 @@ func serializeAny(s *Serializer, t reflect.Type, p unsafe.Pointer) {
 		return
 	}
 
+	if codec, ok := codecs[t]; ok {
+		codec.ser(s, p)
+		return
+	}
+
 	switch t {
 	case reflectTypeType:
 		serializeType(s, *(*reflect.Type)(p))
@@ -103,7 +111,8 @@ func serializeAny(s *Serializer, t reflect.Type, p unsafe.Pointer) {
 		serializeStruct(s, t, p)
 	case reflect.Func:
 		serializeFunc(s, t, p)
-	// Chan
+	case reflect.Chan:
+		serializeChan(s, t, p)
 	default:
 		panic(fmt.Errorf("reflection cannot serialize type %s", t))
 	}
@@ -111,8 +120,28 @@ func serializeAny(s *Serializer, t reflect.Type, p unsafe.Pointer) {
 
 func deserializeAny(d *Deserializer, t reflect.Type, p unsafe.Pointer) {
 	if serde, ok := d.serdes.serdeByType(t); ok {
-		d.b = d.b[8:] // skip size prefix
+		size := binary.LittleEndian.Uint64(d.read(8))
+		if size < 8 || size-8 > uint64(len(d.b)) {
+			panic(d.errorf("invalid size %d of %s", size, t))
+		}
+		end := len(d.b) - int(size-8)
 		serde.des(d, t, p)
+		if len(d.b) != end {
+			panic(d.errorf("%s was not decoded from %d bytes", t, size))
+		}
+		return
+	}
+
+	if plan, ok := d.types.plans[t]; ok {
+		plan.decode(d, p)
+		return
+	}
+
+	if codec, ok := codecs[t]; ok {
+		codec.des(d, p)
+		for _, f := range codec.transient {
+			reinitTransient(f, p)
+		}
 		return
 	}
 
@@ -190,6 +219,8 @@ func deserializeAny(d *Deserializer, t reflect.Type, p unsafe.Pointer) {
 		deserializeStruct(d, t, p)
 	case reflect.Func:
 		deserializeFunc(d, t, p)
+	case reflect.Chan:
+		deserializeChan(d, t, p)
 	default:
 		panic(fmt.Errorf("reflection cannot deserialize type %s", t))
 	}
@@ -265,6 +296,9 @@ func serializeReflectValue(s *Serializer, t reflect.Type, v reflect.Value) {
 		}
 	case reflect.Pointer:
 		serializePointedAt(s, t.Elem(), -1, v.UnsafePointer())
+	case reflect.Chan:
+		c := v.UnsafePointer()
+		serializeChan(s, t, unsafe.Pointer(&c))
 	default:
 		panic(fmt.Sprintf("not implemented: serializing reflect.Value with type %s (%s)", t, t.Kind()))
 	}
@@ -339,6 +373,7 @@ func deserializeReflectValue(d *Deserializer, t reflect.Type) (v reflect.Value)
 		deserializeString(d, &value)
 		v = reflect.ValueOf(value)
 	case reflect.Array:
+		d.allocate(t.Size(), 1)
 		v = reflect.New(t).Elem()
 		deserializeArray(d, t, unsafe.Pointer(v.UnsafeAddr()))
 	case reflect.Slice:
@@ -351,6 +386,7 @@ func deserializeReflectValue(d *Deserializer, t reflect.Type) (v reflect.Value)
 		var p uintptr // FIXME: what should this be?
 		deserializeMapReflect(d, t, v, unsafe.Pointer(&p))
 	case reflect.Struct:
+		d.allocate(t.Size(), 1)
 		v = reflect.New(t).Elem()
 		for i := 0; i < t.NumField(); i++ {
 			fv := deserializeReflectValue(d, t.Field(i).Type)
@@ -368,6 +404,9 @@ func deserializeReflectValue(d *Deserializer, t reflect.Type) (v reflect.Value)
 		ep := deserializePointedAt(d, t.Elem(), -1)
 		v = reflect.New(t).Elem()
 		v.Set(reflect.NewAt(t.Elem(), ep))
+	case reflect.Chan:
+		v = reflect.New(t).Elem()
+		deserializeChan(d, t, unsafe.Pointer(v.UnsafeAddr()))
 	default:
 		panic(fmt.Sprintf("not implemented: deserializing reflect.Value with type %s", t))
 	}
@@ -412,6 +451,10 @@ func serializePointedAt(s *Serializer, et reflect.Type, length int, p unsafe.Poi
 		serializeMap(s, r.typ, r.addr)
 		return
 	}
+	if r.len < 0 && r.typ.Kind() == reflect.Chan {
+		serializeChan(s, r.typ, r.addr)
+		return
+	}
 
 	id, new := s.assignPointerID(r.addr)
 	serializeVarint(s, int(id))
@@ -458,12 +501,17 @@ func deserializePointedAt(d *Deserializer, t reflect.Type, length int) unsafe.Po
 	// instead of taking an unsafe.Pointer as an input, it returns an
 	// unsafe.Pointer to a deserialized object.
 
-	if length < 0 && t.Kind() == reflect.Map {
+	if length < 0 && t != nil && t.Kind() == reflect.Map {
 		m := reflect.New(t)
 		p := m.UnsafePointer()
 		deserializeMapReflect(d, t, m.Elem(), m.UnsafePointer())
 		return p
 	}
+	if length < 0 && t != nil && t.Kind() == reflect.Chan {
+		p := reflect.New(t).UnsafePointer()
+		deserializeChan(d, t, p)
+		return p
+	}
 
 	id := deserializeVarint(d)
 	if id == 0 {
@@ -474,47 +522,61 @@ func deserializePointedAt(d *Deserializer, t reflect.Type, length int) unsafe.Po
 	offset := deserializeVarint(d)
 	if id == -1 {
 		// Pointer into static uint64 table.
+		if !validPointer(staticType, -1, offset, t, length) || (t != nil && hasPointers(t)) {
+			panic(d.errorf("invalid static pointer to %s at offset %d", t, offset))
+		}
 		return staticPointer(offset)
 	}
 
+	region, regionType, regionLength := d.regionOf(id)
+
 	p := d.ptrs[sID(id)]
 	if p == nil {
 		// Deserialize the region.
-		if int(id) > len(d.regions) {
-			panic(fmt.Sprintf("region %d not found", id))
-		}
-		region := d.regions[id-1]
+		regionDeser := d.fork(id-1, region.Data)
 
-		regionType := d.types.ToReflect(typeid(region.Type >> 1))
-
-		if region.Type&1 == 1 {
+		if regionLength >= 0 {
 			elemSize := int(regionType.Size())
-			length := int(region.ArrayLength)
-			data := make([]byte, elemSize*length)
-			p = unsafe.Pointer(unsafe.SliceData(data))
+			d.allocate(regionType.Size(), regionLength)
+			p = reflect.MakeSlice(reflect.SliceOf(regionType), regionLength, regionLength).UnsafePointer()
 			d.store(sID(id), p)
 
 			// Fast path for byte arrays.
 			if regionType.Kind() == reflect.Uint8 {
-				if length > 0 {
-					copy(unsafe.Slice((*byte)(p), length), region.Data)
-				}
-			} else {
-				regionDeser := d.fork(region.Data)
-				for i := 0; i < length; i++ {
-					deserializeAny(regionDeser, regionType, unsafe.Add(p, elemSize*i))
-				}
+				copy(unsafe.Slice((*byte)(p), regionLength), regionDeser.read(regionLength))
 			}
+			regionDeser.decode(func() {
+				if regionType.Kind() != reflect.Uint8 {
+					for i := 0; i < regionLength; i++ {
+						deserializeAny(regionDeser, regionType, unsafe.Add(p, elemSize*i))
+					}
+				}
+			})
 		} else {
+			d.allocate(regionType.Size(), 1)
 			container := reflect.New(regionType)
 			p = container.UnsafePointer()
 			d.store(sID(id), p)
-			regionDeser := d.fork(region.Data)
-			deserializeAny(regionDeser, regionType, p)
+			regionDeser.decode(func() {
+				deserializeAny(regionDeser, regionType, p)
+			})
 		}
 
 	}
 
+	if len(d.types.plans) > 0 {
+		// The layout of the region may have changed, see structPlan.
+		if region.Type&1 == 1 {
+			offset = d.types.translateArray(typeid(region.Type>>1), regionType, offset)
+		} else {
+			offset = d.types.translate(typeid(region.Type>>1), regionType, offset)
+		}
+	}
+
+	if !validPointer(regionType, regionLength, offset, t, length) {
+		panic(d.errorf("invalid pointer to %s at offset %d of region %d", t, offset, id-1))
+	}
+
 	// Create the pointer with an offset into the container.
 	return unsafe.Add(p, offset)
 }
@@ -577,6 +639,7 @@ func deserializeMapReflect(d *Deserializer, t reflect.Type, r reflect.Value, p u
 	}
 
 	_ = deserializeVarint(d) // offset
+	region := d.referenceRegion(id, t)
 
 	ptr := d.ptrs[sID(id)]
 	if ptr != nil {
@@ -585,28 +648,113 @@ func deserializeMapReflect(d *Deserializer, t reflect.Type, r reflect.Value, p u
 		return
 	}
 
-	if id > len(d.regions) {
-		panic(fmt.Sprintf("region %d not found", id))
+	regionDeser := d.fork(id-1, region.Data)
+	regionDeser.decode(func() {
+		n := deserializeVarint(regionDeser)
+		if n < 0 { // nil map
+			panic(regionDeser.errorf("invalid map size %d", n))
+		}
+		regionDeser.allocate(t.Key().Size()+t.Elem().Size(), n)
+
+		// Entries hold at least one byte, unless they are empty and
+		// the map has a single entry.
+		nv := reflect.MakeMapWithSize(t, min(n, len(regionDeser.b)))
+		r.Set(nv)
+		d.store(sID(id), p)
+		for i := 0; i < n; i++ {
+			k := reflect.New(t.Key())
+			deserializeAny(regionDeser, t.Key(), k.UnsafePointer())
+			v := reflect.New(t.Elem())
+			deserializeAny(regionDeser, t.Elem(), v.UnsafePointer())
+			r.SetMapIndex(k.Elem(), v.Elem())
+		}
+	})
+}
+
+// Channels are reference types like maps: the first reference to a channel
+// creates a region holding its capacity, closed flag and buffered elements,
+// and other references to the channel point to that region. The direction of
+// the channel is part of its type.
+func serializeChan(s *Serializer, t reflect.Type, p unsafe.Pointer) {
+	c := *(*unsafe.Pointer)(p)
+	if c == nil {
+		serializeVarint(s, 0)
+		return
 	}
-	region := d.regions[id-1]
 
-	regionDeser := d.fork(region.Data)
+	id, new := s.assignPointerID(c)
+	serializeVarint(s, int(id))
+	serializeVarint(s, 0) // offset, for compat with other region references
 
-	n := deserializeVarint(regionDeser)
-	if n < 0 { // nil map
-		panic("invalid map size")
+	if !new {
+		return
+	}
+
+	region := &coroutinev1.Region{
+		Type: s.types.ToType(t) << 1,
 	}
+	s.regions = append(s.regions, region)
 
-	nv := reflect.MakeMapWithSize(t, n)
-	r.Set(nv)
-	d.store(sID(id), p)
+	h := (*hchan)(c)
+	et := t.Elem()
+	n := h.len()
+
+	regionSer := s.fork()
+	serializeVarint(regionSer, int(h.dataqsiz))
+	serializeBool(regionSer, h.isClosed())
+	serializeVarint(regionSer, n)
 	for i := 0; i < n; i++ {
-		k := reflect.New(t.Key())
-		deserializeAny(regionDeser, t.Key(), k.UnsafePointer())
-		v := reflect.New(t.Elem())
-		deserializeAny(regionDeser, t.Elem(), v.UnsafePointer())
-		r.SetMapIndex(k.Elem(), v.Elem())
+		serializeAny(regionSer, et, h.elem(i, et.Size()))
 	}
+
+	region.Data = regionSer.b
+}
+
+func deserializeChan(d *Deserializer, t reflect.Type, p unsafe.Pointer) {
+	r := reflect.NewAt(t, p).Elem()
+
+	id := deserializeVarint(d)
+	if id == 0 {
+		r.SetZero()
+		return
+	}
+
+	_ = deserializeVarint(d) // offset
+	region := d.referenceRegion(id, t)
+
+	ptr := d.ptrs[sID(id)]
+	if ptr != nil {
+		existing := reflect.NewAt(t, ptr).Elem()
+		r.Set(existing)
+		return
+	}
+
+	regionDeser := d.fork(id-1, region.Data)
+	regionDeser.decode(func() {
+		capacity := deserializeVarint(regionDeser)
+		var closed bool
+		deserializeBool(regionDeser, &closed)
+		n := deserializeVarint(regionDeser)
+		if capacity < 0 || n < 0 || n > capacity {
+			panic(regionDeser.errorf("invalid channel size %d/%d", n, capacity))
+		}
+
+		// Channels can only be created with both directions, and converted
+		// to the directional type of the value afterwards.
+		et := t.Elem()
+		regionDeser.allocate(et.Size(), capacity)
+		c := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, et), capacity)
+		r.Set(c.Convert(t))
+		d.store(sID(id), p)
+		for i := 0; i < n; i++ {
+			v := reflect.New(et)
+			deserializeAny(regionDeser, et, v.UnsafePointer())
+			c.Send(v.Elem())
+		}
+		if closed {
+			c.Close()
+		}
+	})
 }
 
 func serializeSlice(s *Serializer, t reflect.Type, p unsafe.Pointer) {
@@ -620,6 +768,9 @@ func serializeSlice(s *Serializer, t reflect.Type, p unsafe.Pointer) {
 func deserializeSlice(d *Deserializer, t reflect.Type, p unsafe.Pointer) {
 	l := deserializeVarint(d)
 	c := deserializeVarint(d)
+	if l < 0 || l > c {
+		panic(d.errorf("invalid slice length %d and capacity %d", l, c))
+	}
 
 	ar := deserializePointedAt(d, t.Elem(), c)
 	if ar == nil {
@@ -645,6 +796,11 @@ func serializeArray(s *Serializer, t reflect.Type, p unsafe.Pointer) {
 func deserializeArray(d *Deserializer, t reflect.Type, p unsafe.Pointer) {
 	size := int(t.Elem().Size())
 	te := t.Elem()
+	if size == 0 {
+		// No memory is allocated, but the time spent decoding
+		// the elements must be bounded.
+		d.allocate(0, t.Len())
+	}
 	for i := 0; i < t.Len(); i++ {
 		pe := unsafe.Add(p, size*i)
 		deserializeAny(d, te, pe)
@@ -676,7 +832,7 @@ var unsafePointerType = reflect.TypeOf(unsafe.Pointer(nil))
 func deserializeUnsafePointer(d *Deserializer, p unsafe.Pointer) {
 	r := reflect.NewAt(unsafePointerType, p)
 
-	ep := deserializePointedAt(d, unsafePointerType, -1)
+	ep := deserializePointedAt(d, nil, -1)
 	if ep != nil {
 		r.Elem().Set(reflect.ValueOf(ep))
 	}
@@ -693,6 +849,9 @@ func deserializeStruct(d *Deserializer, t reflect.Type, p unsafe.Pointer) {
 func serializeStructFields(s *Serializer, p unsafe.Pointer, n int, field func(int) reflect.StructField) {
 	for i := 0; i < n; i++ {
 		ft := field(i)
+		if transient(ft.Tag) {
+			continue
+		}
 		fp := unsafe.Add(p, ft.Offset)
 		serializeAny(s, ft.Type, fp)
 	}
@@ -701,6 +860,10 @@ func serializeStructFields(s *Serializer, p unsafe.Pointer, n int, field func(in
 func deserializeStructFields(d *Deserializer, p unsafe.Pointer, n int, field func(int) reflect.StructField) {
 	for i := 0; i < n; i++ {
 		ft := field(i)
+		if transient(ft.Tag) {
+			reinitTransient(ft, p)
+			continue
+		}
 		fp := unsafe.Add(p, ft.Offset)
 		deserializeAny(d, ft.Type, fp)
 	}
@@ -738,7 +901,7 @@ func deserializeFunc(d *Deserializer, t reflect.Type, p unsafe.Pointer) {
 		panic(fn.Name + ": function type is missing")
 	}
 	if !t.AssignableTo(fn.Type) {
-		panic(fn.Name + ": function type mismatch: " + fn.Type.String() + " != " + t.String())
+		panic(invalid("%s: function type mismatch: %s != %s", fn.Name, fn.Type, t))
 	}
 
 	if fn.Closure != nil {
@@ -839,8 +1002,14 @@ func deserializeString(d *Deserializer, x *string) {
 	if l == 0 {
 		return
 	}
+	if l < 0 {
+		panic(d.errorf("invalid string length %d", l))
+	}
 
 	ar := deserializePointedAt(d, byteT, l)
+	if ar == nil {
+		panic(d.errorf("nil string data"))
+	}
 
 	*x = unsafe.String((*byte)(ar), l)
 }
@@ -854,8 +1023,7 @@ func serializeBool(s *Serializer, x bool) {
 }
 
 func deserializeBool(d *Deserializer, x *bool) {
-	*x = d.b[0] == 1
-	d.b = d.b[1:]
+	*x = d.read(1)[0] == 1
 }
 
 func serializeInt(s *Serializer, x int) {
@@ -863,8 +1031,7 @@ func serializeInt(s *Serializer, x int) {
 }
 
 func deserializeInt(d *Deserializer, x *int) {
-	*x = int(binary.LittleEndian.Uint64(d.b[:8]))
-	d.b = d.b[8:]
+	*x = int(binary.LittleEndian.Uint64(d.read(8)))
 }
 
 func serializeInt64(s *Serializer, x int64) {
@@ -872,8 +1039,7 @@ func serializeInt64(s *Serializer, x int64) {
 }
 
 func deserializeInt64(d *Deserializer, x *int64) {
-	*x = int64(binary.LittleEndian.Uint64(d.b[:8]))
-	d.b = d.b[8:]
+	*x = int64(binary.LittleEndian.Uint64(d.read(8)))
 }
 
 func serializeInt32(s *Serializer, x int32) {
@@ -881,8 +1047,7 @@ func serializeInt32(s *Serializer, x int32) {
 }
 
 func deserializeInt32(d *Deserializer, x *int32) {
-	*x = int32(binary.LittleEndian.Uint32(d.b[:4]))
-	d.b = d.b[4:]
+	*x = int32(binary.LittleEndian.Uint32(d.read(4)))
 }
 
 func serializeInt16(s *Serializer, x int16) {
@@ -890,8 +1055,7 @@ func serializeInt16(s *Serializer, x int16) {
 }
 
 func deserializeInt16(d *Deserializer, x *int16) {
-	*x = int16(binary.LittleEndian.Uint16(d.b[:2]))
-	d.b = d.b[2:]
+	*x = int16(binary.LittleEndian.Uint16(d.read(2)))
 }
 
 func serializeInt8(s *Serializer, x int8) {
@@ -899,8 +1063,7 @@ func serializeInt8(s *Serializer, x int8) {
 }
 
 func deserializeInt8(d *Deserializer, x *int8) {
-	*x = int8(d.b[0])
-	d.b = d.b[1:]
+	*x = int8(d.read(1)[0])
 }
 
 func serializeUint(s *Serializer, x uint) {
@@ -908,8 +1071,7 @@ func serializeUint(s *Serializer, x uint) {
 }
 
 func deserializeUint(d *Deserializer, x *uint) {
-	*x = uint(binary.LittleEndian.Uint64(d.b[:8]))
-	d.b = d.b[8:]
+	*x = uint(binary.LittleEndian.Uint64(d.read(8)))
 }
 
 func serializeUint64(s *Serializer, x uint64) {
@@ -917,8 +1079,7 @@ func serializeUint64(s *Serializer, x uint64) {
 }
 
 func deserializeUint64(d *Deserializer, x *uint64) {
-	*x = uint64(binary.LittleEndian.Uint64(d.b[:8]))
-	d.b = d.b[8:]
+	*x = uint64(binary.LittleEndian.Uint64(d.read(8)))
 }
 
 func serializeUint32(s *Serializer, x uint32) {
@@ -926,8 +1087,7 @@ func serializeUint32(s *Serializer, x uint32) {
 }
 
 func deserializeUint32(d *Deserializer, x *uint32) {
-	*x = uint32(binary.LittleEndian.Uint32(d.b[:4]))
-	d.b = d.b[4:]
+	*x = uint32(binary.LittleEndian.Uint32(d.read(4)))
 }
 
 func serializeUint16(s *Serializer, x uint16) {
@@ -935,8 +1095,7 @@ func serializeUint16(s *Serializer, x uint16) {
 }
 
 func deserializeUint16(d *Deserializer, x *uint16) {
-	*x = uint16(binary.LittleEndian.Uint16(d.b[:2]))
-	d.b = d.b[2:]
+	*x = uint16(binary.LittleEndian.Uint16(d.read(2)))
 }
 
 func serializeUint8(s *Serializer, x uint8) {
@@ -944,8 +1103,7 @@ func serializeUint8(s *Serializer, x uint8) {
 }
 
 func deserializeUint8(d *Deserializer, x *uint8) {
-	*x = uint8(d.b[0])
-	d.b = d.b[1:]
+	*x = uint8(d.read(1)[0])
 }
 
 func serializeUintptr(s *Serializer, x uintptr) {
@@ -0,0 +1,404 @@
+package types
+
+// resolve.go contains the symbolic resolution of types, which allows states
+// serialized by other builds of the program to be deserialized. Types are
+// normally resolved by their offset in the memory of the program, which is
+// only valid for the build that serialized the state. In cross-build mode,
+// named types are resolved by their package path and name among the types
+// registered by the program, and the layout recorded in the state is compared
+// to the layout of the types of the program, so that values are only decoded
+// if their representation did not change.
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+
+	coroutinev1 "github.com/dispatchrun/coroutine/gen/proto/go/coroutine/v1"
+)
+
+// ErrIncompatibleState is an error that occurs when a program deserializes a
+// state from another build, which references functions or types that do not
+// exist in the program or which changed in incompatible ways.
+var ErrIncompatibleState = errors.New("incompatible state")
+
+// DeserializeOption is an option for [Deserialize].
+type DeserializeOption func(*deserializeOptions)
+
+type deserializeOptions struct {
+	crossBuild    bool
+	maxAllocation int
+}
+
+// CrossBuild returns an option which allows [Deserialize] to decode states
+// serialized by another build of the program.
+//
+// Functions are resolved by name, and named types by package path and name
+// among the types reachable from the functions, closures, codecs and
+// serializers registered by the program, or from the types registered with
+// [RegisterType], or indexed by the linker. Deserialization fails with
+// [ErrIncompatibleState] if a function or type does not exist anymore, if the
+// signature of a function or the layout of a closure changed, or if the
+// layout of a type changed.
+//
+// The state must still have been produced for the same operating system and
+// architecture.
+func CrossBuild() DeserializeOption {
+	return func(o *deserializeOptions) { o.crossBuild = true }
+}
+
+// RegisterType registers T and the types reachable from it, so that values of
+// those types can be deserialized from the states of other builds (see
+// [CrossBuild]).
+//
+// The types that coroutines hold in their frames and closures are registered
+// by the code that coroc generates, and the types of values stored in
+// interfaces are usually found in the type index of the program. Types which
+// the program never refers to through pointers or composite types may need
+// to be registered explicitly.
+func RegisterType[T any]() {
+	registry.add(reflect.TypeFor[T]())
+}
+
+var registry = newTypeRegistry()
+
+// typeRegistry indexes the types known to the program for symbolic
+// resolution.
+type typeRegistry struct {
+	typelinks sync.Once
+
+	mutex sync.Mutex
+	seen  map[reflect.Type]struct{}
+	// Named types by package path and name. Types declared in function
+	// bodies may share the same name in a package.
+	named map[string][]reflect.Type
+	// Unnamed types that cannot be constructed with the reflect package:
+	// interfaces, and structs with unexported fields.
+	unnamed []reflect.Type
+}
+
+func newTypeRegistry() *typeRegistry {
+	r := &typeRegistry{
+		seen:  map[reflect.Type]struct{}{},
+		named: map[string][]reflect.Type{},
+	}
+	for _, t := range []reflect.Type{
+		reflect.TypeFor[bool](),
+		reflect.TypeFor[int](),
+		reflect.TypeFor[int8](),
+		reflect.TypeFor[int16](),
+		reflect.TypeFor[int32](),
+		reflect.TypeFor[int64](),
+		reflect.TypeFor[uint](),
+		reflect.TypeFor[uint8](),
+		reflect.TypeFor[uint16](),
+		reflect.TypeFor[uint32](),
+		reflect.TypeFor[uint64](),
+		reflect.TypeFor[uintptr](),
+		reflect.TypeFor[float32](),
+		reflect.TypeFor[float64](),
+		reflect.TypeFor[complex64](),
+		reflect.TypeFor[complex128](),
+		reflect.TypeFor[string](),
+		reflect.TypeFor[error](),
+		reflect.TypeFor[unsafe.Pointer](),
+	} {
+		r.add(t)
+	}
+	return r
+}
+
+func (r *typeRegistry) add(t reflect.Type) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.walk(t)
+}
+
+func (r *typeRegistry) walk(t reflect.Type) {
+	if t == nil {
+		return
+	}
+	if _, ok := r.seen[t]; ok {
+		return
+	}
+	r.seen[t] = struct{}{}
+
+	if t.Name() != "" {
+		name := typeName(t.PkgPath(), t.Name())
+		r.named[name] = append(r.named[name], t)
+	} else if t.Kind() == reflect.Interface || (t.Kind() == reflect.Struct && hasUnexportedFields(t)) {
+		r.unnamed = append(r.unnamed, t)
+	}
+
+	switch t.Kind() {
+	case reflect.Array, reflect.Chan, reflect.Pointer, reflect.Slice:
+		r.walk(t.Elem())
+	case reflect.Map:
+		r.walk(t.Key())
+		r.walk(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			r.walk(t.Field(i).Type)
+		}
+	case reflect.Func:
+		for i := 0; i < t.NumIn(); i++ {
+			r.walk(t.In(i))
+		}
+		for i := 0; i < t.NumOut(); i++ {
+			r.walk(t.Out(i))
+		}
+	case reflect.Interface:
+		for i := 0; i < t.NumMethod(); i++ {
+			r.walk(t.Method(i).Type)
+		}
+	}
+}
+
+// typelinks returns the types that the linker indexed for the reflect
+// package, which are the unnamed composite types of the program, including
+// the pointers to named types.
+//
+//go:linkname typelinks reflect.typelinks
+func typelinks() (sections []unsafe.Pointer, offsets [][]int32)
+
+// addTypelinks adds the types reachable from the types indexed by the linker,
+// which covers the types of values that the program only stores in
+// interfaces.
+func (r *typeRegistry) addTypelinks() {
+	sections, offsets := typelinks()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for i, section := range sections {
+		for _, offset := range offsets[i] {
+			r.walk(typeForPointer(unsafe.Add(section, offset)))
+		}
+	}
+}
+
+// contains returns true if t is a type of the program. Types are compared by
+// the address of their descriptor, so t may be an invalid reflect.Type which
+// must not be used if the method returns false.
+func (r *typeRegistry) contains(t reflect.Type) bool {
+	r.typelinks.Do(r.addTypelinks)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	_, ok := r.seen[t]
+	return ok
+}
+
+func (r *typeRegistry) lookupNamed(name string) []reflect.Type {
+	r.typelinks.Do(r.addTypelinks)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.named[name]
+}
+
+func (r *typeRegistry) lookupUnnamed(kind reflect.Kind) (types []reflect.Type) {
+	r.typelinks.Do(r.addTypelinks)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, t := range r.unnamed {
+		if t.Kind() == kind {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+func typeName(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+func hasUnexportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if !t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+func incompatible(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrIncompatibleState, fmt.Sprintf(format, args...))
+}
+
+// resolve returns the type of the program that a type recorded in the state
+// of another build refers to. It is called for the types which are not
+// reconstructed from their description: named types, interfaces and structs
+// with unexported fields.
+func (m *typemap) resolve(id typeid, t *coroutinev1.Type) reflect.Type {
+	name := m.strings.Lookup(t.Name)
+	pkg := m.strings.Lookup(t.Package)
+
+	if t.CustomSerializer > 0 && name == "" {
+		// Custom serializers of unnamed types can only be found by ID,
+		// which depends on the order in which they were registered.
+		s := m.serdes.serdeByID(serdeid(t.CustomSerializer))
+		if kindOf(s.typ.Kind()) != t.Kind {
+			panic(incompatible("type with custom serializer %d not found", t.CustomSerializer))
+		}
+		return s.typ
+	}
+
+	if t.CustomSerializer > 0 && t.Kind == coroutinev1.Kind_KIND_POINTER {
+		// The type is a pointer to a named type (see ToType).
+		for _, x := range registry.lookupNamed(typeName(pkg, name)) {
+			if _, ok := m.serdes.serdeByType(reflect.PointerTo(x)); ok {
+				return reflect.PointerTo(x)
+			}
+		}
+		panic(incompatible("type *%s not found", typeName(pkg, name)))
+	}
+
+	if name != "" {
+		candidates := registry.lookupNamed(typeName(pkg, name))
+		if len(candidates) == 0 {
+			panic(incompatible("type %s not found", typeName(pkg, name)))
+		}
+		var err error
+		for _, x := range candidates {
+			// Types may refer to themselves, add the type to the cache
+			// before comparing the layouts.
+			m.cache.add(id, x)
+			if err = m.matchLayout(t, x); err == nil {
+				return x
+			}
+			m.cache.remove(id, x)
+		}
+		panic(incompatible("type %s changed: %v", typeName(pkg, name), err))
+	}
+
+	if t.Kind == coroutinev1.Kind_KIND_INTERFACE && len(t.Fields) == 0 {
+		return typeof[interface{}]()
+	}
+	for _, x := range m.unnamedCandidates(t.Kind) {
+		if m.matchLayout(t, x) == nil {
+			return x
+		}
+	}
+	panic(incompatible("no %s type of the program matches the state", t.Kind))
+}
+
+func (m *typemap) unnamedCandidates(kind coroutinev1.Kind) []reflect.Type {
+	switch kind {
+	case coroutinev1.Kind_KIND_INTERFACE:
+		return registry.lookupUnnamed(reflect.Interface)
+	case coroutinev1.Kind_KIND_STRUCT:
+		return registry.lookupUnnamed(reflect.Struct)
+	default:
+		return nil
+	}
+}
+
+// match returns an error if values of the type recorded with id in the state
+// cannot be decoded as values of type x.
+func (m *typemap) match(id typeid, x reflect.Type) error {
+	t := m.lookup(id)
+	if t == nil {
+		return fmt.Errorf("type %d not found", id)
+	}
+	if t.Name != 0 {
+		if rt := m.ToReflect(id); rt != x {
+			return fmt.Errorf("type %s is now %s", rt, x)
+		}
+		return nil
+	}
+	return m.matchLayout(t, x)
+}
+
+// matchLayout returns an error if the layout of a type recorded in the state
+// differs from the layout of type x.
+func (m *typemap) matchLayout(t *coroutinev1.Type, x reflect.Type) error {
+	if kind := kindOf(x.Kind()); kind != t.Kind {
+		return fmt.Errorf("kind %s is now %s", t.Kind, kind)
+	}
+	if t.CustomSerializer > 0 {
+		// The representation of values is opaque.
+		if _, ok := m.serdes.serdeByType(x); !ok {
+			return fmt.Errorf("%s has no custom serializer", x)
+		}
+		return nil
+	}
+
+	switch x.Kind() {
+	case reflect.Array:
+		if int(t.Length) != x.Len() {
+			return fmt.Errorf("array length %d is now %d", t.Length, x.Len())
+		}
+		return m.match(typeid(t.Elem), x.Elem())
+
+	case reflect.Pointer, reflect.Slice:
+		return m.match(typeid(t.Elem), x.Elem())
+
+	case reflect.Chan:
+		return m.match(typeid(t.Elem), x.Elem())
+
+	case reflect.Map:
+		if err := m.match(typeid(t.Key), x.Key()); err != nil {
+			return err
+		}
+		return m.match(typeid(t.Elem), x.Elem())
+
+	case reflect.Func:
+		if len(t.Params) != x.NumIn() || len(t.Results) != x.NumOut() || t.Variadic != x.IsVariadic() {
+			return fmt.Errorf("signature changed to %s", x)
+		}
+		for i, p := range t.Params {
+			if err := m.match(typeid(p), x.In(i)); err != nil {
+				return err
+			}
+		}
+		for i, r := range t.Results {
+			if err := m.match(typeid(r), x.Out(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		return m.matchStruct(t, x)
+
+	case reflect.Interface:
+		if len(t.Fields) != x.NumMethod() {
+			return fmt.Errorf("interface has %d methods, now %d", len(t.Fields), x.NumMethod())
+		}
+		for i, f := range t.Fields {
+			method := x.Method(i)
+			name := m.strings.Lookup(f.Name)
+			if name != method.Name || m.strings.Lookup(f.Package) != method.PkgPath {
+				return fmt.Errorf("method %s is now %s", name, method.Name)
+			}
+			if err := m.match(typeid(f.Type), method.Type); err != nil {
+				return fmt.Errorf("method %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveFunc returns the function of the program that a function recorded in
+// the state of another build refers to, checking that its signature and the
+// layout of its closure did not change.
+func (m *funcmap) resolveFunc(cf *coroutinev1.Function, f *Func) error {
+	if f.Type == nil {
+		return fmt.Errorf("type information not registered for function %s", f.Name)
+	}
+	if err := m.types.match(typeid(cf.Type), f.Type); err != nil {
+		return fmt.Errorf("function %s: %w", f.Name, err)
+	}
+	switch {
+	case cf.Closure == 0 && f.Closure == nil:
+	case cf.Closure == 0 || f.Closure == nil:
+		return fmt.Errorf("function %s: closure changed", f.Name)
+	default:
+		if err := m.types.match(typeid(cf.Closure), f.Closure); err != nil {
+			return fmt.Errorf("closure %s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Resources are values which cannot be serialized, such as files, network
+// connections or database handles, but which can be reacquired when the state
+// is deserialized. A resource is serialized as a reference to the resource
+// type, identified by the name it was registered with, and the parameters
+// needed to reacquire the resource.
+//
+// Resources referenced multiple times in the state are serialized once: the
+// first reference carries the name and parameters, the others only refer to
+// the first by index. Index zero is used for nil resources.
+
+type resource struct {
+	typ    reflect.Type
+	reopen func(*Deserializer) (any, error)
+}
+
+// Global register of resource types, by name.
+var resources = map[string]resource{}
+
+// RegisterResource registers T as a resource type named name.
+//
+// Values of type T are serialized as the name of the resource type and the
+// parameters returned by params, which must be serializable. On
+// deserialization, the values are reacquired by calling reopen with the
+// parameters. Errors returned by params or reopen abort the serialization or
+// deserialization.
+//
+// T may be an interface type, such as net.Conn, in which case all the values
+// implementing the interface are serialized as resources; reopen must then
+// return values of the same concrete type as the values which were serialized.
+//
+// The name identifies the resource type in serialized states; it should be
+// stable across builds of the program, and is usually the name of the package
+// and of the type, for example "os.File".
+func RegisterResource[T, P any](name string, params func(T) (P, error), reopen func(P) (T, error)) {
+	t := reflect.TypeFor[T]()
+	if r, ok := resources[name]; ok && r.typ != t {
+		panic(fmt.Sprintf("resource %q is already registered for type %s", name, r.typ))
+	}
+	resources[name] = resource{
+		typ: t,
+		reopen: func(d *Deserializer) (any, error) {
+			var p P
+			DeserializeTo(d, &p)
+			return reopen(p)
+		},
+	}
+
+	Register[T](
+		func(s *Serializer, x *T) error {
+			if reflect.ValueOf(x).Elem().IsZero() {
+				SerializeT(s, 0) // nil resource
+				return nil
+			}
+			if i, ok := s.resourceIndex(*x); ok {
+				SerializeT(s, i)
+				return nil
+			}
+			p, err := params(*x)
+			if err != nil {
+				return fmt.Errorf("resource %s: %w", name, err)
+			}
+			SerializeT(s, s.addResource(*x))
+			SerializeT(s, name)
+			SerializeT(s, p)
+			return nil
+		},
+		func(d *Deserializer, x *T) error {
+			v, err := d.reopenResource()
+			if err != nil || v == nil {
+				return err
+			}
+			r, ok := v.(T)
+			if !ok {
+				return fmt.Errorf("resource %s has type %T, not %s", name, v, t)
+			}
+			*x = r
+			return nil
+		},
+	)
+}
+
+// resourceIndex returns the index of a resource already serialized.
+func (s *Serializer) resourceIndex(v any) (int, bool) {
+	if !reflect.ValueOf(v).Comparable() {
+		return 0, false
+	}
+	i, ok := s.resources.byValue[v]
+	return -i, ok
+}
+
+// addResource assigns an index to a resource. Indexes start at 1; the first
+// reference to a resource is written with its index, and the others with the
+// negated index.
+func (s *Serializer) addResource(v any) int {
+	s.resources.count++
+	i := s.resources.count
+	if reflect.ValueOf(v).Comparable() {
+		if s.resources.byValue == nil {
+			s.resources.byValue = make(map[any]int)
+		}
+		s.resources.byValue[v] = i
+	}
+	return i
+}
+
+func (d *Deserializer) reopenResource() (any, error) {
+	var i int
+	DeserializeTo(d, &i)
+	if i == 0 {
+		return nil, nil
+	}
+	if i < 0 {
+		v, ok := d.resources[-i]
+		if !ok {
+			return nil, fmt.Errorf("resource %d not found", -i)
+		}
+		return v, nil
+	}
+
+	var name string
+	DeserializeTo(d, &name)
+	r, ok := resources[name]
+	if !ok {
+		return nil, fmt.Errorf("resource %q is not registered", name)
+	}
+	v, err := r.reopen(d)
+	if err != nil {
+		return nil, fmt.Errorf("resource %s: %w", name, err)
+	}
+	if d.resources == nil {
+		d.resources = make(map[int]any)
+	}
+	d.resources[i] = v
+	return v, nil
+}
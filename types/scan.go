@@ -316,6 +316,9 @@ func (s *Serializer) scan1(t reflect.Type, p unsafe.Pointer, seen map[reflect.Va
 		n := t.NumField()
 		for i := 0; i < n; i++ {
 			f := t.Field(i)
+			if transient(f.Tag) {
+				continue
+			}
 			ft := f.Type
 			fp := unsafe.Add(p, f.Offset)
 			s.scan1(ft, fp, seen)
@@ -356,6 +359,16 @@ func (s *Serializer) scan1(t reflect.Type, p unsafe.Pointer, seen map[reflect.Va
 
 			s.scan1(vt, vp, seen)
 		}
+	case reflect.Chan:
+		c := *(*unsafe.Pointer)(p)
+		if c == nil {
+			return
+		}
+		h := (*hchan)(c)
+		et := t.Elem()
+		for i := 0; i < h.len(); i++ {
+			s.scan1(et, h.elem(i, et.Size()), seen)
+		}
 	case reflect.Bool,
 		reflect.Int,
 		reflect.Int8,
@@ -375,7 +388,6 @@ func (s *Serializer) scan1(t reflect.Type, p unsafe.Pointer, seen map[reflect.Va
 		// nothing to do
 	default:
 		// TODO:
-		// Chan
 		// Func
 		// UnsafePointer
 	}
@@ -1,9 +1,10 @@
 package types
 
 // serde.go contains the reflection based serialization and deserialization
-// procedures. It does not do any type memoization, as eventually codegen should
-// be able to generate code for types. Almost nothing is optimized, as we are
-// iterating on how it works to get it right first.
+// procedures. It does not do any type memoization; the struct types of
+// compiled coroutines use procedures generated by coroc instead (see
+// codegen.go). Almost nothing is optimized, as we are iterating on how it
+// works to get it right first.
 
 import (
 	"encoding/binary"
@@ -39,7 +40,20 @@ func init() {
 //
 // The output of Serialize can be reconstructed back to a Go value using
 // [Deserialize].
-func Serialize(x any) ([]byte, error) {
+//
+// Serialize returns an error if a value in x cannot be serialized, for example
+// when a custom serializer fails.
+func Serialize(x any) (b []byte, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			if e, ok := e.(error); ok {
+				err = fmt.Errorf("cannot serialize state: %w", e)
+			} else {
+				err = fmt.Errorf("cannot serialize state: %v", e)
+			}
+		}
+	}()
+
 	s := newSerializer()
 	w := &x // w is *interface{}
 	wr := reflect.ValueOf(w)
@@ -66,32 +80,61 @@ func Serialize(x any) ([]byte, error) {
 }
 
 // Deserialize value from b. Return left over bytes.
-func Deserialize(b []byte) (x interface{}, err error) {
+//
+// By default, the state must have been serialized by the same build of the
+// program, see [CrossBuild] to deserialize states of other builds.
+//
+// The data of the state is validated as it is decoded: Deserialize returns an
+// error wrapping [ErrInvalidState], usually a [*DecodeError], if the state is
+// corrupted, and limits the memory allocated for its values (see
+// [MaxAllocation]). States of the same build refer to the types of the program
+// by their address in memory, which must be the address of a type known to the
+// program: the types indexed by the linker, and the types reachable from them
+// or registered by the program (see [RegisterType]).
+func Deserialize(b []byte, options ...DeserializeOption) (x interface{}, err error) {
+	opts := deserializeOptions{maxAllocation: defaultMaxAllocation}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
 	defer func() {
-		// FIXME: the deserialize*() functions panic on invalid input
 		if e := recover(); e != nil {
-			err = fmt.Errorf("cannot deserialize state: %v", e)
+			if e, ok := e.(error); ok {
+				err = fmt.Errorf("cannot deserialize state: %w", e)
+			} else {
+				err = fmt.Errorf("cannot deserialize state: %v", e)
+			}
 		}
 	}()
 
 	var state coroutinev1.State
 	if err := state.UnmarshalVT(b); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidState, err)
+	}
+	if err := checkState(&state); err != nil {
 		return nil, err
 	}
-	if state.Build.Id != buildInfo.Id {
+	if opts.crossBuild {
+		if state.Build.Os != buildInfo.Os || state.Build.Arch != buildInfo.Arch {
+			return nil, incompatible("state of %s/%s build, expect %s/%s", state.Build.Os, state.Build.Arch, buildInfo.Os, buildInfo.Arch)
+		}
+	} else if state.Build.Id != buildInfo.Id {
 		return nil, fmt.Errorf("%w: got %v, expect %v", ErrBuildIDMismatch, state.Build.Id, buildInfo.Id)
 	}
 
 	d := newDeserializer(state.Root.Data, state.Types, state.Functions, state.Regions, state.Strings)
+	d.types.crossBuild = opts.crossBuild
+	d.maxAllocation = opts.maxAllocation
 
 	px := &x
 	t := reflect.TypeOf(px).Elem()
 	p := unsafe.Pointer(px)
-	deserializeInterface(d, t, p)
-
-	if len(d.b) != 0 {
-		err = errors.New("trailing bytes")
-	}
+	d.decode(func() {
+		if rt := d.typeOf(int(state.Root.Type >> 1)); state.Root.Type&1 == 1 || rt.Kind() != reflect.Interface {
+			panic(d.errorf("root region of type %s is not an interface", rt))
+		}
+		deserializeInterface(d, t, p)
+	})
 	return
 }
 
@@ -100,14 +143,23 @@ type Deserializer struct {
 
 	// input
 	b []byte
+
+	// region and data being decoded, to report the position of errors
+	region int
+	data   []byte
 }
 
 type deserializerContext struct {
-	serdes  *serdemap
-	types   *typemap
-	funcs   *funcmap
-	regions []*coroutinev1.Region
-	ptrs    map[sID]unsafe.Pointer
+	serdes    *serdemap
+	types     *typemap
+	funcs     *funcmap
+	regions   []*coroutinev1.Region
+	ptrs      map[sID]unsafe.Pointer
+	resources map[int]any
+
+	// memory allocated for the values of the state, see MaxAllocation
+	allocated     int
+	maxAllocation int
 }
 
 func newDeserializer(b []byte, ctypes []*coroutinev1.Type, cfuncs []*coroutinev1.Function, regions []*coroutinev1.Region, cstrings []string) *Deserializer {
@@ -115,26 +167,43 @@ func newDeserializer(b []byte, ctypes []*coroutinev1.Type, cfuncs []*coroutinev1
 	types := newTypeMap(serdes, strings, ctypes)
 	return &Deserializer{
 		&deserializerContext{
-			serdes:  serdes,
-			types:   types,
-			funcs:   newFuncMap(types, strings, cfuncs),
-			regions: regions,
-			ptrs:    make(map[sID]unsafe.Pointer),
+			serdes:        serdes,
+			types:         types,
+			funcs:         newFuncMap(types, strings, cfuncs),
+			regions:       regions,
+			ptrs:          make(map[sID]unsafe.Pointer),
+			maxAllocation: defaultMaxAllocation,
 		},
 		b,
+		-1,
+		b,
 	}
 }
 
-func (d *Deserializer) fork(b []byte) *Deserializer {
+// fork returns a deserializer for the data of the region with the given
+// index.
+func (d *Deserializer) fork(region int, b []byte) *Deserializer {
 	return &Deserializer{
 		d.deserializerContext,
 		b,
+		region,
+		b,
+	}
+}
+
+// decode calls f to decode all the data of the deserializer, converting the
+// panics caused by invalid data into errors reporting their position.
+func (d *Deserializer) decode(f func()) {
+	defer d.catch()
+	f()
+	if len(d.b) != 0 {
+		panic(d.errorf("trailing bytes"))
 	}
 }
 
 func (d *Deserializer) store(i sID, p unsafe.Pointer) {
 	if d.ptrs[i] != nil {
-		panic(fmt.Errorf("trying to overwrite known ID %d with %p", i, p))
+		panic(d.errorf("trying to overwrite known ID %d with %p", i, p))
 	}
 	d.ptrs[i] = p
 }
@@ -180,6 +249,10 @@ type serializerContext struct {
 	ptrs       map[unsafe.Pointer]sID
 	regions    []*coroutinev1.Region
 	containers containers
+	resources  struct {
+		count   int
+		byValue map[any]int
+	}
 }
 
 func newSerializer() *Serializer {
@@ -221,6 +294,9 @@ func serializeVarint(s *Serializer, size int) {
 
 func deserializeVarint(d *Deserializer) int {
 	l, n := binary.Varint(d.b)
+	if n <= 0 {
+		panic(d.errorf("invalid varint"))
+	}
 	d.b = d.b[n:]
 	return int(l)
 }
@@ -249,10 +325,10 @@ func DeserializeTo[T any](d *Deserializer, x *T) {
 	actualType, length := deserializeType(d)
 	if length < 0 {
 		if t != actualType {
-			panic(fmt.Sprintf("cannot deserialize %s as %s", actualType, t))
+			panic(d.errorf("cannot deserialize %s as %s", actualType, t))
 		}
-	} else if t.Kind() != reflect.Array || t.Len() != length || t != actualType.Elem() {
-		panic(fmt.Sprintf("cannot deserialize [%d]%s as %s", length, actualType, t))
+	} else if t.Kind() != reflect.Array || t.Len() != length || t.Elem() != actualType {
+		panic(d.errorf("cannot deserialize [%d]%s as %s", length, actualType, t))
 	}
 	deserializeAny(d, t, p)
 }
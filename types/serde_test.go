@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"os"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 	"unsafe"
@@ -59,6 +61,397 @@ func testSerdeTime(t *testing.T, x time.Time) {
 	}
 }
 
+func TestSerdeSync(t *testing.T) {
+	type state struct {
+		sync.Mutex
+		RW    sync.RWMutex
+		Once  sync.Once
+		Group sync.WaitGroup
+		Map   sync.Map
+		N     int
+	}
+
+	t.Run("unused", func(t *testing.T) {
+		var calls int
+		x := &state{N: 42}
+		x.Once.Do(func() { calls++ })
+		x.Map.Store("a", 1)
+		x.Map.Store(2, &EasyStruct{A: 3})
+		x.Group.Add(1)
+		x.Group.Done()
+
+		b, err := Serialize(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := Deserialize(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertCanInspect(t, b)
+
+		y := out.(*state)
+		assertEqual(t, 42, y.N)
+
+		y.Lock()
+		y.Unlock()
+		y.RW.RLock()
+		y.RW.RUnlock()
+		y.Group.Wait()
+
+		y.Once.Do(func() { calls++ })
+		assertEqual(t, 1, calls)
+
+		v, _ := y.Map.Load("a")
+		assertEqual(t, 1, v)
+		v, _ = y.Map.Load(2)
+		assertEqual(t, &EasyStruct{A: 3}, v)
+	})
+
+	t.Run("once not done", func(t *testing.T) {
+		b, err := Serialize(&sync.Once{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := Deserialize(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var called bool
+		out.(*sync.Once).Do(func() { called = true })
+		assertEqual(t, true, called)
+	})
+
+	for _, test := range []struct {
+		name  string
+		setup func(*state)
+		err   string
+	}{
+		{"mutex locked", func(x *state) { x.Lock() }, "cannot serialize a locked mutex"},
+		{"rwmutex locked", func(x *state) { x.RW.Lock() }, "cannot serialize a locked mutex"},
+		{"rwmutex read locked", func(x *state) { x.RW.RLock() }, "cannot serialize a locked mutex"},
+		{"waitgroup in use", func(x *state) { x.Group.Add(1) }, "cannot serialize a wait group with a non-zero counter"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			x := &state{}
+			test.setup(x)
+			before := *(*[unsafe.Sizeof(state{})]byte)(unsafe.Pointer(x))
+			_, err := Serialize(x)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected error %q, got %v", test.err, err)
+			}
+			// The values are inspected without being acquired.
+			if after := *(*[unsafe.Sizeof(state{})]byte)(unsafe.Pointer(x)); after != before {
+				t.Error("serialization modified the sync values")
+			}
+		})
+	}
+
+	t.Run("unsupported layout", func(t *testing.T) {
+		x := &struct{ state uint32 }{}
+		_, err := syncField(x, reflect.Int32, []string{"state"}, []string{"mu", "state"})
+		if err == nil || !strings.Contains(err.Error(), "unsupported layout") {
+			t.Errorf("expected unsupported layout error, got %v", err)
+		}
+	})
+}
+
+type transientLogger struct{ prefix string }
+
+type transientState struct {
+	N     int
+	Cache map[string]int   `coroutine:"-"`
+	Log   *transientLogger `coroutine:"-"`
+	Next  *transientState
+}
+
+func TestTransientFields(t *testing.T) {
+	RegisterTransient(func(l **transientLogger) error {
+		*l = &transientLogger{prefix: "restored"}
+		return nil
+	})
+
+	x := &transientState{
+		N:     1,
+		Cache: map[string]int{"a": 1},
+		Log:   &transientLogger{prefix: "original"},
+		Next:  &transientState{N: 2, Cache: map[string]int{"b": 2}},
+	}
+
+	b, err := Serialize(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Deserialize(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCanInspect(t, b)
+
+	for i, y := 0, out.(*transientState); y != nil; i, y = i+1, y.Next {
+		assertEqual(t, i+1, y.N)
+		if y.Cache != nil {
+			t.Errorf("transient field was serialized: %v", y.Cache)
+		}
+		if y.Log == nil || y.Log.prefix != "restored" {
+			t.Errorf("transient field was not re-initialized: %v", y.Log)
+		}
+	}
+}
+
+type transientPointV1 struct {
+	X       int
+	Cache   []int
+	Scratch string `coroutine:"-"`
+}
+
+type transientPoint struct {
+	X       int
+	Cache   []int `coroutine:"-"`
+	Scratch string
+}
+
+func TestCrossBuildTransientFields(t *testing.T) {
+	RegisterType[transientPoint]()
+
+	b, err := Serialize(&transientPointV1{X: 1, Cache: []int{1, 2}, Scratch: "scratch"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var state coroutinev1.State
+	if err := state.UnmarshalVT(b); err != nil {
+		t.Fatal(err)
+	}
+	state.Build = &coroutinev1.Build{Id: "other", Os: buildInfo.Os, Arch: buildInfo.Arch}
+	for i, s := range state.Strings {
+		if s == "transientPointV1" {
+			state.Strings[i] = "transientPoint"
+		}
+	}
+
+	out, err := Deserialize(mustSerialize(&state), CrossBuild())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, &transientPoint{X: 1}, out)
+}
+
+type resourceConn interface {
+	Addr() string
+}
+
+type fakeConn struct {
+	addr   string
+	closed bool
+}
+
+func (c *fakeConn) Addr() string { return c.addr }
+
+func TestResource(t *testing.T) {
+	var reopened []string
+	RegisterResource("os.File",
+		func(f *os.File) (string, error) { return f.Name(), nil },
+		func(name string) (*os.File, error) {
+			reopened = append(reopened, name)
+			return os.Open(name)
+		})
+	RegisterResource("types.resourceConn",
+		func(c resourceConn) (string, error) {
+			if c.(*fakeConn).closed {
+				return "", errors.New("connection is closed")
+			}
+			return c.Addr(), nil
+		},
+		func(addr string) (resourceConn, error) {
+			reopened = append(reopened, addr)
+			return &fakeConn{addr: addr}, nil
+		})
+
+	f, err := os.Open("serde_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	type state struct {
+		File  *os.File
+		Same  *os.File
+		None  *os.File
+		Conn  any
+		Conns []resourceConn
+	}
+	c := &fakeConn{addr: "127.0.0.1:80"}
+
+	b, err := Serialize(&state{File: f, Same: f, Conn: c, Conns: []resourceConn{c}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Deserialize(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCanInspect(t, b)
+
+	x := out.(*state)
+	defer x.File.Close()
+
+	assertEqual(t, []string{"serde_test.go", "127.0.0.1:80"}, reopened)
+	if x.File == f || x.File.Name() != f.Name() {
+		t.Errorf("file was not reopened: %v", x.File)
+	}
+	if x.Same != x.File {
+		t.Error("resource referenced twice was reopened twice")
+	}
+	if x.None != nil {
+		t.Errorf("nil resource was reopened: %v", x.None)
+	}
+	if conn := x.Conn.(*fakeConn); conn == c || conn.addr != c.addr || x.Conns[0] != conn {
+		t.Errorf("unexpected connections: %v, %v", x.Conn, x.Conns)
+	}
+
+	t.Run("params error", func(t *testing.T) {
+		_, err := Serialize(&fakeConn{closed: true})
+		if err == nil || !strings.Contains(err.Error(), "connection is closed") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		r := resources["os.File"]
+		delete(resources, "os.File")
+		defer func() { resources["os.File"] = r }()
+
+		_, err := Deserialize(b)
+		if err == nil || !strings.Contains(err.Error(), `resource "os.File" is not registered`) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+// binaryPoint serializes X and Y, but not the cached norm.
+type binaryPoint struct {
+	X, Y int32
+	norm int
+}
+
+func (p binaryPoint) MarshalBinary() ([]byte, error) {
+	return binary.LittleEndian.AppendUint32(binary.LittleEndian.AppendUint32(nil, uint32(p.X)), uint32(p.Y)), nil
+}
+
+func (p *binaryPoint) UnmarshalBinary(b []byte) error {
+	if len(b) != 8 {
+		return fmt.Errorf("invalid point of %d bytes", len(b))
+	}
+	p.X = int32(binary.LittleEndian.Uint32(b))
+	p.Y = int32(binary.LittleEndian.Uint32(b[4:]))
+	return nil
+}
+
+type gobName struct {
+	name  string
+	upper string
+}
+
+func (n *gobName) GobEncode() ([]byte, error) { return []byte(n.name), nil }
+
+func (n *gobName) GobDecode(b []byte) error {
+	n.name = string(b)
+	n.upper = strings.ToUpper(n.name)
+	return nil
+}
+
+// durableRef also implements encoding.BinaryMarshaler, which is ignored in
+// favor of DurableMarshaler.
+type durableRef struct {
+	ref   *int
+	calls int
+}
+
+func (r *durableRef) MarshalDurable(s *Serializer) error {
+	SerializeT(s, r.ref)
+	return nil
+}
+
+func (r *durableRef) UnmarshalDurable(d *Deserializer) error {
+	DeserializeTo(d, &r.ref)
+	r.calls++
+	return nil
+}
+
+func (r *durableRef) MarshalBinary() ([]byte, error) { return nil, errors.New("not implemented") }
+
+func (r *durableRef) UnmarshalBinary([]byte) error { return errors.New("not implemented") }
+
+func TestMarshalers(t *testing.T) {
+	n := 42
+	type state struct {
+		Point *binaryPoint
+		Name  gobName
+		Ref   durableRef
+		N     *int
+	}
+	x := &state{
+		Point: &binaryPoint{X: 1, Y: -2, norm: 3},
+		Name:  gobName{name: "hello"},
+		Ref:   durableRef{ref: &n},
+		N:     &n,
+	}
+
+	b, err := Serialize(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Deserialize(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCanInspect(t, b)
+
+	y := out.(*state)
+	assertEqual(t, &binaryPoint{X: 1, Y: -2}, y.Point)
+	assertEqual(t, gobName{name: "hello", upper: "HELLO"}, y.Name)
+	assertEqual(t, 1, y.Ref.calls)
+	if y.Ref.ref != y.N || *y.N != 42 {
+		t.Errorf("pointer serialized by marshaler is not shared: %p != %p", y.Ref.ref, y.N)
+	}
+
+	t.Run("register takes precedence", func(t *testing.T) {
+		// registeredPoint implements encoding.BinaryMarshaler with the
+		// methods promoted from binaryPoint.
+		type registeredPoint struct{ binaryPoint }
+		Register(
+			func(s *Serializer, p *registeredPoint) error {
+				SerializeT(s, p.X)
+				return nil
+			},
+			func(d *Deserializer, p *registeredPoint) error {
+				DeserializeTo(d, &p.X)
+				p.norm = -1
+				return nil
+			})
+		assertRoundTrip(t, registeredPoint{binaryPoint{X: 1, norm: -1}})
+	})
+
+	t.Run("register after lookup", func(t *testing.T) {
+		// The marshaler found for the type when serializing it first must
+		// not hide the functions registered afterwards.
+		type laterPoint struct{ binaryPoint }
+		assertRoundTrip(t, laterPoint{binaryPoint{X: 1, Y: 2}})
+		Register(
+			func(s *Serializer, p *laterPoint) error {
+				SerializeT(s, p.X)
+				return nil
+			},
+			func(d *Deserializer, p *laterPoint) error {
+				DeserializeTo(d, &p.X)
+				p.norm = -1
+				return nil
+			})
+		assertRoundTrip(t, laterPoint{binaryPoint{X: 1, norm: -1}})
+	})
+}
+
 func assertCanInspect(t *testing.T, b []byte) {
 	c, err := Inspect(b)
 	if err != nil {
@@ -318,6 +711,86 @@ func TestReflectClosure(t *testing.T) {
 	})
 }
 
+func TestReflectChan(t *testing.T) {
+	roundTrip := func(t *testing.T, v any) any {
+		t.Helper()
+		b, err := Serialize(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := Deserialize(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertCanInspect(t, b)
+		return out
+	}
+
+	t.Run("buffered", func(t *testing.T) {
+		ch := make(chan *EasyStruct, 4)
+		// Move the receive index so the buffered elements wrap around
+		// the circular queue of the channel.
+		for i := 0; i < 3; i++ {
+			ch <- nil
+			<-ch
+		}
+		for i := 0; i < 3; i++ {
+			ch <- &EasyStruct{A: i}
+		}
+
+		out := roundTrip(t, ch).(chan *EasyStruct)
+		assertEqual(t, 4, cap(out))
+		assertEqual(t, 3, len(out))
+		assertEqual(t, 3, len(ch)) // serialization does not receive
+		for i := 0; i < 3; i++ {
+			assertEqual(t, i, (<-out).A)
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		ch := make(chan string, 2)
+		ch <- "hello"
+		close(ch)
+
+		out := roundTrip(t, ch).(chan string)
+		v, ok := <-out
+		assertEqual(t, "hello", v)
+		assertEqual(t, true, ok)
+		_, ok = <-out
+		assertEqual(t, false, ok)
+	})
+
+	t.Run("unbuffered", func(t *testing.T) {
+		out := roundTrip(t, make(chan struct{})).(chan struct{})
+		assertEqual(t, 0, cap(out))
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var ch chan int
+		out := roundTrip(t, ch).(chan int)
+		if out != nil {
+			t.Errorf("expected nil channel, got %v", out)
+		}
+	})
+
+	t.Run("direction", func(t *testing.T) {
+		type X struct {
+			In  chan<- int
+			Out <-chan int
+		}
+		ch := make(chan int, 1)
+		ch <- 42
+
+		out := roundTrip(t, X{In: ch, Out: ch}).(X)
+		if reflect.ValueOf(out.In).Pointer() != reflect.ValueOf(out.Out).Pointer() {
+			t.Errorf("channel is not shared by both directions")
+		}
+		assertEqual(t, 42, <-out.Out)
+		out.In <- 21
+		assertEqual(t, 21, <-out.Out)
+	})
+}
+
 func TestErrors(t *testing.T) {
 	s := struct {
 		X5 error
@@ -538,6 +1011,33 @@ func TestReflectSharing(t *testing.T) {
 		assertEqual(t, 8, out.b[7])
 	})
 
+	testReflect(t, "channels", func(t *testing.T) {
+		ch := make(chan int, 2)
+		ch <- 1
+
+		type X struct {
+			a chan int
+			b *chan int
+			c any
+		}
+
+		b, err := Serialize(X{a: ch, b: &ch, c: ch})
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := Deserialize(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertCanInspect(t, b)
+		out := v.(X)
+
+		// check channel is shared after
+		out.a <- 2
+		assertEqual(t, 2, len(*out.b))
+		assertEqual(t, 2, len(out.c.(chan int)))
+	})
+
 	testReflect(t, "slice backing array", func(t *testing.T) {
 		data := make([]int, 10)
 		for i := range data {
@@ -959,6 +1459,264 @@ func TestUnmarshalInvalid(t *testing.T) {
 	}
 }
 
+// decodeInt is an integer type that no custom serializer is registered for.
+type decodeInt int
+
+func TestDeserializeInvalid(t *testing.T) {
+	n := decodeInt(42)
+	b, err := Serialize([]*decodeInt{&n, &n, nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rewrite := func(f func(*coroutinev1.State)) []byte {
+		var state coroutinev1.State
+		if err := state.UnmarshalVT(b); err != nil {
+			t.Fatal(err)
+		}
+		f(&state)
+		return mustSerialize(&state)
+	}
+
+	// Find the regions holding the slice header, its backing array and
+	// the integer.
+	var header, array, integer int
+	rewrite(func(state *coroutinev1.State) {
+		for i, r := range state.Regions {
+			switch {
+			case r.Type&1 == 1:
+				array = i
+			case state.Types[r.Type>>1-1].Kind == coroutinev1.Kind_KIND_SLICE:
+				header = i
+			default:
+				integer = i
+			}
+		}
+	})
+
+	sliceData := func(l, c, region, offset int) func(*coroutinev1.State) {
+		return func(state *coroutinev1.State) {
+			var data []byte
+			for _, v := range []int{l, c, region + 1, offset} {
+				data = binary.AppendVarint(data, int64(v))
+			}
+			state.Regions[header].Data = data
+		}
+	}
+
+	for _, test := range []struct {
+		name    string
+		modify  func(*coroutinev1.State)
+		options []DeserializeOption
+		region  int
+		offset  int
+		err     string
+	}{
+		{
+			name: "truncated data",
+			modify: func(state *coroutinev1.State) {
+				state.Regions[integer].Data = state.Regions[integer].Data[:4]
+			},
+			region: integer,
+			offset: 0,
+			err:    "unexpected end of data",
+		},
+		{
+			name: "trailing bytes",
+			modify: func(state *coroutinev1.State) {
+				state.Regions[integer].Data = append(state.Regions[integer].Data, 0)
+			},
+			region: integer,
+			offset: 8,
+			err:    "trailing bytes",
+		},
+		{
+			name:   "length exceeds capacity",
+			modify: sliceData(4, 3, array, 0),
+			region: header,
+			offset: 2,
+			err:    "invalid slice length 4 and capacity 3",
+		},
+		{
+			name:   "region not found",
+			modify: sliceData(3, 3, 8, 0),
+			region: header,
+			offset: 4,
+			err:    "region 9 not found",
+		},
+		{
+			name:   "capacity out of bounds",
+			modify: sliceData(3, 4, array, 0),
+			region: header,
+			offset: 4,
+			err:    "invalid pointer",
+		},
+		{
+			name:   "offset out of bounds",
+			modify: sliceData(1, 1, array, 24),
+			region: header,
+			offset: 4,
+			err:    "invalid pointer",
+		},
+		{
+			name:   "pointer to memory of another type",
+			modify: sliceData(1, 1, integer, 0),
+			region: header,
+			offset: 4,
+			err:    fmt.Sprintf("invalid pointer to *types.decodeInt at offset 0 of region %d", integer),
+		},
+		{
+			name:    "allocation limit",
+			modify:  func(*coroutinev1.State) {},
+			options: []DeserializeOption{MaxAllocation(32)},
+			region:  header,
+			offset:  4,
+			err:     "exceeds the limit of 32 bytes",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Deserialize(rewrite(test.modify), test.options...)
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) || !errors.Is(err, ErrInvalidState) {
+				t.Fatalf("expected decode error, got %v", err)
+			}
+			if decodeErr.Region != test.region || decodeErr.Offset != test.offset {
+				t.Errorf("expected error at offset %d of region %d, got %v", test.offset, test.region, err)
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected error to contain %q, got %v", test.err, err)
+			}
+		})
+	}
+
+	for _, test := range []struct {
+		name   string
+		modify func(*coroutinev1.State)
+	}{
+		{
+			name: "region type not found",
+			modify: func(state *coroutinev1.State) {
+				state.Regions[header].Type = uint32(len(state.Types)+1) << 1
+			},
+		},
+		{
+			name: "recursive type",
+			modify: func(state *coroutinev1.State) {
+				id := state.Regions[header].Type >> 1 // []*decodeInt
+				state.Types[id-1].Elem = id
+			},
+		},
+		{
+			name: "missing root region",
+			modify: func(state *coroutinev1.State) {
+				state.Root = nil
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b := rewrite(test.modify)
+			if _, err := Deserialize(b); !errors.Is(err, ErrInvalidState) {
+				t.Errorf("expected invalid state, got %v", err)
+			}
+			if _, err := Inspect(b); !errors.Is(err, ErrInvalidState) {
+				t.Errorf("expected invalid state from Inspect, got %v", err)
+			}
+		})
+	}
+
+	t.Run("type offset not in the program", func(t *testing.T) {
+		b := rewrite(func(state *coroutinev1.State) {
+			for _, typ := range state.Types {
+				if typ.MemoryOffset != 0 {
+					typ.MemoryOffset += 1 << 40
+				}
+			}
+		})
+		if _, err := Deserialize(b); !errors.Is(err, ErrInvalidState) {
+			t.Errorf("expected invalid state, got %v", err)
+		}
+	})
+}
+
+// fuzzSeeds returns serialized states used as the seed corpus of the fuzz
+// tests.
+func fuzzSeeds(f *testing.F) [][]byte {
+	node := &codecNode{ID: 1}
+	node.Next = node
+	ch := make(chan int, 4)
+	ch <- 1
+	ch <- 2
+	close(ch)
+
+	var seeds [][]byte
+	for _, v := range []any{
+		42,
+		"hello",
+		[]int{1, 2, 3},
+		map[string]int{"a": 1, "b": 2},
+		&EasyStruct{A: 1, B: "b"},
+		[]any{1, "x", nil, 1.5, true},
+		node,
+		ch,
+		time.Unix(0, 0).UTC(),
+	} {
+		b, err := Serialize(v)
+		if err != nil {
+			f.Fatal(err)
+		}
+		seeds = append(seeds, b)
+	}
+	return seeds
+}
+
+func FuzzDeserialize(f *testing.F) {
+	for _, b := range fuzzSeeds(f) {
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		// States are decoded with types resolved by their offset in the
+		// program, and by name in cross-build mode.
+		for _, options := range [][]DeserializeOption{
+			{MaxAllocation(1 << 20)},
+			{CrossBuild(), MaxAllocation(1 << 20)},
+		} {
+			v, err := Deserialize(b, options...)
+			if err != nil {
+				continue
+			}
+			// The values must be usable: serializing them walks the
+			// whole graph of values.
+			_, _ = Serialize(v)
+		}
+	})
+}
+
+func FuzzInspect(f *testing.F) {
+	for _, b := range fuzzSeeds(f) {
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		state, err := Inspect(b)
+		if err != nil {
+			return
+		}
+		regions := []*Region{state.Root()}
+		for i := 0; i < state.NumRegion(); i++ {
+			regions = append(regions, state.Region(i))
+		}
+		for _, region := range regions {
+			_ = region.String()
+			s := region.Scan()
+			for i := 0; i < 1000 && s.Next(); i++ {
+				_ = fmt.Sprintf("%+v", s.Type())
+			}
+			if err := s.Close(); err != nil && !errors.Is(err, ErrInvalidState) {
+				t.Errorf("unexpected scan error: %v", err)
+			}
+		}
+	})
+}
+
 func mustSerialize(s *coroutinev1.State) []byte {
 	b, err := s.MarshalVT()
 	if err != nil {
@@ -966,3 +1724,238 @@ func mustSerialize(s *coroutinev1.State) []byte {
 	}
 	return b
 }
+
+type codecNode struct {
+	ID       int
+	Name     string
+	Tags     []string
+	Next     *codecNode
+	Children map[string]*codecNode
+}
+
+func TestCodec(t *testing.T) {
+	leaf := &codecNode{ID: 2, Name: "leaf"}
+	root := &codecNode{
+		ID:       1,
+		Name:     "root",
+		Tags:     []string{"a", "b"},
+		Next:     leaf,
+		Children: map[string]*codecNode{"leaf": leaf},
+	}
+	leaf.Next = root
+
+	reflected, err := Serialize(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serialized, deserialized := 0, 0
+	RegisterCodec(func(s *Serializer, x *codecNode) {
+		serialized++
+		SerializeField(s, &x.ID)
+		SerializeField(s, &x.Name)
+		SerializeField(s, &x.Tags)
+		SerializeField(s, &x.Next)
+		SerializeField(s, &x.Children)
+	}, func(d *Deserializer, x *codecNode) {
+		deserialized++
+		DeserializeField(d, &x.ID)
+		DeserializeField(d, &x.Name)
+		DeserializeField(d, &x.Tags)
+		DeserializeField(d, &x.Next)
+		DeserializeField(d, &x.Children)
+	})
+	defer delete(codecs, reflect.TypeFor[codecNode]())
+
+	generated, err := Serialize(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(reflected, generated) {
+		t.Error("generated codec does not produce the same output as reflection")
+	}
+
+	out, err := Deserialize(generated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serialized != 2 || deserialized != 2 {
+		t.Errorf("codec was not used: %d serializations, %d deserializations", serialized, deserialized)
+	}
+
+	x := out.(*codecNode)
+	if x.ID != 1 || x.Name != "root" || !reflect.DeepEqual(x.Tags, root.Tags) {
+		t.Errorf("unexpected root: %+v", x)
+	}
+	if x.Next.Next != x || x.Children["leaf"] != x.Next {
+		t.Error("pointers were not preserved")
+	}
+}
+
+type crossBuildPoint struct {
+	X, Y  int
+	Label string
+	next  *crossBuildPoint
+}
+
+func TestCrossBuild(t *testing.T) {
+	RegisterType[crossBuildPoint]()
+	RegisterFunc[func(int) int]("github.com/dispatchrun/coroutine/types.identity")
+
+	p := &crossBuildPoint{X: 1, Y: 2, Label: "a"}
+	p.next = p
+
+	b, err := Serialize([]any{p, identity, errors.New("oops")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rewrite := func(f func(*coroutinev1.State)) []byte {
+		var state coroutinev1.State
+		if err := state.UnmarshalVT(b); err != nil {
+			t.Fatal(err)
+		}
+		state.Build = &coroutinev1.Build{
+			Id:   "other",
+			Os:   buildInfo.Os,
+			Arch: buildInfo.Arch,
+		}
+		f(&state)
+		return mustSerialize(&state)
+	}
+	renameString := func(from, to string) func(*coroutinev1.State) {
+		return func(state *coroutinev1.State) {
+			for i, s := range state.Strings {
+				if s == from {
+					state.Strings[i] = to
+				}
+			}
+		}
+	}
+
+	other := rewrite(func(*coroutinev1.State) {})
+	if _, err := Deserialize(other); !errors.Is(err, ErrBuildIDMismatch) {
+		t.Fatalf("expected build ID mismatch, got %v", err)
+	}
+
+	out, err := Deserialize(other, CrossBuild())
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := out.([]any)
+	q := values[0].(*crossBuildPoint)
+	if q.X != 1 || q.Y != 2 || q.Label != "a" || q.next != q {
+		t.Errorf("unexpected point: %+v", q)
+	}
+	if fn := values[1].(func(int) int); fn(42) != 42 {
+		t.Error("unexpected function")
+	}
+	if err := values[2].(error); err.Error() != "oops" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, test := range []struct {
+		name   string
+		modify func(*coroutinev1.State)
+		err    string
+	}{
+		{
+			name:   "type not found",
+			modify: renameString("crossBuildPoint", "crossBuildPoint2"),
+		},
+		{
+			name:   "function not found",
+			modify: renameString("github.com/dispatchrun/coroutine/types.identity", "github.com/dispatchrun/coroutine/types.identity2"),
+		},
+		{
+			name: "field type changed",
+			modify: func(state *coroutinev1.State) {
+				for _, typ := range state.Types {
+					for _, f := range typ.Fields {
+						if state.Strings[f.Name-1] == "Label" {
+							f.Type = typ.Fields[0].Type
+						}
+					}
+				}
+			},
+			err: "field Label: type int is now string",
+		},
+		{
+			name: "other architecture",
+			modify: func(state *coroutinev1.State) {
+				state.Build.Arch += "x"
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Deserialize(rewrite(test.modify), CrossBuild())
+			if !errors.Is(err, ErrIncompatibleState) {
+				t.Errorf("expected incompatible state, got %v", err)
+			} else if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected error to contain %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+type evolvedPointV1 struct {
+	Y       int
+	Removed []string
+	X       int
+	next    *evolvedPointV1
+}
+
+type evolvedPoint struct {
+	X     int
+	Y     int
+	Added string
+	next  *evolvedPoint
+}
+
+func TestCrossBuildStructEvolution(t *testing.T) {
+	RegisterType[evolvedPoint]()
+
+	p := &evolvedPointV1{X: 1, Y: 2, Removed: []string{"a", "b"}}
+	p.next = p
+	s := []evolvedPointV1{{X: 3, Y: 4}, {X: 5, Y: 6, Removed: []string{"c"}}}
+
+	b, err := Serialize([]any{p, &p.X, s, &s[1].X, s[1:]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var state coroutinev1.State
+	if err := state.UnmarshalVT(b); err != nil {
+		t.Fatal(err)
+	}
+	state.Build = &coroutinev1.Build{Id: "other", Os: buildInfo.Os, Arch: buildInfo.Arch}
+	for i, s := range state.Strings {
+		if s == "evolvedPointV1" {
+			state.Strings[i] = "evolvedPoint"
+		}
+	}
+
+	out, err := Deserialize(mustSerialize(&state), CrossBuild())
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := out.([]any)
+
+	q := values[0].(*evolvedPoint)
+	if q.X != 1 || q.Y != 2 || q.Added != "" || q.next != q {
+		t.Errorf("unexpected point: %+v", q)
+	}
+	if x := values[1].(*int); x != &q.X {
+		t.Errorf("pointer to field was not translated: %p != %p", x, &q.X)
+	}
+	r := values[2].([]evolvedPoint)
+	if len(r) != 2 || r[0].X != 3 || r[0].Y != 4 || r[1].X != 5 || r[1].Y != 6 {
+		t.Errorf("unexpected points: %+v", r)
+	}
+	if x := values[3].(*int); x != &r[1].X {
+		t.Errorf("pointer to array element field was not translated: %p != %p", x, &r[1].X)
+	}
+	if tail := values[4].([]evolvedPoint); &tail[0] != &r[1] {
+		t.Error("pointer to array element was not translated")
+	}
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// Struct fields tagged with `coroutine:"-"` are transient: their values are not
+// serialized, and they are left to their zero value when the struct is
+// deserialized, unless a function was registered with RegisterTransient to
+// re-initialize the fields of their type.
+
+// Global register of the functions re-initializing transient fields, by type
+// of the field.
+var transients = map[reflect.Type]func(unsafe.Pointer) error{}
+
+// RegisterTransient registers a function which re-initializes the transient
+// fields of type T when the structs holding them are deserialized.
+//
+// Transient fields are struct fields tagged with `coroutine:"-"`, for example
+// caches, loggers or metrics handles, which should not be part of the state of
+// coroutines. Without a registered function, transient fields are left to
+// their zero value on deserialization.
+func RegisterTransient[T any](reinit func(*T) error) {
+	transients[reflect.TypeFor[T]()] = func(p unsafe.Pointer) error {
+		return reinit((*T)(p))
+	}
+}
+
+// transient is true if the struct field is tagged with `coroutine:"-"`.
+func transient(tag reflect.StructTag) bool {
+	return tag.Get("coroutine") == "-"
+}
+
+// transientFields returns the transient fields of struct type t.
+func transientFields(t reflect.Type) (fields []reflect.StructField) {
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); transient(f.Tag) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// reinitTransient re-initializes the transient field f of the struct at p,
+// leaving it to its zero value if no function was registered for its type.
+func reinitTransient(f reflect.StructField, p unsafe.Pointer) {
+	reinit, ok := transients[f.Type]
+	if !ok {
+		return
+	}
+	if err := reinit(unsafe.Add(p, f.Offset)); err != nil {
+		panic(fmt.Errorf("re-initializing transient field %s: %w", f.Name, err))
+	}
+}
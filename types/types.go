@@ -16,6 +16,11 @@ type typemap struct {
 
 	types []*coroutinev1.Type
 	cache doublemap[typeid, reflect.Type]
+
+	// Resolve types symbolically, see CrossBuild.
+	crossBuild bool
+	// Decoding plans of the struct types that evolved, see matchStruct.
+	plans map[reflect.Type]*structPlan
 }
 
 func newTypeMap(serdes *serdemap, strings *stringmap, types []*coroutinev1.Type) *typemap {
@@ -49,9 +54,15 @@ func (m *typemap) ToReflect(id typeid) reflect.Type {
 		panic(fmt.Sprintf("type %d not found", id))
 	}
 
+	if m.crossBuild && (t.CustomSerializer > 0 || t.MemoryOffset != 0) {
+		x := m.resolve(id, t)
+		m.cache.add(id, x)
+		return x
+	}
+
 	if t.CustomSerializer > 0 {
 		if t.MemoryOffset != 0 {
-			et := typeForOffset(namedTypeOffset(t.MemoryOffset))
+			et := programTypeForOffset(namedTypeOffset(t.MemoryOffset))
 			if t.Kind == coroutinev1.Kind_KIND_POINTER {
 				et = reflect.PointerTo(et)
 			}
@@ -62,7 +73,7 @@ func (m *typemap) ToReflect(id typeid) reflect.Type {
 	}
 
 	if t.MemoryOffset != 0 {
-		return typeForOffset(namedTypeOffset(t.MemoryOffset))
+		return programTypeForOffset(namedTypeOffset(t.MemoryOffset))
 	}
 
 	var x reflect.Type
@@ -272,6 +283,19 @@ func (m *typemap) ToType(t reflect.Type) typeid {
 		}
 		ti.Variadic = t.IsVariadic()
 
+	case reflect.Interface:
+		// Methods are recorded so that unnamed interfaces can be resolved
+		// in other builds of the program.
+		ti.Fields = make([]*coroutinev1.Field, t.NumMethod())
+		for i := range ti.Fields {
+			method := t.Method(i)
+			ti.Fields[i] = &coroutinev1.Field{
+				Name:    m.strings.Intern(method.Name),
+				Package: m.strings.Intern(method.PkgPath),
+				Type:    m.ToType(method.Type),
+			}
+		}
+
 	case reflect.Chan:
 		ti.Elem = m.ToType(t.Elem())
 		switch t.ChanDir() {
@@ -387,9 +411,18 @@ func (m *funcmap) ToFunc(id funcid) *Func {
 		panic(fmt.Sprintf("function ID %d not found", id))
 	}
 	name := m.strings.Lookup(cf.Name)
-	f := FuncByName(name)
+	f := funcByID(name)
 	if f == nil {
-		panic(fmt.Sprintf("function %s not found", name))
+		if m.types.crossBuild {
+			panic(incompatible("function %s not found", name))
+		}
+		panic(invalid("function %s not found", name))
+	}
+	if m.types.crossBuild {
+		if err := m.resolveFunc(cf, f); err != nil {
+			panic(incompatible("%v", err))
+		}
+		m.cache.add(id, f)
 	}
 	return f
 }
@@ -409,7 +442,7 @@ func (m *funcmap) RegisterAddr(addr unsafe.Pointer) (id funcid, closureType refl
 	}
 
 	id = m.register(&coroutinev1.Function{
-		Name:    m.strings.Intern(f.Name),
+		Name:    m.strings.Intern(f.id()),
 		Type:    m.types.ToType(f.Type),
 		Closure: closureTypeID,
 	})
@@ -432,6 +465,11 @@ func (m *doublemap[K, V]) getV(v V) (K, bool) {
 	return k, ok
 }
 
+func (m *doublemap[K, V]) remove(k K, v V) {
+	delete(m.fromK, k)
+	delete(m.fromV, v)
+}
+
 func (m *doublemap[K, V]) add(k K, v V) V {
 	if m.fromK == nil {
 		m.fromK = make(map[K]V)
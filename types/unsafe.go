@@ -34,6 +34,8 @@ func inlined(t reflect.Type) bool {
 		return true
 	case reflect.Map:
 		return true
+	case reflect.Chan:
+		return true
 	case reflect.Struct:
 		return t.NumField() == 1 && inlined(t.Field(0).Type)
 	case reflect.Array:
@@ -51,6 +53,10 @@ func init() {
 	staticuint64s = (*iface)(unsafe.Pointer(&x)).ptr
 }
 
+// staticType is the type of the memory that pointers into the static uint64
+// table may point to.
+var staticType = reflect.TypeFor[[256]byte]()
+
 func static(p unsafe.Pointer) bool {
 	return uintptr(p) >= uintptr(staticuint64s) && uintptr(p) < uintptr(staticuint64s)+256
 }
@@ -75,10 +81,28 @@ func offsetForType(t reflect.Type) namedTypeOffset {
 }
 
 func typeForOffset(offset namedTypeOffset) reflect.Type {
+	bptr := (*iface)(unsafe.Pointer(&byteT)).ptr
+	return typeForPointer(unsafe.Add(bptr, offset))
+}
+
+// programTypeForOffset is like typeForOffset but panics with an error wrapping
+// ErrInvalidState if the offset does not refer to a type of the program, since
+// the type descriptor would be read from an arbitrary address. Offsets are
+// read from states, which may be corrupted.
+func programTypeForOffset(offset namedTypeOffset) reflect.Type {
+	t := typeForOffset(offset)
+	if !registry.contains(t) {
+		panic(invalid("no type of the program at offset %#x", uint64(offset)))
+	}
+	return t
+}
+
+// typeForPointer returns the reflect.Type of a type descriptor.
+func typeForPointer(p unsafe.Pointer) reflect.Type {
 	biface := (*iface)(unsafe.Pointer(&byteT))
 	tiface := &iface{
 		typ: biface.typ,
-		ptr: unsafe.Add(biface.ptr, offset),
+		ptr: p,
 	}
 	return *(*reflect.Type)(unsafe.Pointer(tiface))
 }